@@ -3,14 +3,17 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	_ "github.com/kosttiik/semesterly_backend/docs" // Swagger documentation
+	"github.com/kosttiik/semesterly_backend/internal/grpcserver"
 	"github.com/kosttiik/semesterly_backend/internal/pkg/app"
 	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
 )
 
 // @title Автоматизированная система по ведению расписания учебных занятий
@@ -18,6 +21,10 @@ import (
 // @description API для управления расписанием учебных занятий
 // @host localhost:8080
 // @BasePath /api/v1
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Токен администратора в формате "Bearer <ADMIN_TOKEN>"
 func main() {
 	log.Println("Application started!")
 
@@ -45,11 +52,30 @@ func main() {
 		}
 	}()
 
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+
+	// gRPC-сервер работает рядом с Echo и использует те же обработчики
+	grpcServer := grpcserver.New(a.Handlers, os.Getenv("ADMIN_TOKEN"))
+	go func() {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		log.Printf("gRPC server started on :%s", grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			errChan <- err
+		}
+	}()
+
 	// Обработка завершения приложения
-	handleShutdown(e, errChan)
+	handleShutdown(e, grpcServer, errChan)
 }
 
-func handleShutdown(e *echo.Echo, errChan chan error) {
+func handleShutdown(e *echo.Echo, grpcServer *grpc.Server, errChan chan error) {
 	// Ловим сигналы завершения
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -60,6 +86,17 @@ func handleShutdown(e *echo.Echo, errChan chan error) {
 		log.Fatalf("Shutting down the server due to error: %v", err)
 	case <-quit:
 		log.Println("Shutting down server...")
+		// Потоки прогресса могут быть открыты долго, поэтому ждем их завершения ограниченное время
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			grpcServer.Stop()
+		}
 		if err := e.Shutdown(context.Background()); err != nil {
 			log.Fatalf("Error shutting down server: %v", err)
 		}
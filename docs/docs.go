@@ -15,31 +15,32 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/get-data": {
+        "/admin/day-transfers": {
             "get": {
-                "description": "Возвращает данные расписания из базы данных в формате JSON",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "GetData"
+                    "AdminCalendar"
                 ],
-                "summary": "Получение расписания",
+                "summary": "Список переносов дней",
                 "responses": {
                     "200": {
-                        "description": "Список элементов расписания",
+                        "description": "Список переносов",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.ScheduleItem"
+                                "$ref": "#/definitions/models.DayTransfer"
                             }
                         }
                     },
                     "500": {
-                        "description": "error: Failed to fetch schedule items",
+                        "description": "error: Failed to fetch entries",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -48,11 +49,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/get-group-schedule/{uuid}": {
-            "get": {
-                "description": "Возвращает данные расписания конкретной группы из базы данных в формате JSON",
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Например, суббота, в которую занятия проходят по расписанию среды: date - дата субботы, weekday = 3",
                 "consumes": [
                     "application/json"
                 ],
@@ -60,30 +64,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "GetData"
+                    "AdminCalendar"
                 ],
-                "summary": "Получение расписания группы",
+                "summary": "Добавление переноса дня",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "UUID группы",
-                        "name": "uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "Перенос дня",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DayTransfer"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Список элементов расписания",
+                    "201": {
+                        "description": "Созданный перенос",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ScheduleItem"
+                            "$ref": "#/definitions/models.DayTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Date is already taken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
                     "500": {
-                        "description": "error: Failed to fetch schedule items",
+                        "description": "error: Failed to save entry",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -94,9 +115,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/get-groups": {
-            "get": {
-                "description": "Возвращает данные всех групп из базы данных в формате JSON",
+        "/admin/day-transfers/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -104,21 +129,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "GetGroups"
+                    "AdminCalendar"
+                ],
+                "summary": "Изменение переноса дня",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID переноса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Перенос дня",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DayTransfer"
+                        }
+                    }
                 ],
-                "summary": "Получение списка групп",
                 "responses": {
                     "200": {
-                        "description": "Список групп",
+                        "description": "Измененный перенос",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Group"
+                            "$ref": "#/definitions/models.DayTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Date is already taken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
                     "500": {
-                        "description": "error: Failed to fetch groups",
+                        "description": "error: Failed to save entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Удаление переноса дня",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID переноса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Перенос удален"
+                    },
+                    "400": {
+                        "description": "error: Invalid id",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to delete entry",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -129,32 +248,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/hello": {
+        "/admin/exam-sessions": {
             "get": {
-                "description": "Проверяет, работает ли сервер и есть ли подключение к базе данных",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Hello"
+                    "AdminCalendar"
                 ],
-                "summary": "Проверка подключения",
+                "summary": "Список экзаменационных сессий",
                 "responses": {
                     "200": {
-                        "description": "Hello, World!",
+                        "description": "Список сессий",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExamSession"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch entries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/insert-data": {
+            },
             "post": {
-                "description": "Вставляет данные расписания и экзаменов в базу данных",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "В период сессии обычные занятия не проводятся",
                 "consumes": [
                     "application/json"
                 ],
@@ -162,12 +297,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "InsertData"
+                    "AdminCalendar"
+                ],
+                "summary": "Добавление экзаменационной сессии",
+                "parameters": [
+                    {
+                        "description": "Экзаменационная сессия",
+                        "name": "session",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ExamSession"
+                        }
+                    }
                 ],
-                "summary": "Вставка данных",
                 "responses": {
-                    "200": {
-                        "description": "message: Data inserted successfully",
+                    "201": {
+                        "description": "Созданная сессия",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExamSession"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -176,18 +328,24 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "errors: [error messages]",
+                        "description": "error: Failed to save entry",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/insert-group-schedule/{uuid}": {
-            "post": {
-                "description": "Вставляет данные расписания и экзаменов для конкретной группы в базу данных",
+        "/admin/exam-sessions/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -195,21 +353,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "InsertGroupSchedule"
+                    "AdminCalendar"
                 ],
-                "summary": "Вставка расписания группы",
+                "summary": "Изменение экзаменационной сессии",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "UUID группы",
-                        "name": "uuid",
+                        "type": "integer",
+                        "description": "ID сессии",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Экзаменационная сессия",
+                        "name": "session",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ExamSession"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "message: Group schedule inserted successfully",
+                        "description": "Измененная сессия",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExamSession"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -218,31 +400,50 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "errors: [error messages]",
+                        "description": "error: Failed to save entry",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/write-schedule": {
-            "post": {
-                "description": "Сохраняет данные расписания в CSV файл",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
                 ],
                 "tags": [
-                    "WriteSchedule"
+                    "AdminCalendar"
+                ],
+                "summary": "Удаление экзаменационной сессии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сессии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Сохранение расписания",
                 "responses": {
-                    "200": {
-                        "description": "message: Schedule written to file successfully",
+                    "204": {
+                        "description": "Сессия удалена"
+                    },
+                    "400": {
+                        "description": "error: Invalid id",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -251,7 +452,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "error: Failed to fetch schedule items\" \"error: Failed to write schedule to file",
+                        "description": "error: Failed to delete entry",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -261,36 +462,4028 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Audience": {
-            "type": "object",
-            "properties": {
-                "building": {
-                    "type": "string"
-                },
-                "department_uid": {
-                    "description": "Может быть null",
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "uuid": {
-                    "type": "string"
-                }
-            }
         },
-        "models.Discipline": {
-            "type": "object",
-            "properties": {
-                "abbr": {
-                    "type": "string"
-                },
+        "/admin/export-jobs": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ExportJobs"
+                ],
+                "summary": "Список заданий выгрузки",
+                "responses": {
+                    "200": {
+                        "description": "Задания, начиная с новых",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ExportJob"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Для каждой группы, преподавателя и аудитории (или для групп узла структуры и встречающихся в их расписаниях\nпреподавателей и аудиторий) формирует файлы в выбранных форматах: json, html, csv, ics, xlsx, pdf.\nАрхив содержит папки groups, teachers, audiences и manifest.json. Прогресс рассылается через /ws сообщениями\nс type = exportProgress и jobId. Одновременно выполняется одно задание, архив хранится 24 часа",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ExportJobs"
+                ],
+                "summary": "Запуск выгрузки архива расписаний",
+                "parameters": [
+                    {
+                        "description": "Форматы, виды расписаний и узел структуры",
+                        "name": "job",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Созданное задание",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "error: unknown schedule format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Structure node not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Export job is already running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to start export job",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/export-jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ExportJobs"
+                ],
+                "summary": "Состояние задания выгрузки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задание",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportJob"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Export job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "tags": [
+                    "ExportJobs"
+                ],
+                "summary": "Удаление задания выгрузки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Задание удалено"
+                    },
+                    "404": {
+                        "description": "error: Export job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Export job is already running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/export-jobs/{id}/archive": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "ExportJobs"
+                ],
+                "summary": "Скачивание архива выгрузки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Архив ZIP",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Export job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Export job is not finished",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/holidays": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Список праздничных дней",
+                "responses": {
+                    "200": {
+                        "description": "Список праздничных дней",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Holiday"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch entries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Добавление праздничного дня",
+                "parameters": [
+                    {
+                        "description": "Праздничный день",
+                        "name": "holiday",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Holiday"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданный праздничный день",
+                        "schema": {
+                            "$ref": "#/definitions/models.Holiday"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Date is already taken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/holidays/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Изменение праздничного дня",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID праздничного дня",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Праздничный день",
+                        "name": "holiday",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Holiday"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Измененный праздничный день",
+                        "schema": {
+                            "$ref": "#/definitions/models.Holiday"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Date is already taken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Удаление праздничного дня",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID праздничного дня",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Праздничный день удален"
+                    },
+                    "400": {
+                        "description": "error: Invalid id",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to delete entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/import": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Принимает CSV (столбцы прежней выгрузки writeToCSV или /export/schedule.csv), сетку XLSX в раскладке /export/schedule.xlsx\nили JSON в формате ответа lks. Занятия сохраняются тем же путем, что и данные lks, и помечаются источником upload:\u003csource\u003e.\nПовторная загрузка с тем же source заменяет занятия этого источника у групп из файла, синхронизация с lks их не затрагивает.\nЕсли хотя бы одна строка не прошла проверку, ничего не сохраняется и возвращаются ошибки строк.\nГруппы, преподаватели и аудитории без UUID сопоставляются с существующими по названию, новым назначается постоянный UUID.\nЭкзамен группы, которой нет ни в файле, ни в базе данных, считается ошибкой строки",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Загрузка расписания из файла",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл расписания",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: csv, xlsx или json, по умолчанию по расширению файла",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег источника, по умолчанию имя файла",
+                        "name": "source",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить файл, не сохраняя",
+                        "name": "dryRun",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Schedule imported successfully, source, items, exams, groups, conflicts: [conflicts.Conflict], slotMismatches: [repository.SlotMismatch]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid import file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error: Sync is already in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error: Invalid rows, rowErrors: [importer.RowError]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save schedule",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/semesters": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Список семестров",
+                "responses": {
+                    "200": {
+                        "description": "Список семестров",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Semester"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch entries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Первая неделя семестра считается числителем. Даты передаются в формате YYYY-MM-DD, семестры не должны пересекаться",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Добавление семестра",
+                "parameters": [
+                    {
+                        "description": "Семестр",
+                        "name": "semester",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Semester"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданный семестр",
+                        "schema": {
+                            "$ref": "#/definitions/models.Semester"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/semesters/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Изменение семестра",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID семестра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Семестр",
+                        "name": "semester",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Semester"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Измененный семестр",
+                        "schema": {
+                            "$ref": "#/definitions/models.Semester"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "tags": [
+                    "AdminCalendar"
+                ],
+                "summary": "Удаление семестра",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID семестра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Семестр удален"
+                    },
+                    "400": {
+                        "description": "error: Invalid id",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to delete entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audiences/free": {
+            "get": {
+                "description": "Возвращает аудитории, в которых нет занятий в указанный день и пару с учетом четности недели.\nВместо day и week можно передать date, тогда день недели и четность будут вычислены по дате.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audiences"
+                ],
+                "summary": "Поиск свободных аудиторий",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "День недели (1 - понедельник, 6 - суббота)",
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер пары",
+                        "name": "slot",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Четность недели: all, ch (числитель) или zn (знаменатель)",
+                        "name": "week",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Корпус",
+                        "name": "building",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список свободных аудиторий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Audience"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid query parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch audiences",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audiences/{uuid}/occurrences": {
+            "get": {
+                "description": "Раскрывает расписание аудитории в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.\nБез параметров from и to возвращаются занятия на ближайшие 7 дней.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Occurrences"
+                ],
+                "summary": "Занятия в аудитории по датам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID аудитории",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода в формате YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода в формате YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список занятий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/calendar.Occurrence"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audiences/{uuid}/schedule": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "text/csv",
+                    "text/calendar",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Расписание аудитории в выбранном формате",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID аудитории",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: json, html, csv, ics, xlsx или pdf",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочитаемые типы, например text/calendar или text/html",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Интервал автообновления страницы HTML в секундах",
+                        "name": "refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание в выбранном формате",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleItem"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных и формат"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Данные не изменились"
+                    },
+                    "400": {
+                        "description": "error: unknown schedule format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Audience not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "error: none of the accepted media types is supported",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audiences/{uuid}/schedule.html": {
+            "get": {
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Расписание аудитории в HTML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID аудитории",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD, неделя которой подсвечивается, по умолчанию сегодня",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Интервал автообновления страницы в секундах",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница HTML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Audience not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audiences/{uuid}/schedule.ics": {
+            "get": {
+                "description": "Возвращает файл .ics с повторяющимися занятиями в аудитории на все семестры и экзаменами в ней.\nЗанятия по числителю и знаменателю повторяются раз в две недели, праздники и сессии исключаются через EXDATE.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "ICS"
+                ],
+                "summary": "Расписание аудитории в формате iCalendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID аудитории",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Календарь iCalendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Audience not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audiences/{uuid}/schedule.pdf": {
+            "get": {
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Расписание аудитории в PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID аудитории",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Документ PDF",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Audience not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/cache/stats": {
+            "get": {
+                "description": "Возвращает размер кеша расписаний групп, преподавателей и аудиторий, количество попаданий и промахов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cache"
+                ],
+                "summary": "Статистика кеша",
+                "responses": {
+                    "200": {
+                        "description": "Статистика кеша",
+                        "schema": {
+                            "$ref": "#/definitions/cache.Stats"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/week": {
+            "get": {
+                "description": "Возвращает номер учебной недели и ее четность (ch - числитель, zn - знаменатель) для указанной даты.\nБез параметра date используется текущая дата.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Учебная неделя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о неделе",
+                        "schema": {
+                            "$ref": "#/definitions/calendar.WeekInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/compare": {
+            "get": {
+                "description": "Возвращает общие занятия двух групп, занятия только одной из групп и различия по парам.\nЗанятия совпадают, если совпадают время, четность, поток, дисциплины, преподаватели и аудитории. Группы задаются UUID или названием",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Compare"
+                ],
+                "summary": "Сравнение расписаний групп",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID или название первой группы",
+                        "name": "a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID или название второй группы",
+                        "name": "b",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат сравнения",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ScheduleComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Both a and b are required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/conflicts": {
+            "get": {
+                "description": "Возвращает преподавателей, ведущих два занятия одновременно, аудитории с двумя несвязанными занятиями\nи группы с пересекающимися занятиями. Учитывается четность недель, занятия одного потока конфликтом не считаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conflicts"
+                ],
+                "summary": "Конфликты в расписании",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип конфликта: teacher, audience или group",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список конфликтов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/conflicts.Conflict"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid conflict type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/export/schedule.csv": {
+            "get": {
+                "description": "Передает занятия потоково, не сохраняя файл на сервере. Без group, teacher, audience и timetable выгружается все расписание.\nФильтр week=ch или week=zn оставляет также занятия каждую неделю. Все дисциплины занятия перечисляются через точку с запятой.\nДоступные столбцы: type, day, time, week, date, startTime, endTime, stream, disciplineAbbr, disciplineActType, disciplineFullName, disciplineShortName, teachers, audiences, groups",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Экспорт расписания в CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID преподавателя",
+                        "name": "teacher",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID аудитории",
+                        "name": "audience",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID личного расписания",
+                        "name": "timetable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "День недели (1-6)",
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Неделя: all, ch или zn",
+                        "name": "week",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Добавить экзамены строками с type = exam",
+                        "name": "exams",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Столбцы через запятую, по умолчанию все",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Разделитель: comma (по умолчанию), semicolon, tab или |",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Кодировка: utf-8 (по умолчанию), utf-8-bom или cp1251",
+                        "name": "encoding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл CSV",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid export parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Timetable not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/export/schedule.pdf": {
+            "get": {
+                "description": "Формирует PDF формата A4 альбомной ориентации со встроенными кириллическими шрифтами в той же сетке, что и XLSX.\nКаждый лист (группа, кафедра или курс) начинается с новой страницы, поэтому node с UUID кафедры дает расписания всех ее групп одним файлом",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Экспорт расписания в PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID групп через запятую",
+                        "name": "groups",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID узла структуры, в который входят группы",
+                        "name": "node",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Разбивка на страницы: group (по умолчанию), department или course",
+                        "name": "sheet",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Документ PDF",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "error: sheet must be one of group, department, course",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: No groups found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/export/schedule.xlsx": {
+            "get": {
+                "description": "Формирует книгу Excel в классическом виде: дни сверху вниз, пары слева направо, числитель и знаменатель в отдельных строках.\nЗанятия каждую неделю и потоковые занятия соседних групп выводятся в объединенных ячейках.\nГруппы выбираются списком groups или узлом структуры node (факультет, кафедра, курс), по умолчанию - все группы",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Экспорт расписания в XLSX",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID групп через запятую",
+                        "name": "groups",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID узла структуры, в который входят группы",
+                        "name": "node",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Разбивка на листы: group (по умолчанию), department или course",
+                        "name": "sheet",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Книга Excel",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "error: sheet must be one of group, department, course",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: No groups found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds": {
+            "post": {
+                "description": "Возвращает секретную ссылку (в том числе webcal://), которую календарные приложения периодически опрашивают.\nИдентификаторы событий не меняются при повторной загрузке расписания из lks, поэтому приложения обновляют события, а не дублируют их",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ICS"
+                ],
+                "summary": "Создание подписки на календарь",
+                "parameters": [
+                    {
+                        "description": "Расписание для подписки",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CalendarFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданная подписка",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CalendarFeedCreated"
+                        }
+                    },
+                    "400": {
+                        "description": "error: kind must be one of group, teacher, audience, timetable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Schedule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save feed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{token}": {
+            "get": {
+                "description": "Возвращает актуальное расписание подписки в формате iCalendar",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "ICS"
+                ],
+                "summary": "Календарь подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен подписки, можно с расширением .ics",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Календарь iCalendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Feed not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to build calendar",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Токен подписки служит и доступом к ней, поэтому удалить подписку может любой, кто знает ссылку",
+                "tags": [
+                    "ICS"
+                ],
+                "summary": "Удаление подписки на календарь",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен подписки",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подписка удалена"
+                    },
+                    "404": {
+                        "description": "error: Feed not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to delete feed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/free-slots": {
+            "post": {
+                "description": "Возвращает пары (день, номер пары, четность недели), в которые ни у одной из групп и ни у одного из преподавателей нет занятий.\nПри указании диапазона дат окна возвращаются для конкретных дат, даты вне семестров пропускаются. Параметр rank=gaps сортирует окна по количеству создаваемых у студентов окон.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "FreeSlots"
+                ],
+                "summary": "Поиск общих свободных окон",
+                "parameters": [
+                    {
+                        "description": "Участники и диапазон дат",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FreeSlotsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список свободных окон",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FreeSlot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/get-data": {
+            "get": {
+                "description": "Возвращает данные расписания из базы данных в формате JSON",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GetData"
+                ],
+                "summary": "Получение расписания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата Last-Modified ранее полученного ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список элементов расписания",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleItem"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время последнего изменения данных"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Данные не изменились"
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/get-group-schedule/{uuid}": {
+            "get": {
+                "description": "Возвращает данные расписания конкретной группы из базы данных в формате JSON.\nПри layout=grid расписание возвращается сеткой дни × пары, где каждая ячейка содержит занятия по всем неделям, числителям и знаменателям",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GetData"
+                ],
+                "summary": "Получение расписания группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: flat (по умолчанию) или grid",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата Last-Modified ранее полученного ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список элементов расписания; при layout=grid - объект timetable.Grid",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleItem"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время последнего изменения данных"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Данные не изменились"
+                    },
+                    "400": {
+                        "description": "error: layout must be one of flat, grid",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/get-groups": {
+            "get": {
+                "description": "Возвращает данные всех групп из базы данных в формате JSON",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GetGroups"
+                ],
+                "summary": "Получение списка групп",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата Last-Modified ранее полученного ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список групп",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Group"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время последнего изменения данных"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Данные не изменились"
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch groups",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Выполняет GraphQL-запрос к графу расписания: группы, занятия, преподаватели, аудитории, дисциплины, экзамены и структура университета.\nВложенность запроса ограничена 8 уровнями, списки верхнего уровня - 500 записями, а все списки запроса вместе, включая вложенные, - 5000 записями. Запрос можно передать в теле POST или в параметрах GET.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "GraphQL API",
+                "parameters": [
+                    {
+                        "description": "GraphQL-запрос",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/gql.Request"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текст запроса (для GET)",
+                        "name": "query",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data и errors по спецификации GraphQL",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{uuid}/occurrences": {
+            "get": {
+                "description": "Раскрывает расписание группы в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.\nБез параметров from и to возвращаются занятия на ближайшие 7 дней.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Occurrences"
+                ],
+                "summary": "Занятия группы по датам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода в формате YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода в формате YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список занятий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/calendar.Occurrence"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{uuid}/schedule": {
+            "get": {
+                "description": "Один адрес для всех форматов расписания: json, html, csv, ics, xlsx и pdf.\nФормат задается параметром format, а без него выбирается по заголовку Accept с учетом q; без Accept отдается JSON.\nПараметры date и refresh относятся к странице HTML, layout - к JSON",
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "text/csv",
+                    "text/calendar",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Расписание группы в выбранном формате",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: json, html, csv, ics, xlsx или pdf",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочитаемые типы, например text/calendar или text/html",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Интервал автообновления страницы HTML в секундах",
+                        "name": "refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание в выбранном формате",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleItem"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных и формат"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Данные не изменились"
+                    },
+                    "400": {
+                        "description": "error: unknown schedule format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "error: none of the accepted media types is supported",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{uuid}/schedule.html": {
+            "get": {
+                "description": "Страница для киосков, ссылок в письмах и браузеров без приложения. Подходит для телефонов и печати.\nЗанятия текущей недели (числителя или знаменателя) подсвечиваются, занятия другой недели приглушаются",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Расписание группы в HTML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD, неделя которой подсвечивается, по умолчанию сегодня",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Интервал автообновления страницы в секундах",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница HTML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{uuid}/schedule.ics": {
+            "get": {
+                "description": "Возвращает файл .ics с повторяющимися занятиями группы на все семестры и экзаменами.\nЗанятия по числителю и знаменателю повторяются раз в две недели, праздники и сессии исключаются через EXDATE.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "ICS"
+                ],
+                "summary": "Расписание группы в формате iCalendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Календарь iCalendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{uuid}/schedule.pdf": {
+            "get": {
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Расписание группы в PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Документ PDF",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/hello": {
+            "get": {
+                "description": "Проверяет, работает ли сервер и есть ли подключение к базе данных",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hello"
+                ],
+                "summary": "Проверка подключения",
+                "responses": {
+                    "200": {
+                        "description": "Hello, World!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/insert-data": {
+            "post": {
+                "description": "Вставляет данные расписания и экзаменов в базу данных и проверяет расписание на конфликты",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "InsertData"
+                ],
+                "summary": "Вставка данных",
+                "responses": {
+                    "200": {
+                        "description": "message: Data inserted successfully, conflicts: [conflicts.Conflict], slotMismatches: [repository.SlotMismatch]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "error: Sync is already in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "errors: [error messages]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/insert-group-schedule/{uuid}": {
+            "post": {
+                "description": "Вставляет данные расписания и экзаменов для конкретной группы в базу данных и проверяет занятия группы на конфликты",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "InsertGroupSchedule"
+                ],
+                "summary": "Вставка расписания группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Group schedule inserted successfully, conflicts: [conflicts.Conflict], slotMismatches: [repository.SlotMismatch]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "errors: [error messages]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/now": {
+            "get": {
+                "description": "Возвращает занятие, идущее в момент at, следующее занятие и оставшееся время.\nНужно указать ровно один из параметров group, teacher или audience. Без at используется текущее время.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Occurrences"
+                ],
+                "summary": "Текущее и следующее занятие",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID группы",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID преподавателя",
+                        "name": "teacher",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID аудитории",
+                        "name": "audience",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Момент времени в формате RFC 3339",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текущее и следующее занятие",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid query parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/slots": {
+            "get": {
+                "description": "Возвращает пары с временем начала и окончания. Пары выводятся из занятий при загрузке данных, занятия ссылаются на них через slotId",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Slots"
+                ],
+                "summary": "Расписание звонков",
+                "responses": {
+                    "200": {
+                        "description": "Список пар",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimeSlot"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch time slots",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{uuid}/occurrences": {
+            "get": {
+                "description": "Раскрывает расписание преподавателя в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.\nБез параметров from и to возвращаются занятия на ближайшие 7 дней.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Occurrences"
+                ],
+                "summary": "Занятия преподавателя по датам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID преподавателя",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода в формате YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода в формате YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список занятий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/calendar.Occurrence"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{uuid}/schedule": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "text/csv",
+                    "text/calendar",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Расписание преподавателя в выбранном формате",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID преподавателя",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: json, html, csv, ics, xlsx или pdf",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочитаемые типы, например text/calendar или text/html",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Интервал автообновления страницы HTML в секундах",
+                        "name": "refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание в выбранном формате",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleItem"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных и формат"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Данные не изменились"
+                    },
+                    "400": {
+                        "description": "error: unknown schedule format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Teacher not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "error: none of the accepted media types is supported",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{uuid}/schedule.html": {
+            "get": {
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Расписание преподавателя в HTML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID преподавателя",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD, неделя которой подсвечивается, по умолчанию сегодня",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Интервал автообновления страницы в секундах",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница HTML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Teacher not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{uuid}/schedule.ics": {
+            "get": {
+                "description": "Возвращает файл .ics с повторяющимися занятиями преподавателя на все семестры и принимаемыми экзаменами.\nЗанятия по числителю и знаменателю повторяются раз в две недели, праздники и сессии исключаются через EXDATE.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "ICS"
+                ],
+                "summary": "Расписание преподавателя в формате iCalendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID преподавателя",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Календарь iCalendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Teacher not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{uuid}/schedule.pdf": {
+            "get": {
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Расписание преподавателя в PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID преподавателя",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Документ PDF",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Teacher not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timetables": {
+            "post": {
+                "description": "Сохраняет набор групп с фильтрами по дисциплинам и типам занятий под публичным ID.\nТокен editToken из ответа нужен для изменения и удаления расписания, он возвращается только один раз.\nНазвание - не длиннее 200 символов, групп - не больше 20",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PersonalTimetables"
+                ],
+                "summary": "Создание личного расписания",
+                "parameters": [
+                    {
+                        "description": "Личное расписание",
+                        "name": "timetable",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PersonalTimetableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданное расписание",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PersonalTimetableCreated"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save timetable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timetables/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PersonalTimetables"
+                ],
+                "summary": "Получение личного расписания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID личного расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Личное расписание",
+                        "schema": {
+                            "$ref": "#/definitions/models.PersonalTimetable"
+                        }
+                    },
+                    "404": {
+                        "description": "error: Timetable not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PersonalTimetables"
+                ],
+                "summary": "Изменение личного расписания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID личного расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен, полученный при создании",
+                        "name": "X-Edit-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Личное расписание",
+                        "name": "timetable",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PersonalTimetableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Измененное расписание",
+                        "schema": {
+                            "$ref": "#/definitions/models.PersonalTimetable"
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "error: Invalid edit token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Timetable not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to save timetable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "PersonalTimetables"
+                ],
+                "summary": "Удаление личного расписания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID личного расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен, полученный при создании",
+                        "name": "X-Edit-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Расписание удалено"
+                    },
+                    "403": {
+                        "description": "error: Invalid edit token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Timetable not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to delete timetable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timetables/{id}/occurrences": {
+            "get": {
+                "description": "Раскрывает личное расписание в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.\nБез параметров from и to возвращаются занятия на ближайшие 7 дней.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PersonalTimetables"
+                ],
+                "summary": "Занятия личного расписания по датам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID личного расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода в формате YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода в формате YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список занятий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/calendar.Occurrence"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error: Invalid date range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Timetable not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timetables/{id}/schedule": {
+            "get": {
+                "description": "Собирает занятия всех групп личного расписания с учетом фильтров. Поддерживает те же форматы и параметры, что и расписание группы:\njson, html, csv, ics, xlsx и pdf",
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "text/csv",
+                    "text/calendar",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "PersonalTimetables"
+                ],
+                "summary": "Занятия личного расписания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID личного расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: json, html, csv, ics, xlsx или pdf",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочитаемые типы, например text/calendar или text/html",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Интервал автообновления страницы HTML в секундах",
+                        "name": "refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание в выбранном формате",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleItem"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных и формат"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Данные не изменились"
+                    },
+                    "400": {
+                        "description": "error: unknown schedule format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error: Timetable not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "error: none of the accepted media types is supported",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "error: Semester calendar is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/write-schedule": {
+            "post": {
+                "description": "Устарел, используйте GET /export/schedule.csv. Возвращает все расписание в CSV вместо сохранения файла на сервере",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "WriteSchedule"
+                ],
+                "summary": "Сохранение расписания",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "Файл CSV",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "500": {
+                        "description": "error: Failed to fetch schedule items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "cache.Stats": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "evictions": {
+                    "type": "integer"
+                },
+                "hitRatio": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "invalidations": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "ttl": {
+                    "type": "string"
+                }
+            }
+        },
+        "calendar.Occurrence": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "item": {
+                    "$ref": "#/definitions/models.ScheduleItem"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "weekNumber": {
+                    "type": "integer"
+                }
+            }
+        },
+        "calendar.WeekInfo": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "examSession": {
+                    "type": "boolean"
+                },
+                "holiday": {
+                    "type": "string"
+                },
+                "inSemester": {
+                    "type": "boolean"
+                },
+                "parity": {
+                    "description": "ch или zn, пусто, если дата вне семестра",
+                    "type": "string"
+                },
+                "scheduleDay": {
+                    "description": "День недели и четность, по расписанию которых проходят занятия (0, если занятий нет)",
+                    "type": "integer"
+                },
+                "scheduleParity": {
+                    "type": "string"
+                },
+                "semester": {
+                    "type": "string"
+                },
+                "transfer": {
+                    "type": "string"
+                },
+                "weekNumber": {
+                    "description": "0, если дата вне семестра",
+                    "type": "integer"
+                },
+                "weekday": {
+                    "description": "1 - понедельник, 7 - воскресенье",
+                    "type": "integer"
+                }
+            }
+        },
+        "conflicts.Conflict": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "resourceName": {
+                    "type": "string"
+                },
+                "resourceUuid": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "week": {
+                    "description": "Неделя, на которой пересекаются занятия",
+                    "type": "string"
+                }
+            }
+        },
+        "gql.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "handlers.CalendarFeedCreated": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "group, teacher, audience или timetable",
+                    "type": "string"
+                },
+                "ref": {
+                    "description": "UUID группы, преподавателя, аудитории или ID личного расписания",
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webcalUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CalendarFeedRequest": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "group, teacher, audience или timetable",
+                    "type": "string"
+                },
+                "ref": {
+                    "description": "UUID группы, преподавателя, аудитории или ID личного расписания",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExportJob": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "description": "Обработано расписаний",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Причина, по которой задание не выполнено",
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "formats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kinds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "node": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Размер архива в байтах",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Количество расписаний",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ExportJobRequest": {
+            "type": "object",
+            "properties": {
+                "formats": {
+                    "description": "json, html, csv, ics, xlsx, pdf",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kinds": {
+                    "description": "group, teacher, audience, по умолчанию все",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "node": {
+                    "description": "UUID узла структуры, по умолчанию весь университет",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FreeSlot": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "day": {
+                    "type": "integer"
+                },
+                "gaps": {
+                    "description": "Изменение количества окон у студентов, если занять этот слот",
+                    "type": "integer"
+                },
+                "slot": {
+                    "type": "integer"
+                },
+                "week": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FreeSlotsRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rank": {
+                    "description": "chronological (по умолчанию) или gaps",
+                    "type": "string"
+                },
+                "teachers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.NowLesson": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "item": {
+                    "$ref": "#/definitions/models.ScheduleItem"
+                },
+                "remaining": {
+                    "type": "string"
+                },
+                "remainingSeconds": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "weekNumber": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.NowResponse": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "current": {
+                    "$ref": "#/definitions/handlers.NowLesson"
+                },
+                "next": {
+                    "$ref": "#/definitions/handlers.NowLesson"
+                }
+            }
+        },
+        "handlers.PersonalTimetableCreated": {
+            "type": "object",
+            "properties": {
+                "editToken": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimetableSource"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PersonalTimetableRequest": {
+            "type": "object",
+            "properties": {
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimetableSource"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ScheduleComparison": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "$ref": "#/definitions/models.Group"
+                },
+                "b": {
+                    "$ref": "#/definitions/models.Group"
+                },
+                "onlyA": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduleItem"
+                    }
+                },
+                "onlyB": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduleItem"
+                    }
+                },
+                "shared": {
+                    "description": "Занятия, совпадающие у обеих групп",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduleItem"
+                    }
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SlotDifference"
+                    }
+                }
+            }
+        },
+        "handlers.SlotDifference": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduleItem"
+                    }
+                },
+                "b": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduleItem"
+                    }
+                },
+                "day": {
+                    "type": "integer"
+                },
+                "slot": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "onlyA, onlyB или different",
+                    "type": "string"
+                },
+                "week": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Audience": {
+            "type": "object",
+            "properties": {
+                "building": {
+                    "type": "string"
+                },
+                "department_uid": {
+                    "description": "Может быть null",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DayTransfer": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "parity": {
+                    "description": "ch или zn, пусто - четность недели самой даты",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "weekday": {
+                    "description": "1 - понедельник, 6 - суббота",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Discipline": {
+            "type": "object",
+            "properties": {
+                "abbr": {
+                    "type": "string"
+                },
                 "actType": {
                     "type": "string"
                 },
@@ -305,6 +4498,23 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ExamSession": {
+            "type": "object",
+            "properties": {
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Group": {
             "type": "object",
             "properties": {
@@ -322,6 +4532,37 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Holiday": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PersonalTimetable": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimetableSource"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "models.ScheduleItem": {
             "type": "object",
             "properties": {
@@ -363,6 +4604,14 @@ const docTemplate = `{
                 "permission": {
                     "type": "string"
                 },
+                "slotId": {
+                    "description": "Ссылка на TimeSlot, заполняется после загрузки данных",
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "Откуда загружено занятие",
+                    "type": "string"
+                },
                 "startTime": {
                     "type": "string"
                 },
@@ -383,6 +4632,23 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Semester": {
+            "type": "object",
+            "properties": {
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Teacher": {
             "type": "object",
             "properties": {
@@ -402,6 +4668,65 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.TimeSlot": {
+            "type": "object",
+            "properties": {
+                "endTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "description": "Совпадает с ScheduleItem.Time",
+                    "type": "integer"
+                },
+                "startTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimetableSource": {
+            "type": "object",
+            "properties": {
+                "excludeActTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "excludeDisciplines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group": {
+                    "description": "UUID группы",
+                    "type": "string"
+                },
+                "includeActTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "includeDisciplines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Токен администратора в формате \"Bearer \u003cADMIN_TOKEN\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache - потокобезопасный LRU-кеш с ограничением по количеству записей и времени жизни.
+// Кеш размера 0 ничего не хранит, но считает промахи
+type Cache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List // Начало списка - недавно использованные записи
+
+	// generation увеличивается при каждой инвалидации. GetOrLoad не сохраняет значение,
+	// загруженное до инвалидации, чтобы устаревшие данные не вернулись в кеш
+	generation uint64
+
+	hits          uint64
+	misses        uint64
+	evictions     uint64
+	invalidations uint64
+
+	now func() time.Time
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// Stats описывает статистику использования кеша
+type Stats struct {
+	Capacity      int     `json:"capacity"`
+	Size          int     `json:"size"`
+	TTL           string  `json:"ttl"`
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRatio      float64 `json:"hitRatio"`
+	Evictions     uint64  `json:"evictions"`
+	Invalidations uint64  `json:"invalidations"`
+}
+
+// New создает кеш на capacity записей, каждая из которых живет ttl (0 - без ограничения)
+func New[V any](capacity int, ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get возвращает значение по ключу, если оно есть в кеше и не устарело
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(key)
+}
+
+// Set сохраняет значение, вытесняя давно не использованные записи при переполнении
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value)
+}
+
+// GetOrLoad возвращает значение из кеша или загружает его с помощью load и сохраняет.
+// Если во время загрузки кеш был инвалидирован, значение возвращается, но не сохраняется
+func (c *Cache[V]) GetOrLoad(key string, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	value, ok := c.get(key)
+	generation := c.generation
+	c.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.set(key, value)
+	}
+	return value, nil
+}
+
+// Delete удаляет записи с указанными ключами
+func (c *Cache[V]) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, key := range keys {
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+			c.invalidations++
+		}
+	}
+}
+
+// DeletePrefix удаляет все записи, ключи которых начинаются с prefix
+func (c *Cache[V]) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key, el := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+			c.invalidations++
+		}
+	}
+}
+
+// Stats возвращает статистику использования кеша
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := Stats{
+		Capacity:      c.capacity,
+		Size:          c.order.Len(),
+		TTL:           c.ttl.String(),
+		Hits:          c.hits,
+		Misses:        c.misses,
+		Evictions:     c.evictions,
+		Invalidations: c.invalidations,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRatio = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+func (c *Cache[V]) get(key string) (V, bool) {
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[V])
+		if c.ttl == 0 || c.now().Before(e.expiresAt) {
+			c.order.MoveToFront(el)
+			c.hits++
+			return e.value, true
+		}
+		c.remove(el)
+	}
+
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+func (c *Cache[V]) set(key string, value V) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[V])
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *Cache[V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry[V]).key)
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheEviction(t *testing.T) {
+	c := New[int](2, 0)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // "b" становится самой старой записью
+	c.Set("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC)
+	c := New[string](10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("group:1", "schedule")
+	_, ok := c.Get("group:1")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get("group:1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats().Size)
+}
+
+func TestCacheGetOrLoadAndInvalidate(t *testing.T) {
+	c := New[int](10, 0)
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return 42, nil
+	}
+
+	for range 3 {
+		value, err := c.GetOrLoad("teacher:1", load)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+	}
+	assert.Equal(t, 1, loads)
+
+	_, err := c.GetOrLoad("teacher:2", func() (int, error) { return 0, errors.New("db error") })
+	assert.Error(t, err)
+	assert.Equal(t, 1, c.Stats().Size)
+
+	c.Set("group:1", 1)
+	c.Delete("teacher:1")
+	c.DeletePrefix("group:")
+	assert.Equal(t, 0, c.Stats().Size)
+	assert.Equal(t, uint64(2), c.Stats().Invalidations)
+}
+
+func TestCacheDeleteDuringLoad(t *testing.T) {
+	c := New[int](10, 0)
+
+	// Инвалидация во время загрузки: загруженное значение возвращается, но не сохраняется
+	value, err := c.GetOrLoad("group:1", func() (int, error) {
+		c.Delete("group:1")
+		return 1, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+	_, ok := c.Get("group:1")
+	assert.False(t, ok)
+
+	_, err = c.GetOrLoad("group:1", func() (int, error) {
+		c.DeletePrefix("teacher:")
+		return 1, nil
+	})
+	assert.NoError(t, err)
+	_, ok = c.Get("group:1")
+	assert.False(t, ok)
+
+	// Без инвалидации значение сохраняется как обычно
+	_, err = c.GetOrLoad("group:1", func() (int, error) { return 2, nil })
+	assert.NoError(t, err)
+	value, ok = c.Get("group:1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+}
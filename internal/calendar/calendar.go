@@ -0,0 +1,390 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+)
+
+const (
+	DateLayout   = "2006-01-02"
+	defaultWeeks = 17
+)
+
+var (
+	ErrNotConfigured = errors.New("semester calendar is not configured, set SEMESTER_START or add a semester")
+	ErrOutOfSemester = errors.New("date is outside of the semester")
+	ErrInvalidConfig = errors.New("invalid semester calendar configuration")
+)
+
+// Period описывает промежуток дат, включая обе границы
+type Period struct {
+	Title string
+	Start time.Time
+	End   time.Time
+}
+
+// Holiday описывает праздничный день без занятий
+type Holiday struct {
+	Title string
+	Date  time.Time
+}
+
+// Transfer описывает перенос дня: в дату Date занятия проходят по расписанию дня Weekday.
+// Если Parity пустая, используется четность недели, в которую попадает Date
+type Transfer struct {
+	Title   string
+	Date    time.Time
+	Weekday int
+	Parity  string
+}
+
+// Data содержит настройки учебного календаря
+type Data struct {
+	Semesters    []Period
+	Holidays     []Holiday
+	ExamSessions []Period
+	Transfers    []Transfer
+}
+
+// Calendar описывает учебные семестры, праздники, сессии и переносы дней.
+// Первая неделя каждого семестра - числитель, дальше недели чередуются
+type Calendar struct {
+	mu       sync.RWMutex
+	location *time.Location
+	base     Data // Настройки из переменных окружения
+
+	semesters []Period // Начало семестра сдвинуто на понедельник первой недели
+	holidays  map[string]string
+	sessions  []Period
+	transfers map[string]Transfer
+}
+
+// WeekInfo описывает учебную неделю и день, в которые попадает дата
+type WeekInfo struct {
+	Date       string `json:"date"`
+	Weekday    int    `json:"weekday"`    // 1 - понедельник, 7 - воскресенье
+	WeekNumber int    `json:"weekNumber"` // 0, если дата вне семестра
+	Parity     string `json:"parity"`     // ch или zn, пусто, если дата вне семестра
+	InSemester bool   `json:"inSemester"`
+	Semester   string `json:"semester,omitempty"`
+
+	Holiday     string `json:"holiday,omitempty"`
+	ExamSession bool   `json:"examSession"`
+	Transfer    string `json:"transfer,omitempty"`
+	// День недели и четность, по расписанию которых проходят занятия (0, если занятий нет)
+	ScheduleDay    int    `json:"scheduleDay"`
+	ScheduleParity string `json:"scheduleParity,omitempty"`
+}
+
+// New создает календарь с указанными настройками во временной зоне location
+func New(location *time.Location, data Data) *Calendar {
+	if location == nil {
+		location = time.Local
+	}
+	c := &Calendar{location: location, base: data}
+	c.Update(Data{})
+	return c
+}
+
+// FromEnv создает календарь по переменным окружения SEMESTER_START (YYYY-MM-DD), SEMESTER_WEEKS
+// и HOLIDAYS (даты через запятую). Эти настройки используются, пока в базе данных нет семестров
+func FromEnv() (*Calendar, error) {
+	var data Data
+
+	if startStr := os.Getenv("SEMESTER_START"); startStr != "" {
+		start, err := time.ParseInLocation(DateLayout, startStr, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("%w: SEMESTER_START must be a date in YYYY-MM-DD format", ErrInvalidConfig)
+		}
+
+		weeks := defaultWeeks
+		if weeksStr := os.Getenv("SEMESTER_WEEKS"); weeksStr != "" {
+			weeks, err = strconv.Atoi(weeksStr)
+			if err != nil || weeks <= 0 {
+				return nil, fmt.Errorf("%w: SEMESTER_WEEKS must be a positive integer", ErrInvalidConfig)
+			}
+		}
+
+		monday := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		data.Semesters = append(data.Semesters, Period{
+			Start: start,
+			End:   monday.AddDate(0, 0, weeks*7-1),
+		})
+	}
+
+	if holidaysStr := os.Getenv("HOLIDAYS"); holidaysStr != "" {
+		for _, holidayStr := range strings.Split(holidaysStr, ",") {
+			holiday, err := time.ParseInLocation(DateLayout, strings.TrimSpace(holidayStr), time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%w: HOLIDAYS must be a comma-separated list of YYYY-MM-DD dates", ErrInvalidConfig)
+			}
+			data.Holidays = append(data.Holidays, Holiday{Title: "Праздничный день", Date: holiday})
+		}
+	}
+
+	return New(time.Local, data), nil
+}
+
+// FromModels преобразует записи учебного календаря из базы данных в настройки календаря
+func FromModels(semesters []models.Semester, holidays []models.Holiday, sessions []models.ExamSession, transfers []models.DayTransfer) (Data, error) {
+	var data Data
+
+	for _, s := range semesters {
+		period, err := parsePeriod(s.Title, s.StartDate, s.EndDate)
+		if err != nil {
+			return Data{}, fmt.Errorf("semester %d: %w", s.ID, err)
+		}
+		data.Semesters = append(data.Semesters, period)
+	}
+
+	for _, h := range holidays {
+		date, err := time.ParseInLocation(DateLayout, h.Date, time.Local)
+		if err != nil {
+			return Data{}, fmt.Errorf("holiday %d: invalid date %q", h.ID, h.Date)
+		}
+		data.Holidays = append(data.Holidays, Holiday{Title: h.Title, Date: date})
+	}
+
+	for _, s := range sessions {
+		period, err := parsePeriod(s.Title, s.StartDate, s.EndDate)
+		if err != nil {
+			return Data{}, fmt.Errorf("exam session %d: %w", s.ID, err)
+		}
+		data.ExamSessions = append(data.ExamSessions, period)
+	}
+
+	for _, t := range transfers {
+		date, err := time.ParseInLocation(DateLayout, t.Date, time.Local)
+		if err != nil {
+			return Data{}, fmt.Errorf("day transfer %d: invalid date %q", t.ID, t.Date)
+		}
+		data.Transfers = append(data.Transfers, Transfer{
+			Title:   t.Title,
+			Date:    date,
+			Weekday: t.Weekday,
+			Parity:  t.Parity,
+		})
+	}
+
+	return data, nil
+}
+
+func parsePeriod(title, startStr, endStr string) (Period, error) {
+	start, err := time.ParseInLocation(DateLayout, startStr, time.Local)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid start date %q", startStr)
+	}
+	end, err := time.ParseInLocation(DateLayout, endStr, time.Local)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid end date %q", endStr)
+	}
+	return Period{Title: title, Start: start, End: end}, nil
+}
+
+// Update заменяет настройки календаря из базы данных. Если в data нет семестров,
+// используется семестр из переменных окружения; праздники объединяются
+func (c *Calendar) Update(data Data) {
+	semesters := data.Semesters
+	if len(semesters) == 0 {
+		semesters = c.base.Semesters
+	}
+
+	normalized := make([]Period, 0, len(semesters))
+	for _, s := range semesters {
+		start := civil(s.Start)
+		normalized = append(normalized, Period{
+			Title: s.Title,
+			Start: start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7)),
+			End:   civil(s.End),
+		})
+	}
+	sort.Slice(normalized, func(i, j int) bool {
+		return normalized[i].Start.Before(normalized[j].Start)
+	})
+
+	holidays := make(map[string]string)
+	for _, h := range append(append([]Holiday{}, c.base.Holidays...), data.Holidays...) {
+		holidays[h.Date.Format(DateLayout)] = h.Title
+	}
+
+	sessions := make([]Period, 0, len(data.ExamSessions))
+	for _, s := range data.ExamSessions {
+		sessions = append(sessions, Period{Title: s.Title, Start: civil(s.Start), End: civil(s.End)})
+	}
+
+	transfers := make(map[string]Transfer)
+	for _, t := range data.Transfers {
+		transfers[t.Date.Format(DateLayout)] = t
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.semesters = normalized
+	c.holidays = holidays
+	c.sessions = sessions
+	c.transfers = transfers
+}
+
+// Configured сообщает, задан ли хотя бы один семестр
+func (c *Calendar) Configured() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.semesters) > 0
+}
+
+// Location возвращает временную зону календаря
+func (c *Calendar) Location() *time.Location {
+	return c.location
+}
+
+// Semesters возвращает семестры, начало каждого сдвинуто на понедельник первой недели
+func (c *Calendar) Semesters() []Period {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	semesters := make([]Period, 0, len(c.semesters))
+	for _, s := range c.semesters {
+		semesters = append(semesters, Period{Title: s.Title, Start: c.local(s.Start), End: c.local(s.End)})
+	}
+	return semesters
+}
+
+// Bounds возвращает первый и последний день всех семестров
+func (c *Calendar) Bounds() (time.Time, time.Time) {
+	semesters := c.Semesters()
+	if len(semesters) == 0 {
+		return time.Time{}, time.Time{}
+	}
+
+	first, last := semesters[0].Start, semesters[0].End
+	for _, s := range semesters[1:] {
+		if s.End.After(last) {
+			last = s.End
+		}
+	}
+	return first, last
+}
+
+// ParseDate разбирает дату в формате YYYY-MM-DD во временной зоне календаря
+func (c *Calendar) ParseDate(value string) (time.Time, error) {
+	date, err := time.ParseInLocation(DateLayout, value, c.location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	return date, nil
+}
+
+// IsHoliday сообщает, является ли дата праздничным днем
+func (c *Calendar) IsHoliday(date time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.holidays[date.In(c.location).Format(DateLayout)]
+	return ok
+}
+
+// Week возвращает номер и четность учебной недели для даты, а также день расписания,
+// по которому проходят занятия с учетом праздников, сессий и переносов
+func (c *Calendar) Week(date time.Time) (WeekInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.semesters) == 0 {
+		return WeekInfo{}, ErrNotConfigured
+	}
+
+	date = date.In(c.location)
+	day := civil(date)
+	key := date.Format(DateLayout)
+	info := WeekInfo{
+		Date:    key,
+		Weekday: (int(date.Weekday())+6)%7 + 1,
+	}
+
+	for _, s := range c.sessions {
+		if !day.Before(s.Start) && !day.After(s.End) {
+			info.ExamSession = true
+		}
+	}
+	info.Holiday = c.holidays[key]
+	_, isHoliday := c.holidays[key]
+
+	for _, s := range c.semesters {
+		if day.Before(s.Start) || day.After(s.End) {
+			continue
+		}
+
+		info.WeekNumber = int(day.Sub(s.Start).Hours()/24)/7 + 1
+		info.InSemester = true
+		info.Semester = s.Title
+		if info.WeekNumber%2 == 1 {
+			info.Parity = models.WeekNumerator
+		} else {
+			info.Parity = models.WeekDenominator
+		}
+		break
+	}
+
+	if !info.InSemester || isHoliday || info.ExamSession {
+		return info, nil
+	}
+
+	info.ScheduleDay = info.Weekday
+	info.ScheduleParity = info.Parity
+	if transfer, ok := c.transfers[key]; ok {
+		info.Transfer = transfer.Title
+		info.ScheduleDay = transfer.Weekday
+		if transfer.Parity != "" {
+			info.ScheduleParity = transfer.Parity
+		}
+	}
+	if info.ScheduleDay == 7 {
+		info.ScheduleDay = 0 // По воскресеньям занятий нет
+		info.ScheduleParity = ""
+	}
+
+	return info, nil
+}
+
+// Parity возвращает четность недели (числитель/знаменатель) для даты внутри семестра
+func (c *Calendar) Parity(date time.Time) (string, error) {
+	info, err := c.Week(date)
+	if err != nil {
+		return "", err
+	}
+	if !info.InSemester {
+		return "", fmt.Errorf("%w: %s", ErrOutOfSemester, info.Date)
+	}
+	return info.Parity, nil
+}
+
+// Resolve возвращает день недели и четность, по расписанию которых проходят занятия в дату.
+// Для праздников, сессий и воскресений возвращается день 0
+func (c *Calendar) Resolve(date time.Time) (int, string, error) {
+	info, err := c.Week(date)
+	if err != nil {
+		return 0, "", err
+	}
+	if !info.InSemester {
+		return 0, "", fmt.Errorf("%w: %s", ErrOutOfSemester, info.Date)
+	}
+	return info.ScheduleDay, info.ScheduleParity, nil
+}
+
+// local переводит календарную дату в полночь временной зоны календаря
+func (c *Calendar) local(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.location)
+}
+
+// civil отбрасывает время и временную зону, оставляя календарную дату в UTC,
+// чтобы разница между датами всегда была кратна суткам
+func civil(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
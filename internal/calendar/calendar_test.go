@@ -0,0 +1,137 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCalendarWeek(t *testing.T) {
+	// Семестр начинается в среду, первая неделя считается с понедельника
+	cal := New(time.UTC, Data{
+		Semesters: []Period{{Start: date(2025, 2, 5), End: date(2025, 6, 1)}},
+	})
+
+	cases := []struct {
+		date   string
+		number int
+		parity string
+	}{
+		{"2025-02-03", 1, models.WeekNumerator},
+		{"2025-02-09", 1, models.WeekNumerator},
+		{"2025-02-10", 2, models.WeekDenominator},
+		{"2025-02-17", 3, models.WeekNumerator},
+		{"2025-06-01", 17, models.WeekNumerator},
+		{"2025-06-02", 0, ""},
+		{"2025-01-31", 0, ""},
+	}
+
+	for _, tc := range cases {
+		d, err := cal.ParseDate(tc.date)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		info, err := cal.Week(d)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.number, info.WeekNumber, tc.date)
+		assert.Equal(t, tc.parity, info.Parity, tc.date)
+		assert.Equal(t, tc.number > 0, info.InSemester, tc.date)
+	}
+
+	_, err := cal.Parity(date(2025, 1, 31))
+	assert.ErrorIs(t, err, ErrOutOfSemester)
+}
+
+func TestCalendarNotConfigured(t *testing.T) {
+	t.Setenv("SEMESTER_START", "")
+
+	cal, err := FromEnv()
+	if assert.NoError(t, err) {
+		assert.False(t, cal.Configured())
+		_, err = cal.Week(time.Now())
+		assert.ErrorIs(t, err, ErrNotConfigured)
+	}
+}
+
+func TestCalendarUpdate(t *testing.T) {
+	cal := New(time.UTC, Data{
+		Semesters: []Period{{Start: date(2025, 2, 3), End: date(2025, 6, 1)}},
+	})
+
+	// Семестр из базы данных заменяет семестр из окружения
+	cal.Update(Data{
+		Semesters:    []Period{{Title: "Осень", Start: date(2025, 9, 1), End: date(2025, 12, 28)}},
+		Holidays:     []Holiday{{Title: "День народного единства", Date: date(2025, 11, 4)}},
+		ExamSessions: []Period{{Start: date(2025, 12, 22), End: date(2025, 12, 28)}},
+		Transfers:    []Transfer{{Title: "Перенос", Date: date(2025, 11, 1), Weekday: 3}},
+	})
+
+	info, err := cal.Week(date(2025, 3, 3))
+	assert.NoError(t, err)
+	assert.False(t, info.InSemester)
+
+	info, _ = cal.Week(date(2025, 11, 4))
+	assert.Equal(t, "День народного единства", info.Holiday)
+	assert.Equal(t, 0, info.ScheduleDay)
+
+	info, _ = cal.Week(date(2025, 12, 23))
+	assert.True(t, info.ExamSession)
+	assert.Equal(t, 0, info.ScheduleDay)
+
+	// Суббота девятой недели (числитель) по расписанию среды
+	day, parity, err := cal.Resolve(date(2025, 11, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, day)
+	assert.Equal(t, models.WeekNumerator, parity)
+
+	day, _, _ = cal.Resolve(date(2025, 11, 2))
+	assert.Equal(t, 0, day)
+}
+
+func TestCalendarExpand(t *testing.T) {
+	cal := New(time.UTC, Data{
+		Semesters: []Period{{Start: date(2025, 2, 3), End: date(2025, 2, 16)}},
+		Holidays:  []Holiday{{Date: date(2025, 2, 10)}},
+	})
+
+	scheduleItems := []models.ScheduleItem{
+		{ID: 1, Day: 1, Week: models.WeekAll, StartTime: "08:30", EndTime: "10:05"},
+		{ID: 2, Day: 2, Week: models.WeekNumerator, StartTime: "10:15", EndTime: "11:50"},
+		{ID: 3, Day: 2, Week: models.WeekDenominator, StartTime: "12:00", EndTime: "13:35"},
+	}
+
+	occurrences, err := cal.Expand(scheduleItems, date(2025, 1, 27), date(2025, 3, 1))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Понедельник второй недели - праздник
+	var ids []uint
+	for _, o := range occurrences {
+		ids = append(ids, o.Item.ID)
+	}
+	assert.Equal(t, []uint{1, 2, 3}, ids)
+	assert.Equal(t, time.Date(2025, 2, 3, 8, 30, 0, 0, time.UTC), occurrences[0].Start)
+	assert.Equal(t, time.Date(2025, 2, 11, 13, 35, 0, 0, time.UTC), occurrences[2].End)
+	assert.Equal(t, 2, occurrences[2].WeekNumber)
+
+	occurrences, err = cal.Expand(scheduleItems, date(2025, 1, 1), date(2025, 1, 31))
+	assert.NoError(t, err)
+	assert.Empty(t, occurrences)
+
+	// Семестр заканчивается во вторник: следующий день уже вне семестра
+	cal = New(time.UTC, Data{Semesters: []Period{{Start: date(2025, 2, 3), End: date(2025, 2, 11)}}})
+	occurrences, err = cal.Expand(scheduleItems, date(2025, 2, 12), date(2025, 2, 20))
+	assert.NoError(t, err)
+	assert.Empty(t, occurrences)
+
+	occurrences, err = cal.Expand(scheduleItems, date(2025, 2, 11).Add(15*time.Hour), date(2025, 2, 20))
+	assert.NoError(t, err)
+	assert.Len(t, occurrences, 1)
+}
@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+)
+
+// Occurrence описывает конкретное занятие с датой и временем
+type Occurrence struct {
+	Start      time.Time           `json:"start"`
+	End        time.Time           `json:"end"`
+	WeekNumber int                 `json:"weekNumber"`
+	Item       models.ScheduleItem `json:"item"`
+}
+
+// Expand раскрывает недельное расписание в список занятий с датами в диапазоне [from, to].
+// Учитываются четность недель, границы семестров, праздники, сессии и переносы дней
+func (c *Calendar) Expand(scheduleItems []models.ScheduleItem, from, to time.Time) ([]Occurrence, error) {
+	if !c.Configured() {
+		return nil, ErrNotConfigured
+	}
+
+	// Группируем занятия по дням недели
+	byDay := make(map[int][]models.ScheduleItem)
+	for _, item := range scheduleItems {
+		byDay[item.Day] = append(byDay[item.Day], item)
+	}
+
+	occurrences := make([]Occurrence, 0)
+	first, last := c.Bounds()
+	// Границы сравниваются по датам: диапазон, начинающийся в любой момент после последнего дня, пуст
+	from, to = dateOf(from.In(c.location)), dateOf(to.In(c.location))
+	if to.Before(first) || from.After(last) {
+		return occurrences, nil
+	}
+
+	from = clampDate(from, first, last)
+	to = clampDate(to, first, last)
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		info, err := c.Week(date)
+		if err != nil {
+			return nil, err
+		}
+		if info.ScheduleDay == 0 {
+			continue // Вне семестра, праздник, сессия или выходной
+		}
+
+		for _, item := range byDay[info.ScheduleDay] {
+			if item.Week != models.WeekAll && item.Week != info.ScheduleParity {
+				continue
+			}
+
+			start, err := c.at(date, item.StartTime)
+			if err != nil {
+				return nil, err
+			}
+			end, err := c.at(date, item.EndTime)
+			if err != nil {
+				return nil, err
+			}
+
+			occurrences = append(occurrences, Occurrence{
+				Start:      start,
+				End:        end,
+				WeekNumber: info.WeekNumber,
+				Item:       item,
+			})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+
+	return occurrences, nil
+}
+
+// at возвращает момент времени clock (HH:MM) в указанную дату
+func (c *Calendar) at(date time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid lesson time %q: %w", clock, err)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, c.location), nil
+}
+
+// clampDate ограничивает дату диапазоном [first, last]
+func clampDate(date, first, last time.Time) time.Time {
+	if date.Before(first) {
+		return first
+	}
+	if date.After(last) {
+		return last
+	}
+	return date
+}
+
+// dateOf приводит момент времени к началу суток в его временной зоне
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,154 @@
+package compress
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// compressedTypes - префиксы типов содержимого, которые уже сжаты: PDF, ZIP и документы Office
+// (XLSX - ZIP-архив). Повторное сжатие только тратит процессор
+var compressedTypes = []string{
+	"application/pdf",
+	"application/zip",
+	"application/vnd.openxmlformats-officedocument.",
+	"image/",
+}
+
+// Middleware сжимает ответы с помощью brotli или gzip в зависимости от заголовка Accept-Encoding.
+// Ответы без тела (204, 304) и уже сжатое содержимое (compressedTypes) не сжимаются
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := negotiate(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+			if encoding == "" {
+				return next(c)
+			}
+
+			w := &compressWriter{ResponseWriter: res.Writer, encoding: encoding}
+			res.Writer = w
+			defer func() {
+				if err := w.Close(); err != nil {
+					c.Logger().Error(err)
+				}
+				res.Writer = w.ResponseWriter
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// negotiate выбирает поддерживаемое сжатие с наибольшим весом, при равенстве предпочитается brotli
+func negotiate(acceptEncoding string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encodingBrotli && name != encodingGzip {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if q > bestQ || (q == bestQ && name == encodingBrotli) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	writer      io.WriteCloser
+	skip        bool
+	wroteHeader bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified || compressed(w.Header().Get(echo.HeaderContentType)) {
+		w.skip = true
+	} else {
+		w.Header().Set(echo.HeaderContentEncoding, w.encoding)
+		w.Header().Del(echo.HeaderContentLength)
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.skip {
+		return w.ResponseWriter.Write(b)
+	}
+
+	if w.writer == nil {
+		switch w.encoding {
+		case encodingBrotli:
+			w.writer = brotli.NewWriterLevel(w.ResponseWriter, brotli.DefaultCompression)
+		default:
+			w.writer = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+	return w.writer.Write(b)
+}
+
+func (w *compressWriter) Flush() {
+	if f, ok := w.writer.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func compressed(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, prefix := range compressedTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close дописывает сжатый поток, если ответ был сжат
+func (w *compressWriter) Close() error {
+	if w.writer == nil {
+		return nil
+	}
+	return w.writer.Close()
+}
@@ -0,0 +1,70 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, "br", negotiate("gzip, deflate, br"))
+	assert.Equal(t, "gzip", negotiate("gzip;q=1.0, br;q=0.5"))
+	assert.Equal(t, "gzip", negotiate("gzip"))
+	assert.Equal(t, "", negotiate("deflate, identity"))
+	assert.Equal(t, "", negotiate(""))
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	handler := Middleware()(func(c echo.Context) error {
+		return c.String(http.StatusOK, "Hello, World!")
+	})
+
+	for encoding, decode := range map[string]func(io.Reader) (io.Reader, error){
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, encoding)
+		rec := httptest.NewRecorder()
+
+		if assert.NoError(t, handler(e.NewContext(req, rec))) {
+			assert.Equal(t, encoding, rec.Header().Get(echo.HeaderContentEncoding))
+			r, err := decode(rec.Body)
+			if assert.NoError(t, err) {
+				body, err := io.ReadAll(r)
+				assert.NoError(t, err)
+				assert.Equal(t, "Hello, World!", string(body))
+			}
+		}
+	}
+
+	// Ответ 304 не сжимается
+	notModified := Middleware()(func(c echo.Context) error {
+		return c.NoContent(http.StatusNotModified)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	if assert.NoError(t, notModified(e.NewContext(req, rec))) {
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Zero(t, rec.Body.Len())
+	}
+
+	// PDF уже сжат и отдается как есть
+	pdf := Middleware()(func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "application/pdf", []byte("%PDF-1.4"))
+	})
+	rec = httptest.NewRecorder()
+	if assert.NoError(t, pdf(e.NewContext(req, rec))) {
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Equal(t, "%PDF-1.4", rec.Body.String())
+	}
+}
@@ -0,0 +1,184 @@
+package conflicts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+)
+
+// Типы конфликтов
+const (
+	TypeTeacher  = "teacher"  // Преподаватель ведет два занятия одновременно
+	TypeAudience = "audience" // В аудитории проходят два несвязанных занятия одновременно
+	TypeGroup    = "group"    // У группы пересекаются занятия
+)
+
+// Conflict описывает ресурс, занятый несколькими занятиями в одном слоте
+type Conflict struct {
+	Type         string `json:"type"`
+	ResourceUUID string `json:"resourceUuid"`
+	ResourceName string `json:"resourceName"`
+	Day          int    `json:"day"`
+	Time         int    `json:"time"`
+	Week         string `json:"week"` // Неделя, на которой пересекаются занятия
+	ItemIDs      []uint `json:"itemIds"`
+}
+
+// resource описывает преподавателя, аудиторию или группу, участвующие в занятии
+type resource struct {
+	uuid string
+	name string
+}
+
+// Detect ищет пересечения занятий у преподавателей, аудиторий и групп с учетом четности недель.
+// Занятия одного потока и одно и то же занятие (с тем же ID) конфликтом не считаются
+func Detect(scheduleItems []models.ScheduleItem) []Conflict {
+	result := make([]Conflict, 0)
+
+	result = append(result, detect(TypeTeacher, scheduleItems, func(item models.ScheduleItem) []resource {
+		resources := make([]resource, 0, len(item.Teachers))
+		for _, t := range item.Teachers {
+			name := strings.TrimSpace(fmt.Sprintf("%s %s %s", t.LastName, t.FirstName, t.MiddleName))
+			resources = append(resources, resource{uuid: t.UUID, name: name})
+		}
+		return resources
+	})...)
+
+	result = append(result, detect(TypeAudience, scheduleItems, func(item models.ScheduleItem) []resource {
+		resources := make([]resource, 0, len(item.Audiences))
+		for _, a := range item.Audiences {
+			resources = append(resources, resource{uuid: a.UUID, name: a.Name})
+		}
+		return resources
+	})...)
+
+	result = append(result, detect(TypeGroup, scheduleItems, func(item models.ScheduleItem) []resource {
+		resources := make([]resource, 0, len(item.Groups))
+		for _, g := range item.Groups {
+			resources = append(resources, resource{uuid: g.UUID, name: g.Name})
+		}
+		return resources
+	})...)
+
+	return result
+}
+
+func detect(conflictType string, scheduleItems []models.ScheduleItem, resourcesOf func(models.ScheduleItem) []resource) []Conflict {
+	type slotKey struct {
+		uuid      string
+		day, time int
+	}
+
+	// Занятия каждого ресурса по слотам
+	names := make(map[string]string)
+	slots := make(map[slotKey][]models.ScheduleItem)
+	for _, item := range scheduleItems {
+		for _, r := range resourcesOf(item) {
+			if r.uuid == "" {
+				continue
+			}
+			names[r.uuid] = r.name
+			key := slotKey{uuid: r.uuid, day: item.Day, time: item.Time}
+			slots[key] = append(slots[key], item)
+		}
+	}
+
+	type conflictKey struct {
+		slotKey
+		week string
+	}
+
+	found := make(map[conflictKey]map[uint]bool)
+	for key, items := range slots {
+		for i := 0; i < len(items); i++ {
+			for j := i + 1; j < len(items); j++ {
+				if items[i].ID == items[j].ID || legitimate(items[i], items[j]) {
+					continue
+				}
+				week, ok := overlap(items[i].Week, items[j].Week)
+				if !ok {
+					continue
+				}
+
+				ck := conflictKey{slotKey: key, week: week}
+				if found[ck] == nil {
+					found[ck] = make(map[uint]bool)
+				}
+				found[ck][items[i].ID] = true
+				found[ck][items[j].ID] = true
+			}
+		}
+	}
+
+	result := make([]Conflict, 0, len(found))
+	for key, ids := range found {
+		itemIDs := make([]uint, 0, len(ids))
+		for id := range ids {
+			itemIDs = append(itemIDs, id)
+		}
+		sort.Slice(itemIDs, func(i, j int) bool { return itemIDs[i] < itemIDs[j] })
+
+		result = append(result, Conflict{
+			Type:         conflictType,
+			ResourceUUID: key.uuid,
+			ResourceName: names[key.uuid],
+			Day:          key.day,
+			Time:         key.time,
+			Week:         key.week,
+			ItemIDs:      itemIDs,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ResourceName != b.ResourceName {
+			return a.ResourceName < b.ResourceName
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Time != b.Time {
+			return a.Time < b.Time
+		}
+		return a.Week < b.Week
+	})
+
+	return result
+}
+
+// legitimate сообщает, что одновременные занятия допустимы: это общий поток.
+// Одинаковая дисциплина без общего потока - например, две лекции разных преподавателей
+// в одной аудитории - остается конфликтом
+func legitimate(a, b models.ScheduleItem) bool {
+	return a.Stream != "" && a.Stream == b.Stream
+}
+
+// Involving оставляет конфликты, в которых участвует хотя бы одно из занятий itemIDs
+func Involving(result []Conflict, itemIDs map[uint]bool) []Conflict {
+	filtered := make([]Conflict, 0)
+	for _, conflict := range result {
+		for _, id := range conflict.ItemIDs {
+			if itemIDs[id] {
+				filtered = append(filtered, conflict)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// overlap возвращает неделю, на которой пересекаются занятия с четностью a и b
+func overlap(a, b string) (string, bool) {
+	switch {
+	case a == models.WeekAll:
+		return b, true
+	case b == models.WeekAll:
+		return a, true
+	case a == b:
+		return a, true
+	default:
+		return "", false
+	}
+}
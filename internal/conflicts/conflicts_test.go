@@ -0,0 +1,66 @@
+package conflicts
+
+import (
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	teacher := models.Teacher{UUID: "t-1", LastName: "Иванов", FirstName: "Иван", MiddleName: "Иванович"}
+	room := models.Audience{UUID: "a-1", Name: "501ю"}
+	math := models.Discipline{FullName: "Математический анализ", ActType: "lecture"}
+	physics := models.Discipline{FullName: "Физика", ActType: "seminar"}
+
+	scheduleItems := []models.ScheduleItem{
+		// Поточная лекция для двух групп - не конфликт
+		{ID: 1, Day: 1, Time: 1, Week: models.WeekAll, Stream: "ИУ7-6", Teachers: []models.Teacher{teacher},
+			Groups: []models.Group{{UUID: "g-1"}}, Disciplines: []models.Discipline{math}},
+		{ID: 2, Day: 1, Time: 1, Week: models.WeekAll, Stream: "ИУ7-6", Teachers: []models.Teacher{teacher},
+			Groups: []models.Group{{UUID: "g-2"}}, Disciplines: []models.Discipline{math}},
+		// Числитель и знаменатель не пересекаются
+		{ID: 3, Day: 2, Time: 1, Week: models.WeekNumerator, Audiences: []models.Audience{room}, Disciplines: []models.Discipline{math}},
+		{ID: 4, Day: 2, Time: 1, Week: models.WeekDenominator, Audiences: []models.Audience{room}, Disciplines: []models.Discipline{physics}},
+		// Занятие каждую неделю пересекается с занятием по числителям
+		{ID: 5, Day: 3, Time: 2, Week: models.WeekAll, Teachers: []models.Teacher{teacher}, Audiences: []models.Audience{room},
+			Disciplines: []models.Discipline{math}},
+		{ID: 6, Day: 3, Time: 2, Week: models.WeekNumerator, Teachers: []models.Teacher{teacher},
+			Disciplines: []models.Discipline{physics}},
+	}
+
+	result := Detect(scheduleItems)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, Conflict{
+			Type:         TypeTeacher,
+			ResourceUUID: "t-1",
+			ResourceName: "Иванов Иван Иванович",
+			Day:          3,
+			Time:         2,
+			Week:         models.WeekNumerator,
+			ItemIDs:      []uint{5, 6},
+		}, result[0])
+	}
+}
+
+func TestDetectSameDisciplineDifferentStreams(t *testing.T) {
+	room := models.Audience{UUID: "a-1", Name: "501ю"}
+	math := models.Discipline{FullName: "Математический анализ", ActType: "lecture"}
+
+	// Две лекции по одной дисциплине у разных потоков и преподавателей в одной аудитории
+	scheduleItems := []models.ScheduleItem{
+		{ID: 1, Day: 1, Time: 1, Week: models.WeekAll, Stream: "ИУ7-1", Audiences: []models.Audience{room},
+			Teachers: []models.Teacher{{UUID: "t-1"}}, Disciplines: []models.Discipline{math}},
+		{ID: 2, Day: 1, Time: 1, Week: models.WeekAll, Stream: "ИУ7-2", Audiences: []models.Audience{room},
+			Teachers: []models.Teacher{{UUID: "t-2"}}, Disciplines: []models.Discipline{math}},
+	}
+
+	result := Detect(scheduleItems)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, TypeAudience, result[0].Type)
+		assert.Equal(t, []uint{1, 2}, result[0].ItemIDs)
+	}
+
+	assert.Len(t, Involving(result, map[uint]bool{2: true}), 1)
+	assert.Empty(t, Involving(result, map[uint]bool{3: true}))
+}
@@ -0,0 +1,256 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+// Кодировки CSV
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF8BOM = "utf-8-bom" // Excel распознает UTF-8 только с BOM
+	EncodingCP1251  = "cp1251"
+)
+
+// Типы строк CSV
+const (
+	RowLesson = "lesson"
+	RowExam   = "exam"
+)
+
+var ErrUnknownColumn = errors.New("unknown column")
+
+// csvRow - занятие или экзамен, из которого столбцы берут значения
+type csvRow struct {
+	item *models.ScheduleItem
+	exam *models.Exam
+}
+
+// csvColumns - доступные столбцы CSV в порядке по умолчанию
+var csvColumns = []struct {
+	name  string
+	value func(row csvRow) string
+}{
+	{"type", func(row csvRow) string {
+		if row.exam != nil {
+			return RowExam
+		}
+		return RowLesson
+	}},
+	{"day", func(row csvRow) string {
+		return lessonField(row, func(i *models.ScheduleItem) string { return strconv.Itoa(i.Day) })
+	}},
+	{"time", func(row csvRow) string {
+		return lessonField(row, func(i *models.ScheduleItem) string { return strconv.Itoa(i.Time) })
+	}},
+	{"week", func(row csvRow) string {
+		return lessonField(row, func(i *models.ScheduleItem) string { return i.Week })
+	}},
+	{"date", func(row csvRow) string {
+		if row.exam != nil {
+			return row.exam.ExamDate
+		}
+		return ""
+	}},
+	{"startTime", func(row csvRow) string {
+		if row.exam != nil {
+			return row.exam.ExamTime
+		}
+		return row.item.StartTime
+	}},
+	{"endTime", func(row csvRow) string {
+		return lessonField(row, func(i *models.ScheduleItem) string { return i.EndTime })
+	}},
+	{"stream", func(row csvRow) string {
+		return lessonField(row, func(i *models.ScheduleItem) string { return i.Stream })
+	}},
+	{"disciplineAbbr", func(row csvRow) string {
+		return disciplineField(row, func(d models.Discipline) string { return d.Abbr })
+	}},
+	{"disciplineActType", func(row csvRow) string {
+		return disciplineField(row, func(d models.Discipline) string { return d.ActType })
+	}},
+	{"disciplineFullName", func(row csvRow) string {
+		return disciplineField(row, func(d models.Discipline) string { return d.FullName })
+	}},
+	{"disciplineShortName", func(row csvRow) string {
+		return disciplineField(row, func(d models.Discipline) string { return d.ShortName })
+	}},
+	{"teachers", func(row csvRow) string {
+		if row.exam != nil {
+			return strings.TrimSpace(strings.Join([]string{row.exam.LastName, row.exam.FirstName, row.exam.MiddleName}, " "))
+		}
+		names := make([]string, len(row.item.Teachers))
+		for i, t := range row.item.Teachers {
+			names[i] = strings.TrimSpace(strings.Join([]string{t.LastName, t.FirstName, t.MiddleName}, " "))
+		}
+		return strings.Join(names, "; ")
+	}},
+	{"audiences", func(row csvRow) string {
+		if row.exam != nil {
+			return row.exam.Room
+		}
+		names := make([]string, len(row.item.Audiences))
+		for i, a := range row.item.Audiences {
+			names[i] = a.Name
+		}
+		return strings.Join(names, "; ")
+	}},
+	{"groups", func(row csvRow) string {
+		var groups []models.Group
+		if row.exam != nil {
+			groups = row.exam.Groups
+		} else {
+			groups = row.item.Groups
+		}
+		names := make([]string, len(groups))
+		for i, g := range groups {
+			names[i] = g.Name
+		}
+		return strings.Join(names, "; ")
+	}},
+}
+
+func lessonField(row csvRow, value func(*models.ScheduleItem) string) string {
+	if row.item == nil {
+		return ""
+	}
+	return value(row.item)
+}
+
+// disciplineField перечисляет значения всех дисциплин строки через точку с запятой
+func disciplineField(row csvRow, value func(models.Discipline) string) string {
+	var disciplines []models.Discipline
+	if row.exam != nil {
+		disciplines = row.exam.Disciplines
+	} else {
+		disciplines = row.item.Disciplines
+	}
+	values := make([]string, len(disciplines))
+	for i, d := range disciplines {
+		values[i] = value(d)
+	}
+	return strings.Join(values, "; ")
+}
+
+// CSVColumns возвращает названия всех столбцов CSV в порядке по умолчанию
+func CSVColumns() []string {
+	names := make([]string, len(csvColumns))
+	for i, column := range csvColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// CSVOptions - настройки CSV: разделитель, кодировка и столбцы (пустой список - все столбцы)
+type CSVOptions struct {
+	Delimiter rune
+	Encoding  string
+	Columns   []string
+}
+
+// CSVWriter пишет занятия и экзамены построчно, не накапливая весь файл в памяти
+type CSVWriter struct {
+	w       *csv.Writer
+	out     io.Writer
+	closer  io.Closer
+	bom     bool
+	columns []func(row csvRow) string
+	header  []string
+}
+
+// NewCSVWriter проверяет настройки и создает CSVWriter поверх w
+func NewCSVWriter(w io.Writer, opts CSVOptions) (*CSVWriter, error) {
+	names := opts.Columns
+	if len(names) == 0 {
+		names = CSVColumns()
+	}
+
+	cw := &CSVWriter{header: names}
+	for _, name := range names {
+		found := false
+		for _, column := range csvColumns {
+			if column.name == name {
+				cw.columns = append(cw.columns, column.value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w %q", ErrUnknownColumn, name)
+		}
+	}
+
+	switch opts.Encoding {
+	case "", EncodingUTF8:
+	case EncodingUTF8BOM:
+		cw.bom = true
+	case EncodingCP1251:
+		// Символы вне CP1251 заменяются, а не обрывают выгрузку
+		tw := transform.NewWriter(w, encoding.ReplaceUnsupported(charmap.Windows1251.NewEncoder()))
+		w, cw.closer = tw, tw
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", opts.Encoding)
+	}
+
+	cw.out = w
+	cw.w = csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		cw.w.Comma = opts.Delimiter
+	}
+	return cw, nil
+}
+
+// WriteHeader записывает BOM, если он нужен, и строку заголовков
+func (cw *CSVWriter) WriteHeader() error {
+	if cw.bom {
+		if _, err := cw.out.Write([]byte("\uFEFF")); err != nil {
+			return err
+		}
+	}
+	return cw.w.Write(cw.header)
+}
+
+// WriteItem записывает занятие
+func (cw *CSVWriter) WriteItem(item models.ScheduleItem) error {
+	return cw.write(csvRow{item: &item})
+}
+
+// WriteExam записывает экзамен
+func (cw *CSVWriter) WriteExam(exam models.Exam) error {
+	return cw.write(csvRow{exam: &exam})
+}
+
+func (cw *CSVWriter) write(row csvRow) error {
+	record := make([]string, len(cw.columns))
+	for i, value := range cw.columns {
+		record[i] = value(row)
+	}
+	return cw.w.Write(record)
+}
+
+// Flush отправляет накопленные строки в нижележащий writer
+func (cw *CSVWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// Close дописывает буферизованные данные, в том числе хвост перекодировки
+func (cw *CSVWriter) Close() error {
+	if err := cw.Flush(); err != nil {
+		return err
+	}
+	if cw.closer != nil {
+		return cw.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestCSVWriter(t *testing.T) {
+	item := lecture(1, models.WeekAll)
+	item.Disciplines = append(item.Disciplines, models.Discipline{FullName: "Физика", ActType: "lab"})
+	exam := models.Exam{ExamDate: "20.06.2025", ExamTime: "10:00", Room: "218л", LastName: "Петров",
+		Disciplines: []models.Discipline{{FullName: "Физика"}}}
+
+	var buf bytes.Buffer
+	w, err := NewCSVWriter(&buf, CSVOptions{Delimiter: ';', Encoding: EncodingUTF8BOM, Columns: []string{"type", "day", "date", "disciplineFullName", "audiences"}})
+	require.NoError(t, err)
+	require.NoError(t, w.WriteHeader())
+	require.NoError(t, w.WriteItem(item))
+	require.NoError(t, w.WriteExam(exam))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "\uFEFFtype;day;date;disciplineFullName;audiences\n"+
+		"lesson;1;;\"Математический анализ; Физика\";501ю\n"+
+		"exam;;20.06.2025;Физика;218л\n", buf.String())
+}
+
+func TestCSVWriterCP1251(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCSVWriter(&buf, CSVOptions{Encoding: EncodingCP1251, Columns: []string{"disciplineFullName"}})
+	require.NoError(t, err)
+	require.NoError(t, w.WriteItem(lecture(1, models.WeekAll)))
+	require.NoError(t, w.Close())
+
+	decoded, err := charmap.Windows1251.NewDecoder().Bytes(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "Математический анализ\n", string(decoded))
+	assert.Len(t, buf.Bytes(), len([]rune("Математический анализ\n")))
+}
+
+func TestCSVWriterOptions(t *testing.T) {
+	_, err := NewCSVWriter(&bytes.Buffer{}, CSVOptions{Columns: []string{"day", "color"}})
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+
+	_, err = NewCSVWriter(&bytes.Buffer{}, CSVOptions{Encoding: "koi8-r"})
+	assert.Error(t, err)
+}
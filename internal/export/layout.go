@@ -0,0 +1,99 @@
+package export
+
+import (
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+)
+
+// layout - классическая сетка листа: дни сверху вниз, пары слева направо.
+// Внутри дня у каждой группы две строки - числитель и знаменатель
+type layout struct {
+	RowLabel string
+	Slots    []slotHeader
+	Days     []dayBlock
+}
+
+type slotHeader struct {
+	Number    int
+	StartTime string
+	EndTime   string
+}
+
+type dayBlock struct {
+	Name string
+	Rows []layoutRow
+}
+
+type layoutRow struct {
+	Title      string
+	TitleFirst bool // Первая строка расписания, в ней выводится его название
+	Parity     string
+	Cells      []layoutCell
+}
+
+// layoutCell - ячейка сетки. Span - сколько строк вниз занимает объединенная ячейка,
+// у ячеек, поглощенных объединением сверху, Span равен нулю
+type layoutCell struct {
+	Text string
+	Span int
+}
+
+// buildLayout раскладывает расписания групп листа по сетке и находит объединяемые ячейки:
+// занятие каждую неделю занимает обе строки группы, а потоковое занятие - строки соседних групп
+func buildLayout(sheet Sheet, bells map[int]timetable.Bell) layout {
+	grids := make([]timetable.Grid, len(sheet.Schedules))
+	slots := timetable.SlotsPerDay
+	for i, schedule := range sheet.Schedules {
+		grids[i] = timetable.Build(schedule.Items, bells)
+		if len(grids[i].Days) > 0 {
+			slots = max(slots, len(grids[i].Days[0].Slots))
+		}
+	}
+
+	result := layout{RowLabel: sheet.RowLabel, Slots: make([]slotHeader, slots)}
+	if result.RowLabel == "" {
+		result.RowLabel = "Группа"
+	}
+	for s := range result.Slots {
+		bell := bells[s+1]
+		result.Slots[s] = slotHeader{Number: s + 1, StartTime: bell.StartTime, EndTime: bell.EndTime}
+	}
+
+	for d := 0; d < timetable.Days; d++ {
+		block := dayBlock{Name: timetable.DayNames[d+1]}
+		var keys [][]string
+
+		for g, schedule := range sheet.Schedules {
+			for p, parity := range parities {
+				row := layoutRow{Title: schedule.Title, TitleFirst: p == 0, Parity: parity.label, Cells: make([]layoutCell, slots)}
+				rowKeys := make([]string, slots)
+				for s := 0; s < slots && s < len(grids[g].Days[d].Slots); s++ {
+					lessons := cellLessons(grids[g].Days[d].Slots[s], parity.parity)
+					if len(lessons) > 0 {
+						row.Cells[s].Text = lessonsText(lessons, sheet.ShowGroups)
+						rowKeys[s] = lessonsKey(row.Cells[s].Text, lessons)
+					}
+				}
+				block.Rows = append(block.Rows, row)
+				keys = append(keys, rowKeys)
+			}
+		}
+
+		// Объединяем подряд идущие одинаковые непустые ячейки в каждом столбце пары
+		for s := 0; s < slots; s++ {
+			for r := 0; r < len(block.Rows); {
+				end := r + 1
+				if keys[r][s] != "" {
+					for end < len(block.Rows) && keys[end][s] == keys[r][s] {
+						end++
+					}
+				}
+				block.Rows[r].Cells[s].Span = end - r
+				r = end
+			}
+		}
+
+		result.Days = append(result.Days, block)
+	}
+
+	return result
+}
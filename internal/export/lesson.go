@@ -0,0 +1,119 @@
+package export
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+)
+
+// Schedule - расписание группы, преподавателя или аудитории для печатных форматов
+type Schedule struct {
+	Title string
+	Items []models.ScheduleItem
+}
+
+// Sheet - лист (страница) экспорта с одним или несколькими расписаниями, например всеми группами кафедры.
+// RowLabel - заголовок столбца с названиями расписаний, по умолчанию «Группа».
+// Для расписаний преподавателей и аудиторий ShowGroups добавляет группы в описание занятий
+type Sheet struct {
+	Name       string
+	RowLabel   string
+	ShowGroups bool
+	Schedules  []Schedule
+}
+
+// parities - строки группы в сетке и их подписи
+var parities = []struct{ parity, label string }{
+	{models.WeekNumerator, "чс"},
+	{models.WeekDenominator, "зн"},
+}
+
+// cellLessons возвращает занятия ячейки сетки на неделе parity
+func cellLessons(cell timetable.Cell, parity string) []models.ScheduleItem {
+	lessons := append([]models.ScheduleItem(nil), cell.All...)
+	if parity == models.WeekNumerator {
+		return append(lessons, cell.Numerator...)
+	}
+	return append(lessons, cell.Denominator...)
+}
+
+// LessonText описывает занятие для печати: дисциплина с типом, преподаватели и аудитории
+func LessonText(item models.ScheduleItem) string {
+	return lessonText(item, false)
+}
+
+func lessonText(item models.ScheduleItem, withGroups bool) string {
+	var lines []string
+
+	for _, d := range item.Disciplines {
+		name := d.FullName
+		if name == "" {
+			name = d.ShortName
+		}
+		if d.ActType != "" {
+			name += " (" + d.ActType + ")"
+		}
+		lines = append(lines, name)
+	}
+
+	teachers := make([]string, 0, len(item.Teachers))
+	for _, t := range item.Teachers {
+		teachers = append(teachers, TeacherShortName(t))
+	}
+	if len(teachers) > 0 {
+		lines = append(lines, strings.Join(teachers, ", "))
+	}
+
+	audiences := make([]string, 0, len(item.Audiences))
+	for _, a := range item.Audiences {
+		audiences = append(audiences, a.Name)
+	}
+	if len(audiences) > 0 {
+		lines = append(lines, strings.Join(audiences, ", "))
+	}
+
+	if withGroups {
+		groups := make([]string, 0, len(item.Groups))
+		for _, g := range item.Groups {
+			groups = append(groups, g.Name)
+		}
+		if len(groups) > 0 {
+			lines = append(lines, strings.Join(groups, ", "))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// TeacherShortName возвращает фамилию с инициалами: Иванов И. И.
+func TeacherShortName(t models.Teacher) string {
+	name := t.LastName
+	for _, part := range []string{t.FirstName, t.MiddleName} {
+		if r := []rune(part); len(r) > 0 {
+			name += " " + string(r[0]) + "."
+		}
+	}
+	return name
+}
+
+// lessonsText объединяет описания занятий ячейки
+func lessonsText(lessons []models.ScheduleItem, withGroups bool) string {
+	texts := make([]string, len(lessons))
+	for i, item := range lessons {
+		texts[i] = lessonText(item, withGroups)
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// lessonsKey сравнивает содержимое ячеек: одинаковые ячейки соседних групп
+// относятся к потоковому занятию и объединяются
+func lessonsKey(text string, lessons []models.ScheduleItem) string {
+	keys := make([]string, len(lessons))
+	for i, item := range lessons {
+		keys[i] = item.StartTime + "|" + item.Stream
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",") + "\x00" + text
+}
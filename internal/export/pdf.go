@@ -0,0 +1,267 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+)
+
+// Шрифты DejaVu поддерживают кириллицу и встраиваются в каждый PDF (лицензия в fonts/LICENSE)
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	fontRegular []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	fontBold []byte
+)
+
+const (
+	pdfFont       = "DejaVu"
+	pdfMargin     = 8.0  // Поля страницы, мм
+	pdfDayWidth   = 20.0 // Ширина столбца дня, мм
+	pdfTitleWidth = 22.0 // Ширина столбца с названием расписания, мм
+	pdfWeekWidth  = 8.0  // Ширина столбца недели, мм
+	pdfLineHeight = 3.0  // Высота строки текста занятия, мм
+	pdfFontSize   = 6.5
+)
+
+// WritePDF записывает расписания в PDF формата A4 альбомной ориентации, каждый лист sheets начинается с новой страницы.
+// Если день не помещается на странице, он переносится на следующую целиком, а если не помещается и на пустой -
+// делится по границам расписаний, и на каждой странице повторяются заголовок таблицы и название дня
+func WritePDF(w io.Writer, sheets []Sheet, bells map[int]timetable.Bell) error {
+	pdf := newPDF()
+	if len(sheets) == 0 {
+		pdf.AddPage()
+	}
+	for _, sheet := range sheets {
+		for _, page := range planPDFSheet(pdf, sheet, buildLayout(sheet, bells)) {
+			writePDFPage(pdf, page)
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+func newPDF() *fpdf.Fpdf {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.AddUTF8FontFromBytes(pdfFont, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", fontBold)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin + 2)
+		pdf.SetFont(pdfFont, "", 6)
+		pdf.CellFormat(0, 3, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	return pdf
+}
+
+// pdfBottom - нижняя граница таблицы, ниже остается место под номер страницы
+func pdfBottom(pdf *fpdf.Fpdf) float64 {
+	_, pageHeight := pdf.GetPageSize()
+	return pageHeight - pdfMargin - 3
+}
+
+// pdfPage - страница листа: заголовок и ячейки таблицы
+type pdfPage struct {
+	title string
+	boxes []pdfCell
+}
+
+// pdfCell - ячейка таблицы с рамкой и текстом шрифтом style размера size
+type pdfCell struct {
+	x, y, width, height float64
+	text                string
+	style               string
+	size                float64
+	fill                bool
+}
+
+// planPDFSheet раскладывает лист по страницам
+func planPDFSheet(pdf *fpdf.Fpdf, sheet Sheet, grid layout) []pdfPage {
+	pageWidth, _ := pdf.GetPageSize()
+	bottom := pdfBottom(pdf)
+	slotWidth := (pageWidth - 2*pdfMargin - pdfDayWidth - pdfTitleWidth - pdfWeekWidth) / float64(len(grid.Slots))
+	top := pdfMargin + 7 // Под названием листа
+	pageTop := top + 8   // Под заголовком таблицы
+
+	var pages []pdfPage
+	var y float64
+	newPage := func() {
+		page := pdfPage{title: sheet.Name}
+		x := pdfMargin
+		for _, header := range []struct {
+			width float64
+			text  string
+		}{{pdfDayWidth, "День"}, {pdfTitleWidth, grid.RowLabel}, {pdfWeekWidth, "Нед."}} {
+			page.boxes = append(page.boxes, pdfCell{x: x, y: top, width: header.width, height: 8, text: header.text, style: "B", size: 7, fill: true})
+			x += header.width
+		}
+		for _, slot := range grid.Slots {
+			text := fmt.Sprintf("%d пара\n%s–%s", slot.Number, slot.StartTime, slot.EndTime)
+			page.boxes = append(page.boxes, pdfCell{x: x, y: top, width: slotWidth, height: 8, text: text, style: "B", size: 7, fill: true})
+			x += slotWidth
+		}
+		pages = append(pages, page)
+		y = pageTop
+	}
+
+	newPage()
+	for _, day := range grid.Days {
+		if len(day.Rows) == 0 {
+			continue
+		}
+		heights := pdfRowHeights(pdf, day, slotWidth)
+		groupEnd := func(r int) int {
+			for r++; r < len(day.Rows) && !day.Rows[r].TitleFirst; r++ {
+			}
+			return r
+		}
+
+		for from := 0; from < len(day.Rows); {
+			remaining := sumHeights(heights, from, len(day.Rows)-from)
+			first := sumHeights(heights, from, groupEnd(from)-from)
+			// День целиком переносится на новую страницу, если там поместится, иначе делится,
+			// но первое расписание части всегда должно поместиться на текущей странице
+			if y > pageTop && y+remaining > bottom && (remaining <= bottom-pageTop || y+first > bottom) {
+				newPage()
+				continue
+			}
+
+			to, height := groupEnd(from), first
+			for to < len(day.Rows) {
+				next := groupEnd(to)
+				h := sumHeights(heights, to, next-to)
+				if y+height+h > bottom {
+					break
+				}
+				to, height = next, height+h
+			}
+
+			page := &pages[len(pages)-1]
+			page.boxes = append(page.boxes, pdfDayRows(day, heights, from, to, y, slotWidth)...)
+			y += height
+			if from = to; from < len(day.Rows) {
+				newPage()
+			}
+		}
+	}
+	return pages
+}
+
+// pdfDayRows размещает строки дня с from по to (не включая) начиная с высоты y. Объединенная ячейка,
+// которая продолжается за пределами части, обрезается, а на следующей странице выводится снова
+func pdfDayRows(day dayBlock, heights []float64, from, to int, y, slotWidth float64) []pdfCell {
+	boxes := []pdfCell{{x: pdfMargin, y: y, width: pdfDayWidth, height: sumHeights(heights, from, to-from), text: day.Name, style: "B", size: 7}}
+
+	for r := from; r < to; r++ {
+		row := day.Rows[r]
+		x := pdfMargin + pdfDayWidth
+		if row.TitleFirst {
+			height := sumHeights(heights, r, min(len(parities), to-r))
+			boxes = append(boxes, pdfCell{x: x, y: y, width: pdfTitleWidth, height: height, text: row.Title, style: "B", size: 7})
+		}
+		x += pdfTitleWidth
+
+		boxes = append(boxes, pdfCell{x: x, y: y, width: pdfWeekWidth, height: heights[r], text: row.Parity, size: 6})
+		x += pdfWeekWidth
+
+		for s, cell := range row.Cells {
+			start := r
+			if cell.Span == 0 && r == from {
+				// Ячейка поглощена объединением, начатым на предыдущей странице
+				for start > 0 && day.Rows[start].Cells[s].Span == 0 {
+					start--
+				}
+				cell = day.Rows[start].Cells[s]
+			}
+			if cell.Span > 0 {
+				span := min(start+cell.Span, to) - r
+				boxes = append(boxes, pdfCell{x: x, y: y, width: slotWidth, height: sumHeights(heights, r, span), text: cell.Text, size: pdfFontSize})
+			}
+			x += slotWidth
+		}
+		y += heights[r]
+	}
+	return boxes
+}
+
+func writePDFPage(pdf *fpdf.Fpdf, page pdfPage) {
+	pdf.AddPage()
+	pdf.SetFont(pdfFont, "B", 12)
+	pdf.CellFormat(0, 7, page.title, "", 1, "C", false, 0, "")
+
+	pdf.SetFillColor(217, 225, 242)
+	for _, box := range page.boxes {
+		pdf.SetFont(pdfFont, box.style, box.size)
+		pdfBox(pdf, box.x, box.y, box.width, box.height, box.text, box.fill)
+	}
+}
+
+// pdfRowHeights подбирает высоту строк дня так, чтобы текст каждой ячейки поместился,
+// объединенным ячейкам недостающая высота добавляется к их последней строке
+func pdfRowHeights(pdf *fpdf.Fpdf, day dayBlock, slotWidth float64) []float64 {
+	pdf.SetFont(pdfFont, "", pdfFontSize)
+
+	heights := make([]float64, len(day.Rows))
+	for r, row := range day.Rows {
+		heights[r] = 2 * pdfLineHeight
+		for _, cell := range row.Cells {
+			if cell.Span == 1 {
+				heights[r] = max(heights[r], textHeight(pdf, cell.Text, slotWidth))
+			}
+		}
+	}
+
+	for r, row := range day.Rows {
+		for _, cell := range row.Cells {
+			if cell.Span > 1 {
+				last := min(r+cell.Span, len(heights)) - 1
+				if need := textHeight(pdf, cell.Text, slotWidth) - sumHeights(heights, r, cell.Span); need > 0 {
+					heights[last] += need
+				}
+			}
+		}
+	}
+	return heights
+}
+
+func textHeight(pdf *fpdf.Fpdf, text string, width float64) float64 {
+	if text == "" {
+		return 0
+	}
+	return float64(len(pdf.SplitText(text, width)))*pdfLineHeight + 1
+}
+
+func sumHeights(heights []float64, from, count int) float64 {
+	total := 0.0
+	for i := from; i < from+count && i < len(heights); i++ {
+		total += heights[i]
+	}
+	return total
+}
+
+// pdfBox рисует ячейку с рамкой и выровненным по центру многострочным текстом
+func pdfBox(pdf *fpdf.Fpdf, x, y, width, height float64, text string, fill bool) {
+	style := "D"
+	if fill {
+		style = "FD"
+	}
+	pdf.Rect(x, y, width, height, style)
+
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	lines := pdf.SplitText(text, width)
+	textY := y + (height-float64(len(lines))*pdfLineHeight)/2
+	for i, line := range lines {
+		pdf.SetXY(x, textY+float64(i)*pdfLineHeight)
+		pdf.CellFormat(width, pdfLineHeight, line, "", 0, "C", false, 0, "")
+	}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePDF(t *testing.T) {
+	schedules := make([]Schedule, 0, 12)
+	for i := 0; i < 12; i++ {
+		schedules = append(schedules, Schedule{Title: fmt.Sprintf("ИУ7-%d1Б", i+1), Items: []models.ScheduleItem{lecture(uint(i+1), models.WeekAll)}})
+	}
+	sheets := []Sheet{
+		{Name: "ИУ7", Schedules: schedules},
+		{Name: "Иванов И. И.", RowLabel: "Преподаватель", ShowGroups: true, Schedules: schedules[:1]},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, sheets, timetable.DefaultBells))
+
+	out := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+	// Кириллический шрифт встроен в документ
+	assert.Contains(t, string(out), "FontFile2")
+	// Кафедра из 12 групп не помещается на одну страницу
+	assert.GreaterOrEqual(t, bytes.Count(out, []byte("/Type /Page\n")), 3)
+}
+
+func TestPlanPDFSheetSplitsLargeDays(t *testing.T) {
+	// 25 групп кафедры: каждый день занимает больше страницы
+	schedules := make([]Schedule, 0, 25)
+	for i := 0; i < 25; i++ {
+		item := lecture(uint(i+1), models.WeekAll)
+		item.Stream = fmt.Sprintf("s%d", i/4) // Потоковые лекции по четыре группы
+		schedules = append(schedules, Schedule{Title: fmt.Sprintf("ИУ7-%02dБ", i+1), Items: []models.ScheduleItem{item}})
+	}
+	sheet := Sheet{Name: "ИУ7", Schedules: schedules}
+
+	pdf := newPDF()
+	pages := planPDFSheet(pdf, sheet, buildLayout(sheet, timetable.DefaultBells))
+
+	bottom := pdfBottom(pdf)
+	texts := make(map[string]int)
+	mondayPages := 0
+	for _, page := range pages {
+		pageTexts := make(map[string]int)
+		for _, box := range page.boxes {
+			assert.LessOrEqual(t, box.y+box.height, bottom+1e-9, "box %q is below the bottom margin", box.text)
+			pageTexts[box.text]++
+			texts[box.text]++
+		}
+		// Заголовок таблицы повторяется на каждой странице
+		assert.Equal(t, 1, pageTexts["День"])
+		if pageTexts["Понедельник"] > 0 {
+			mondayPages++
+		}
+	}
+	// Понедельник разделен, название дня выводится на каждой его странице
+	assert.GreaterOrEqual(t, mondayPages, 2)
+	assert.Equal(t, mondayPages, texts["Понедельник"])
+	for _, schedule := range schedules {
+		assert.Equal(t, timetable.Days, texts[schedule.Title], schedule.Title)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, []Sheet{sheet}, timetable.DefaultBells))
+	assert.Equal(t, len(pages), bytes.Count(buf.Bytes(), []byte("/Type /Page\n")))
+}
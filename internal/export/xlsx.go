@@ -0,0 +1,223 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	maxSheetName = 31 // Ограничение Excel на длину названия листа
+	firstSlotCol = 4  // Столбцы A-C заняты днем, названием расписания и неделей
+	headerRows   = 2  // Заголовок листа и строка с парами
+)
+
+// WriteXLSX записывает книгу Excel, по листу на каждый элемент sheets
+func WriteXLSX(w io.Writer, sheets []Sheet, bells map[int]timetable.Bell) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for i, sheet := range sheets {
+		name := sheetName(sheet.Name, used)
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", name); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+
+		if err := writeXLSXSheet(f, name, sheet, buildLayout(sheet, bells), styles); err != nil {
+			return fmt.Errorf("sheet %q: %w", sheet.Name, err)
+		}
+	}
+
+	_, err = f.WriteTo(w)
+	return err
+}
+
+type xlsxStyles struct {
+	title, header, label, lesson int
+}
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	border := []excelize.Border{
+		{Type: "left", Color: "000000", Style: 1},
+		{Type: "right", Color: "000000", Style: 1},
+		{Type: "top", Color: "000000", Style: 1},
+		{Type: "bottom", Color: "000000", Style: 1},
+	}
+	center := &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true}
+
+	var styles xlsxStyles
+	var err error
+	if styles.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}, Alignment: center}); err != nil {
+		return styles, err
+	}
+	if styles.header, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, Alignment: center, Border: border,
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9E1F2"}}}); err != nil {
+		return styles, err
+	}
+	if styles.label, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, Alignment: center, Border: border}); err != nil {
+		return styles, err
+	}
+	if styles.lesson, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Size: 9}, Alignment: center, Border: border}); err != nil {
+		return styles, err
+	}
+	return styles, nil
+}
+
+func writeXLSXSheet(f *excelize.File, name string, sheet Sheet, grid layout, styles xlsxStyles) error {
+	lastCol := firstSlotCol + len(grid.Slots) - 1
+
+	// Заголовок листа
+	if err := f.SetCellValue(name, "A1", sheet.Name); err != nil {
+		return err
+	}
+	if err := mergeXLSX(f, name, 1, 1, lastCol, 1, styles.title); err != nil {
+		return err
+	}
+
+	header := []string{"День", grid.RowLabel, "Неделя"}
+	for _, slot := range grid.Slots {
+		header = append(header, fmt.Sprintf("%d пара\n%s–%s", slot.Number, slot.StartTime, slot.EndTime))
+	}
+	for i, value := range header {
+		if err := setXLSXCell(f, name, i+1, headerRows, value, styles.header); err != nil {
+			return err
+		}
+	}
+
+	row := headerRows + 1
+	for _, day := range grid.Days {
+		if len(day.Rows) == 0 {
+			continue
+		}
+
+		if err := setXLSXCell(f, name, 1, row, day.Name, styles.label); err != nil {
+			return err
+		}
+		if err := mergeXLSX(f, name, 1, row, 1, row+len(day.Rows)-1, styles.label); err != nil {
+			return err
+		}
+
+		for r, line := range day.Rows {
+			if line.TitleFirst {
+				if err := setXLSXCell(f, name, 2, row+r, line.Title, styles.label); err != nil {
+					return err
+				}
+				if err := mergeXLSX(f, name, 2, row+r, 2, row+r+len(parities)-1, styles.label); err != nil {
+					return err
+				}
+			}
+			if err := setXLSXCell(f, name, 3, row+r, line.Parity, styles.label); err != nil {
+				return err
+			}
+
+			for s, cell := range line.Cells {
+				col := firstSlotCol + s
+				if cell.Span == 0 {
+					continue
+				}
+				if err := setXLSXCell(f, name, col, row+r, cell.Text, styles.lesson); err != nil {
+					return err
+				}
+				if err := mergeXLSX(f, name, col, row+r, col, row+r+cell.Span-1, styles.lesson); err != nil {
+					return err
+				}
+			}
+		}
+		row += len(day.Rows)
+	}
+
+	// Ширина столбцов и закрепление заголовка для печати
+	if err := f.SetColWidth(name, "A", "A", 14); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(name, "B", "B", 12); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(name, "C", "C", 7); err != nil {
+		return err
+	}
+	first, _ := excelize.ColumnNumberToName(firstSlotCol)
+	last, _ := excelize.ColumnNumberToName(lastCol)
+	if err := f.SetColWidth(name, first, last, 24); err != nil {
+		return err
+	}
+
+	orientation := "landscape"
+	if err := f.SetPageLayout(name, &excelize.PageLayoutOptions{Orientation: &orientation}); err != nil {
+		return err
+	}
+	return f.SetPanes(name, &excelize.Panes{Freeze: true, XSplit: firstSlotCol - 1, YSplit: headerRows, TopLeftCell: first + "3", ActivePane: "bottomRight"})
+}
+
+func setXLSXCell(f *excelize.File, sheet string, col, row int, value string, style int) error {
+	cell, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellValue(sheet, cell, value); err != nil {
+		return err
+	}
+	return f.SetCellStyle(sheet, cell, cell, style)
+}
+
+// mergeXLSX объединяет диапазон ячеек и применяет к нему стиль, чтобы рамки были у всего диапазона
+func mergeXLSX(f *excelize.File, sheet string, col1, row1, col2, row2, style int) error {
+	from, err := excelize.CoordinatesToCellName(col1, row1)
+	if err != nil {
+		return err
+	}
+	to, err := excelize.CoordinatesToCellName(col2, row2)
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, from, to, style); err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+	return f.MergeCell(sheet, from, to)
+}
+
+// sheetName приводит название к ограничениям Excel: не длиннее 31 символа,
+// без символов []:*?/\ и уникальное в пределах книги
+func sheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = "Расписание"
+	}
+	name = truncateRunes(name, maxSheetName)
+
+	candidate := name
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		candidate = truncateRunes(name, maxSheetName-len([]rune(suffix))) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func lecture(id uint, week string) models.ScheduleItem {
+	return models.ScheduleItem{
+		ID: id, Day: 1, Time: 1, Week: week, StartTime: "08:30", EndTime: "10:05", Stream: "ИУ7-1",
+		Disciplines: []models.Discipline{{FullName: "Математический анализ", ActType: "lecture"}},
+		Teachers:    []models.Teacher{{LastName: "Иванов", FirstName: "Иван", MiddleName: "Иванович"}},
+		Audiences:   []models.Audience{{Name: "501ю"}},
+	}
+}
+
+func TestBuildLayoutMergesStreamLectures(t *testing.T) {
+	seminar := lecture(3, models.WeekNumerator)
+	seminar.Time, seminar.StartTime, seminar.EndTime = 2, "10:15", "11:50"
+	seminar.Disciplines = []models.Discipline{{FullName: "Физика", ActType: "seminar"}}
+
+	sheet := Sheet{Name: "ИУ7, 1 курс", Schedules: []Schedule{
+		{Title: "ИУ7-11Б", Items: []models.ScheduleItem{lecture(1, models.WeekAll)}},
+		{Title: "ИУ7-12Б", Items: []models.ScheduleItem{lecture(2, models.WeekAll), seminar}},
+	}}
+
+	grid := buildLayout(sheet, timetable.DefaultBells)
+	require.Len(t, grid.Days, timetable.Days)
+	assert.Len(t, grid.Slots, timetable.SlotsPerDay)
+
+	monday := grid.Days[0]
+	require.Len(t, monday.Rows, 4)
+	// Потоковая лекция занимает обе строки обеих групп
+	assert.Equal(t, 4, monday.Rows[0].Cells[0].Span)
+	assert.Equal(t, "Математический анализ (lecture)\nИванов И. И.\n501ю", monday.Rows[0].Cells[0].Text)
+	for _, row := range monday.Rows[1:] {
+		assert.Zero(t, row.Cells[0].Span)
+	}
+	// Пустые ячейки не объединяются, семинар по числителю занимает одну строку
+	assert.Equal(t, 1, monday.Rows[0].Cells[1].Span)
+	assert.Equal(t, 1, monday.Rows[1].Cells[1].Span)
+	assert.Equal(t, 1, monday.Rows[2].Cells[1].Span)
+	assert.Contains(t, monday.Rows[2].Cells[1].Text, "Физика")
+	assert.Empty(t, monday.Rows[3].Cells[1].Text)
+}
+
+func TestWriteXLSX(t *testing.T) {
+	sheets := []Sheet{
+		{Name: "ИУ7-11Б", Schedules: []Schedule{{Title: "ИУ7-11Б", Items: []models.ScheduleItem{lecture(1, models.WeekAll)}}}},
+		{Name: "ИУ7-11Б", Schedules: []Schedule{{Title: "ИУ7-11Б"}}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, sheets, timetable.DefaultBells))
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"ИУ7-11Б", "ИУ7-11Б (2)"}, f.GetSheetList())
+
+	value, err := f.GetCellValue("ИУ7-11Б", "D3")
+	require.NoError(t, err)
+	assert.Contains(t, value, "Математический анализ")
+
+	merged, err := f.GetMergeCells("ИУ7-11Б")
+	require.NoError(t, err)
+	ranges := make([]string, len(merged))
+	for i, m := range merged {
+		ranges[i] = m.GetStartAxis() + ":" + m.GetEndAxis()
+	}
+	assert.Contains(t, ranges, "D3:D4")
+}
+
+func TestSheetName(t *testing.T) {
+	used := map[string]bool{}
+	assert.Equal(t, "ИУ7_1", sheetName("ИУ7/1", used))
+	assert.Equal(t, "ИУ7_1 (2)", sheetName("ИУ7/1", used))
+	assert.Len(t, []rune(sheetName("Очень длинное название кафедры информатики", used)), maxSheetName)
+}
@@ -0,0 +1,85 @@
+package gql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Ограничения сложности запросов
+const (
+	maxDepth       = 8     // Максимальная вложенность полей
+	maxQueryLength = 10000 // Максимальная длина текста запроса в байтах
+	maxParallelism = 10    // Количество резолверов, выполняемых параллельно
+)
+
+// Handler обслуживает GraphQL-запросы к графу расписания
+type Handler struct {
+	db     *gorm.DB
+	schema *graphql.Schema
+}
+
+// Request - тело GraphQL-запроса
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// NewHandler разбирает схему и связывает ее с резолверами
+func NewHandler(db *gorm.DB) (*Handler, error) {
+	schema, err := graphql.ParseSchema(schemaSDL, &Resolver{db: db},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxQueryLength(maxQueryLength),
+		graphql.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{db: db, schema: schema}, nil
+}
+
+// ServeHTTP выполняет GraphQL-запрос
+// @Summary GraphQL API
+// @Description Выполняет GraphQL-запрос к графу расписания: группы, занятия, преподаватели, аудитории, дисциплины, экзамены и структура университета.
+// @Description Вложенность запроса ограничена 8 уровнями, списки верхнего уровня - 500 записями, а все списки запроса вместе, включая вложенные, - 5000 записями. Запрос можно передать в теле POST или в параметрах GET.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Param request body Request false "GraphQL-запрос"
+// @Param query query string false "Текст запроса (для GET)"
+// @Success 200 {object} map[string]interface{} "data и errors по спецификации GraphQL"
+// @Failure 400 {object} map[string]string "error: Invalid request body"
+// @Router /graphql [post]
+func (h *Handler) ServeHTTP(c echo.Context) error {
+	var req Request
+
+	if c.Request().Method == http.MethodGet {
+		req.Query = c.QueryParam("query")
+		req.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid variables"})
+			}
+		}
+	} else if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.Query == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "query is required"})
+	}
+
+	// Загрузчики живут в пределах одного запроса, чтобы не отдавать устаревшие данные
+	ctx := withLoaders(c.Request().Context(), newLoaders(h.db))
+
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,106 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaMatchesResolvers(t *testing.T) {
+	// ParseSchema проверяет, что у каждого поля схемы есть резолвер
+	_, err := NewHandler(nil)
+	require.NoError(t, err)
+}
+
+func TestLimit(t *testing.T) {
+	n, err := limit(100)
+	require.NoError(t, err)
+	assert.Equal(t, 100, n)
+
+	n, err = limit(100000)
+	require.NoError(t, err)
+	assert.Equal(t, maxListSize, n)
+
+	_, err = limit(-1)
+	assert.Error(t, err)
+}
+
+func TestQueryDepthLimit(t *testing.T) {
+	h, err := NewHandler(nil)
+	require.NoError(t, err)
+
+	query := `{ groups { lessons { groups { lessons { groups { lessons { groups { lessons { id } } } } } } } } }`
+	response := h.schema.Exec(t.Context(), query, "", nil)
+	require.NotEmpty(t, response.Errors)
+}
+
+// primedLoaders возвращает загрузчики без базы данных: группа с lessons занятиями и одним экзаменом,
+// каждый из которых проводится у этой же группы, доступная из корня структуры
+func primedLoaders(t *testing.T, lessons int) *loaders {
+	group := models.Group{ID: 1, UUID: "g1", Name: "ИУ7-11Б"}
+	groupType := "group"
+	root := models.StructureNode{UUID: "root"}
+	parent := root.UUID
+
+	l := &loaders{
+		itemGroups:   dataloader.NewBatchedLoader[uint, []models.Group](nil),
+		groupLessons: dataloader.NewBatchedLoader[uint, []models.ScheduleItem](nil),
+		examGroups:   dataloader.NewBatchedLoader[uint, []models.Group](nil),
+		groupExams:   dataloader.NewBatchedLoader[uint, []models.Exam](nil),
+		groupsByUUID: dataloader.NewBatchedLoader[string, *models.Group](nil),
+	}
+	l.structureOnce.Do(func() {})
+	l.structure = &structureTree{
+		root:     &root,
+		children: map[string][]models.StructureNode{root.UUID: {{UUID: group.UUID, ParentUUID: &parent, NodeType: &groupType}}},
+	}
+
+	items := make([]models.ScheduleItem, lessons)
+	for i := range items {
+		items[i] = models.ScheduleItem{ID: uint(i + 1)}
+		l.itemGroups.Prime(t.Context(), items[i].ID, []models.Group{group})
+	}
+	l.groupLessons.Prime(t.Context(), group.ID, items)
+
+	exam := models.Exam{ID: 1, Room: "218л"}
+	l.groupExams.Prime(t.Context(), group.ID, []models.Exam{exam})
+	l.examGroups.Prime(t.Context(), exam.ID, []models.Group{group})
+	l.groupsByUUID.Prime(t.Context(), group.UUID, &group)
+	return l
+}
+
+func TestNestedListsRecordLimit(t *testing.T) {
+	h, err := NewHandler(nil)
+	require.NoError(t, err)
+
+	exec := func(query string) []string {
+		ctx := withLoaders(t.Context(), primedLoaders(t, 100))
+		var messages []string
+		for _, e := range h.schema.Exec(ctx, query, "", nil).Errors {
+			messages = append(messages, e.Message)
+		}
+		return messages
+	}
+
+	// 100 занятий и по группе у каждого укладываются в бюджет
+	assert.Empty(t, exec(`{ structure { children { group { lessons { id groups { name } } } } } }`))
+
+	// Занятия групп каждого занятия - 100 * 100 записей
+	errs := exec(`{ structure { children { group { lessons { groups { lessons { id } } } } } } }`)
+	require.NotEmpty(t, errs)
+	assert.Contains(t, errs[0], errTooManyRecords.Error())
+}
+
+func TestExamGroups(t *testing.T) {
+	h, err := NewHandler(nil)
+	require.NoError(t, err)
+
+	ctx := withLoaders(t.Context(), primedLoaders(t, 1))
+	response := h.schema.Exec(ctx, `{ structure { children { group { exams { room groups { name } } } } } }`, "", nil)
+	require.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"structure":{"children":[{"group":{"exams":[{"room":"218л","groups":[{"name":"ИУ7-11Б"}]}]}}]}}`,
+		string(response.Data))
+}
@@ -0,0 +1,232 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type loadersKey struct{}
+
+// maxRecords ограничивает количество записей во всех списках одного запроса, включая вложенные
+const maxRecords = 5000
+
+var errTooManyRecords = fmt.Errorf("query exceeds the limit of %d records", maxRecords)
+
+// loaders - загрузчики связей, создаваемые на каждый запрос.
+// Запросы связей из разных резолверов объединяются в один запрос к БД на таблицу связи
+type loaders struct {
+	itemGroups      *dataloader.Loader[uint, []models.Group]
+	itemTeachers    *dataloader.Loader[uint, []models.Teacher]
+	itemAudiences   *dataloader.Loader[uint, []models.Audience]
+	itemDisciplines *dataloader.Loader[uint, []models.Discipline]
+	examDisciplines *dataloader.Loader[uint, []models.Discipline]
+	examGroups      *dataloader.Loader[uint, []models.Group]
+	groupExams      *dataloader.Loader[uint, []models.Exam]
+	groupLessons    *dataloader.Loader[uint, []models.ScheduleItem]
+	teacherLessons  *dataloader.Loader[uint, []models.ScheduleItem]
+	audienceLessons *dataloader.Loader[uint, []models.ScheduleItem]
+	groupsByUUID    *dataloader.Loader[string, *models.Group]
+
+	structureOnce sync.Once
+	structure     *structureTree
+	structureErr  error
+
+	records atomic.Int64 // Записи, уже отданные в списках запроса
+}
+
+func newLoaders(db *gorm.DB) *loaders {
+	lessons := func(db *gorm.DB) *gorm.DB { return db.Order("day, time, id") }
+	exams := func(db *gorm.DB) *gorm.DB { return db.Order("exam_date, exam_time, id") }
+
+	return &loaders{
+		itemGroups: dataloader.NewBatchedLoader(manyToMany[models.Group](db,
+			"schedule_item_groups", "schedule_item_id", "group_id", nil)),
+		itemTeachers: dataloader.NewBatchedLoader(manyToMany[models.Teacher](db,
+			"schedule_item_teachers", "schedule_item_id", "teacher_id", nil)),
+		itemAudiences: dataloader.NewBatchedLoader(manyToMany[models.Audience](db,
+			"schedule_item_audiences", "schedule_item_id", "audience_id", nil)),
+		itemDisciplines: dataloader.NewBatchedLoader(manyToMany[models.Discipline](db,
+			"schedule_item_disciplines", "schedule_item_id", "discipline_id", nil)),
+		examDisciplines: dataloader.NewBatchedLoader(manyToMany[models.Discipline](db,
+			"exam_disciplines", "exam_id", "discipline_id", nil)),
+		examGroups: dataloader.NewBatchedLoader(manyToMany[models.Group](db,
+			"exam_groups", "exam_id", "group_id", nil)),
+		groupExams: dataloader.NewBatchedLoader(manyToMany[models.Exam](db,
+			"exam_groups", "group_id", "exam_id", exams)),
+		groupLessons: dataloader.NewBatchedLoader(manyToMany[models.ScheduleItem](db,
+			"schedule_item_groups", "group_id", "schedule_item_id", lessons)),
+		teacherLessons: dataloader.NewBatchedLoader(manyToMany[models.ScheduleItem](db,
+			"schedule_item_teachers", "teacher_id", "schedule_item_id", lessons)),
+		audienceLessons: dataloader.NewBatchedLoader(manyToMany[models.ScheduleItem](db,
+			"schedule_item_audiences", "audience_id", "schedule_item_id", lessons)),
+		groupsByUUID: dataloader.NewBatchedLoader(groupsByUUID(db)),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// reserve проверяет, что бюджет записей запроса еще не исчерпан, до загрузки очередного списка
+func (l *loaders) reserve() error {
+	if l.records.Load() >= maxRecords {
+		return errTooManyRecords
+	}
+	return nil
+}
+
+// charge учитывает n записей списка в бюджете запроса
+func (l *loaders) charge(n int) error {
+	if l.records.Add(int64(n)) > maxRecords {
+		return errTooManyRecords
+	}
+	return nil
+}
+
+// link - строка таблицы связи many2many
+type link struct {
+	OwnerID  uint
+	TargetID uint
+}
+
+// manyToMany загружает связанные записи для набора владельцев двумя запросами:
+// строки таблицы связи и сами записи. scope задает порядок связанных записей
+func manyToMany[T any](db *gorm.DB, joinTable, ownerColumn, targetColumn string, scope func(*gorm.DB) *gorm.DB) dataloader.BatchFunc[uint, []T] {
+	return func(ctx context.Context, keys []uint) []*dataloader.Result[[]T] {
+		results := make([]*dataloader.Result[[]T], len(keys))
+
+		fail := func(err error) []*dataloader.Result[[]T] {
+			for i := range results {
+				results[i] = &dataloader.Result[[]T]{Error: err}
+			}
+			return results
+		}
+
+		var links []link
+		err := db.WithContext(ctx).
+			Table(joinTable).
+			Select(fmt.Sprintf("%s AS owner_id, %s AS target_id", ownerColumn, targetColumn)).
+			Where(ownerColumn+" IN ?", keys).
+			Scan(&links).Error
+		if err != nil {
+			return fail(err)
+		}
+
+		owners := make(map[uint][]uint)
+		var targetIDs []uint
+		for _, l := range links {
+			if _, ok := owners[l.TargetID]; !ok {
+				targetIDs = append(targetIDs, l.TargetID)
+			}
+			owners[l.TargetID] = append(owners[l.TargetID], l.OwnerID)
+		}
+
+		var targets []T
+		if len(targetIDs) > 0 {
+			query := db.WithContext(ctx)
+			if scope != nil {
+				query = scope(query)
+			}
+			if err := query.Find(&targets, targetIDs).Error; err != nil {
+				return fail(err)
+			}
+		}
+
+		// Порядок связанных записей совпадает с порядком выборки
+		grouped := make(map[uint][]T, len(keys))
+		for _, target := range targets {
+			for _, owner := range owners[recordID(target)] {
+				grouped[owner] = append(grouped[owner], target)
+			}
+		}
+
+		for i, key := range keys {
+			values := grouped[key]
+			if values == nil {
+				values = []T{}
+			}
+			results[i] = &dataloader.Result[[]T]{Data: values}
+		}
+		return results
+	}
+}
+
+// recordID возвращает первичный ключ записи, загружаемой через таблицу связи
+func recordID(record any) uint {
+	switch r := record.(type) {
+	case models.Group:
+		return r.ID
+	case models.Teacher:
+		return r.ID
+	case models.Audience:
+		return r.ID
+	case models.Discipline:
+		return r.ID
+	case models.ScheduleItem:
+		return r.ID
+	case models.Exam:
+		return r.ID
+	}
+	panic(fmt.Sprintf("gql: unsupported record type %T", record))
+}
+
+func groupsByUUID(db *gorm.DB) dataloader.BatchFunc[string, *models.Group] {
+	return func(ctx context.Context, keys []string) []*dataloader.Result[*models.Group] {
+		results := make([]*dataloader.Result[*models.Group], len(keys))
+
+		var groups []models.Group
+		if err := db.WithContext(ctx).Where("uuid IN ?", keys).Find(&groups).Error; err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*models.Group]{Error: err}
+			}
+			return results
+		}
+
+		byUUID := make(map[string]*models.Group, len(groups))
+		for i := range groups {
+			byUUID[groups[i].UUID] = &groups[i]
+		}
+		for i, key := range keys {
+			results[i] = &dataloader.Result[*models.Group]{Data: byUUID[key]}
+		}
+		return results
+	}
+}
+
+// structureTree - дерево структуры университета, загружаемое один раз за запрос
+type structureTree struct {
+	root     *models.StructureNode
+	children map[string][]models.StructureNode
+}
+
+func (l *loaders) structureTree(ctx context.Context, db *gorm.DB) (*structureTree, error) {
+	l.structureOnce.Do(func() {
+		nodes, err := repository.StructureNodes(db.WithContext(ctx))
+		if err != nil {
+			l.structureErr = err
+			return
+		}
+
+		tree := &structureTree{children: make(map[string][]models.StructureNode)}
+		for i, node := range nodes {
+			if node.ParentUUID == nil {
+				tree.root = &nodes[i]
+				continue
+			}
+			tree.children[*node.ParentUUID] = append(tree.children[*node.ParentUUID], node)
+		}
+		l.structure = tree
+	})
+	return l.structure, l.structureErr
+}
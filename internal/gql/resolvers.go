@@ -0,0 +1,397 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxListSize ограничивает количество записей в корневых списках
+const maxListSize = 500
+
+// Resolver - корневой резолвер запросов
+type Resolver struct {
+	db *gorm.DB
+}
+
+type listArgs struct {
+	Name  *string
+	First int32
+}
+
+// limit возвращает допустимое количество записей для аргумента first
+func limit(first int32) (int, error) {
+	if first < 0 {
+		return 0, errors.New("first must be non-negative")
+	}
+	return min(int(first), maxListSize), nil
+}
+
+func (r *Resolver) Groups(ctx context.Context, args listArgs) ([]*groupResolver, error) {
+	n, err := limit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Order("name").Limit(n)
+	if args.Name != nil && *args.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+*args.Name+"%")
+	}
+
+	var groups []models.Group
+	if err := query.Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	if err := loadersFrom(ctx).charge(len(groups)); err != nil {
+		return nil, err
+	}
+	return wrap(groups, func(g models.Group) *groupResolver { return &groupResolver{g} }), nil
+}
+
+func (r *Resolver) Group(ctx context.Context, args struct{ UUID string }) (*groupResolver, error) {
+	group, err := loadersFrom(ctx).groupsByUUID.Load(ctx, args.UUID)()
+	if err != nil || group == nil {
+		return nil, err
+	}
+	return &groupResolver{*group}, nil
+}
+
+func (r *Resolver) Teachers(ctx context.Context, args listArgs) ([]*teacherResolver, error) {
+	n, err := limit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Order("last_name, first_name, middle_name").Limit(n)
+	if args.Name != nil && *args.Name != "" {
+		query = query.Where("last_name ILIKE ?", "%"+*args.Name+"%")
+	}
+
+	var teachers []models.Teacher
+	if err := query.Find(&teachers).Error; err != nil {
+		return nil, err
+	}
+	if err := loadersFrom(ctx).charge(len(teachers)); err != nil {
+		return nil, err
+	}
+	return wrap(teachers, func(t models.Teacher) *teacherResolver { return &teacherResolver{t} }), nil
+}
+
+func (r *Resolver) Teacher(ctx context.Context, args struct{ UUID string }) (*teacherResolver, error) {
+	var teacher models.Teacher
+	if err := r.db.WithContext(ctx).Where("uuid = ?", args.UUID).First(&teacher).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &teacherResolver{teacher}, nil
+}
+
+func (r *Resolver) Audiences(ctx context.Context, args struct {
+	Building *string
+	First    int32
+}) ([]*audienceResolver, error) {
+	n, err := limit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Order("name").Limit(n)
+	if args.Building != nil && *args.Building != "" {
+		query = query.Where("building = ?", *args.Building)
+	}
+
+	var audiences []models.Audience
+	if err := query.Find(&audiences).Error; err != nil {
+		return nil, err
+	}
+	if err := loadersFrom(ctx).charge(len(audiences)); err != nil {
+		return nil, err
+	}
+	return wrap(audiences, func(a models.Audience) *audienceResolver { return &audienceResolver{a} }), nil
+}
+
+func (r *Resolver) Audience(ctx context.Context, args struct{ UUID string }) (*audienceResolver, error) {
+	var audience models.Audience
+	if err := r.db.WithContext(ctx).Where("uuid = ?", args.UUID).First(&audience).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &audienceResolver{audience}, nil
+}
+
+func (r *Resolver) Disciplines(ctx context.Context, args listArgs) ([]*disciplineResolver, error) {
+	n, err := limit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Order("full_name, act_type").Limit(n)
+	if args.Name != nil && *args.Name != "" {
+		query = query.Where("full_name ILIKE ?", "%"+*args.Name+"%")
+	}
+
+	var disciplines []models.Discipline
+	if err := query.Find(&disciplines).Error; err != nil {
+		return nil, err
+	}
+	if err := loadersFrom(ctx).charge(len(disciplines)); err != nil {
+		return nil, err
+	}
+	return wrap(disciplines, func(d models.Discipline) *disciplineResolver { return &disciplineResolver{d} }), nil
+}
+
+func (r *Resolver) ScheduleItems(ctx context.Context, args struct{ First int32 }) ([]*scheduleItemResolver, error) {
+	n, err := limit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.ScheduleItem
+	if err := r.db.WithContext(ctx).Order("day, time, id").Limit(n).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if err := loadersFrom(ctx).charge(len(items)); err != nil {
+		return nil, err
+	}
+	return wrap(items, func(s models.ScheduleItem) *scheduleItemResolver { return &scheduleItemResolver{s} }), nil
+}
+
+func (r *Resolver) Exams(ctx context.Context, args struct{ First int32 }) ([]*examResolver, error) {
+	n, err := limit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	var exams []models.Exam
+	if err := r.db.WithContext(ctx).Order("exam_date, exam_time, id").Limit(n).Find(&exams).Error; err != nil {
+		return nil, err
+	}
+	if err := loadersFrom(ctx).charge(len(exams)); err != nil {
+		return nil, err
+	}
+	return wrap(exams, func(e models.Exam) *examResolver { return &examResolver{e} }), nil
+}
+
+func (r *Resolver) Structure(ctx context.Context) (*structureNodeResolver, error) {
+	tree, err := loadersFrom(ctx).structureTree(ctx, r.db)
+	if err != nil || tree.root == nil {
+		return nil, err
+	}
+	return &structureNodeResolver{node: *tree.root, tree: tree}, nil
+}
+
+type groupResolver struct{ g models.Group }
+
+func (r *groupResolver) ID() graphql.ID        { return uintID(r.g.ID) }
+func (r *groupResolver) UUID() string          { return r.g.UUID }
+func (r *groupResolver) Name() string          { return r.g.Name }
+func (r *groupResolver) DepartmentUID() string { return r.g.DepartmentUID }
+
+func (r *groupResolver) Lessons(ctx context.Context) ([]*scheduleItemResolver, error) {
+	return lessons(ctx, loadersFrom(ctx).groupLessons, r.g.ID)
+}
+
+func (r *groupResolver) Exams(ctx context.Context) ([]*examResolver, error) {
+	exams, err := related(ctx, loadersFrom(ctx).groupExams, r.g.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(exams, func(e models.Exam) *examResolver { return &examResolver{e} }), nil
+}
+
+type teacherResolver struct{ t models.Teacher }
+
+func (r *teacherResolver) ID() graphql.ID     { return uintID(r.t.ID) }
+func (r *teacherResolver) UUID() string       { return r.t.UUID }
+func (r *teacherResolver) LastName() string   { return r.t.LastName }
+func (r *teacherResolver) FirstName() string  { return r.t.FirstName }
+func (r *teacherResolver) MiddleName() string { return r.t.MiddleName }
+
+func (r *teacherResolver) Lessons(ctx context.Context) ([]*scheduleItemResolver, error) {
+	return lessons(ctx, loadersFrom(ctx).teacherLessons, r.t.ID)
+}
+
+type audienceResolver struct{ a models.Audience }
+
+func (r *audienceResolver) ID() graphql.ID         { return uintID(r.a.ID) }
+func (r *audienceResolver) UUID() string           { return r.a.UUID }
+func (r *audienceResolver) Name() string           { return r.a.Name }
+func (r *audienceResolver) Building() string       { return r.a.Building }
+func (r *audienceResolver) DepartmentUID() *string { return r.a.DepartmentUID }
+
+func (r *audienceResolver) Lessons(ctx context.Context) ([]*scheduleItemResolver, error) {
+	return lessons(ctx, loadersFrom(ctx).audienceLessons, r.a.ID)
+}
+
+type disciplineResolver struct{ d models.Discipline }
+
+func (r *disciplineResolver) ID() graphql.ID    { return uintID(r.d.ID) }
+func (r *disciplineResolver) Abbr() string      { return r.d.Abbr }
+func (r *disciplineResolver) ActType() string   { return r.d.ActType }
+func (r *disciplineResolver) FullName() string  { return r.d.FullName }
+func (r *disciplineResolver) ShortName() string { return r.d.ShortName }
+
+type scheduleItemResolver struct{ s models.ScheduleItem }
+
+func (r *scheduleItemResolver) ID() graphql.ID { return uintID(r.s.ID) }
+func (r *scheduleItemResolver) Day() int32     { return int32(r.s.Day) }
+func (r *scheduleItemResolver) Time() int32    { return int32(r.s.Time) }
+func (r *scheduleItemResolver) Week() string   { return r.s.Week }
+
+func (r *scheduleItemResolver) SlotID() *graphql.ID {
+	if r.s.SlotID == nil {
+		return nil
+	}
+	id := uintID(*r.s.SlotID)
+	return &id
+}
+
+func (r *scheduleItemResolver) Stream() string     { return r.s.Stream }
+func (r *scheduleItemResolver) StartTime() string  { return r.s.StartTime }
+func (r *scheduleItemResolver) EndTime() string    { return r.s.EndTime }
+func (r *scheduleItemResolver) Permission() string { return r.s.Permission }
+
+func (r *scheduleItemResolver) Groups(ctx context.Context) ([]*groupResolver, error) {
+	groups, err := related(ctx, loadersFrom(ctx).itemGroups, r.s.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(groups, func(g models.Group) *groupResolver { return &groupResolver{g} }), nil
+}
+
+func (r *scheduleItemResolver) Teachers(ctx context.Context) ([]*teacherResolver, error) {
+	teachers, err := related(ctx, loadersFrom(ctx).itemTeachers, r.s.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(teachers, func(t models.Teacher) *teacherResolver { return &teacherResolver{t} }), nil
+}
+
+func (r *scheduleItemResolver) Audiences(ctx context.Context) ([]*audienceResolver, error) {
+	audiences, err := related(ctx, loadersFrom(ctx).itemAudiences, r.s.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(audiences, func(a models.Audience) *audienceResolver { return &audienceResolver{a} }), nil
+}
+
+func (r *scheduleItemResolver) Disciplines(ctx context.Context) ([]*disciplineResolver, error) {
+	disciplines, err := related(ctx, loadersFrom(ctx).itemDisciplines, r.s.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(disciplines, func(d models.Discipline) *disciplineResolver { return &disciplineResolver{d} }), nil
+}
+
+type examResolver struct{ e models.Exam }
+
+func (r *examResolver) ID() graphql.ID     { return uintID(r.e.ID) }
+func (r *examResolver) Room() string       { return r.e.Room }
+func (r *examResolver) ExamDate() string   { return r.e.ExamDate }
+func (r *examResolver) ExamTime() string   { return r.e.ExamTime }
+func (r *examResolver) LastName() string   { return r.e.LastName }
+func (r *examResolver) FirstName() string  { return r.e.FirstName }
+func (r *examResolver) MiddleName() string { return r.e.MiddleName }
+
+func (r *examResolver) Disciplines(ctx context.Context) ([]*disciplineResolver, error) {
+	disciplines, err := related(ctx, loadersFrom(ctx).examDisciplines, r.e.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(disciplines, func(d models.Discipline) *disciplineResolver { return &disciplineResolver{d} }), nil
+}
+
+func (r *examResolver) Groups(ctx context.Context) ([]*groupResolver, error) {
+	groups, err := related(ctx, loadersFrom(ctx).examGroups, r.e.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(groups, func(g models.Group) *groupResolver { return &groupResolver{g} }), nil
+}
+
+type structureNodeResolver struct {
+	node models.StructureNode
+	tree *structureTree
+}
+
+func (r *structureNodeResolver) UUID() string      { return r.node.UUID }
+func (r *structureNodeResolver) Abbr() string      { return r.node.Abbr }
+func (r *structureNodeResolver) Name() string      { return r.node.Name }
+func (r *structureNodeResolver) NodeType() *string { return r.node.NodeType }
+func (r *structureNodeResolver) Course() *int32    { return int32Ptr(r.node.Course) }
+func (r *structureNodeResolver) Semester() *int32  { return int32Ptr(r.node.Semester) }
+
+func (r *structureNodeResolver) Children(ctx context.Context) ([]*structureNodeResolver, error) {
+	children := r.tree.children[r.node.UUID]
+	if err := loadersFrom(ctx).charge(len(children)); err != nil {
+		return nil, err
+	}
+	return wrap(children, func(n models.StructureNode) *structureNodeResolver {
+		return &structureNodeResolver{node: n, tree: r.tree}
+	}), nil
+}
+
+func (r *structureNodeResolver) Group(ctx context.Context) (*groupResolver, error) {
+	if r.node.NodeType == nil || *r.node.NodeType != "group" {
+		return nil, nil
+	}
+	group, err := loadersFrom(ctx).groupsByUUID.Load(ctx, r.node.UUID)()
+	if err != nil || group == nil {
+		return nil, err
+	}
+	return &groupResolver{*group}, nil
+}
+
+func lessons(ctx context.Context, loader *dataloader.Loader[uint, []models.ScheduleItem], id uint) ([]*scheduleItemResolver, error) {
+	items, err := related(ctx, loader, id)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(items, func(s models.ScheduleItem) *scheduleItemResolver { return &scheduleItemResolver{s} }), nil
+}
+
+// related загружает связанные записи через загрузчик запроса. Вложенные списки не ограничены аргументом first,
+// поэтому каждый из них расходует общий бюджет записей запроса, и после его исчерпания списки не загружаются
+func related[K comparable, T any](ctx context.Context, loader *dataloader.Loader[K, []T], key K) ([]T, error) {
+	l := loadersFrom(ctx)
+	if err := l.reserve(); err != nil {
+		return nil, err
+	}
+	values, err := loader.Load(ctx, key)()
+	if err != nil {
+		return nil, err
+	}
+	if err := l.charge(len(values)); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func wrap[T, R any](values []T, fn func(T) R) []R {
+	result := make([]R, len(values))
+	for i, v := range values {
+		result[i] = fn(v)
+	}
+	return result
+}
+
+func uintID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
@@ -0,0 +1,108 @@
+package grpcserver
+
+import (
+	"github.com/kosttiik/semesterly_backend/internal/handlers"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	pb "github.com/kosttiik/semesterly_backend/internal/pb/semesterlyv1"
+)
+
+var weeks = map[string]pb.Week{
+	models.WeekAll:         pb.Week_WEEK_ALL,
+	models.WeekNumerator:   pb.Week_WEEK_NUMERATOR,
+	models.WeekDenominator: pb.Week_WEEK_DENOMINATOR,
+}
+
+func convertScheduleItems(scheduleItems []models.ScheduleItem) []*pb.ScheduleItem {
+	result := make([]*pb.ScheduleItem, 0, len(scheduleItems))
+	for _, item := range scheduleItems {
+		result = append(result, &pb.ScheduleItem{
+			Id:          uint64(item.ID),
+			Day:         int32(item.Day),
+			Time:        int32(item.Time),
+			Week:        weeks[item.Week],
+			Stream:      item.Stream,
+			StartTime:   item.StartTime,
+			EndTime:     item.EndTime,
+			Permission:  item.Permission,
+			Groups:      convertGroups(item.Groups),
+			Teachers:    convertTeachers(item.Teachers),
+			Audiences:   convertAudiences(item.Audiences),
+			Disciplines: convertDisciplines(item.Disciplines),
+			SlotId:      slotID(item.SlotID),
+		})
+	}
+	return result
+}
+
+func slotID(id *uint) *uint64 {
+	if id == nil {
+		return nil
+	}
+	v := uint64(*id)
+	return &v
+}
+
+func convertGroups(groups []models.Group) []*pb.Group {
+	result := make([]*pb.Group, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, &pb.Group{
+			Id:            uint64(g.ID),
+			Uuid:          g.UUID,
+			Name:          g.Name,
+			DepartmentUid: g.DepartmentUID,
+		})
+	}
+	return result
+}
+
+func convertTeachers(teachers []models.Teacher) []*pb.Teacher {
+	result := make([]*pb.Teacher, 0, len(teachers))
+	for _, t := range teachers {
+		result = append(result, &pb.Teacher{
+			Id:         uint64(t.ID),
+			Uuid:       t.UUID,
+			LastName:   t.LastName,
+			FirstName:  t.FirstName,
+			MiddleName: t.MiddleName,
+		})
+	}
+	return result
+}
+
+func convertAudiences(audiences []models.Audience) []*pb.Audience {
+	result := make([]*pb.Audience, 0, len(audiences))
+	for _, a := range audiences {
+		result = append(result, &pb.Audience{
+			Id:            uint64(a.ID),
+			Uuid:          a.UUID,
+			Name:          a.Name,
+			Building:      a.Building,
+			DepartmentUid: a.DepartmentUID,
+		})
+	}
+	return result
+}
+
+func convertDisciplines(disciplines []models.Discipline) []*pb.Discipline {
+	result := make([]*pb.Discipline, 0, len(disciplines))
+	for _, d := range disciplines {
+		result = append(result, &pb.Discipline{
+			Id:        uint64(d.ID),
+			Abbr:      d.Abbr,
+			ActType:   d.ActType,
+			FullName:  d.FullName,
+			ShortName: d.ShortName,
+		})
+	}
+	return result
+}
+
+func convertProgress(update handlers.ProgressUpdate) *pb.StreamSyncProgressResponse {
+	return &pb.StreamSyncProgressResponse{
+		CurrentItem:    int32(update.CurrentItem),
+		TotalItems:     int32(update.TotalItems),
+		CompletedItems: int32(update.CompletedItems),
+		Percentage:     update.Percentage,
+		Eta:            update.ETA,
+	}
+}
@@ -0,0 +1,164 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/handlers"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	pb "github.com/kosttiik/semesterly_backend/internal/pb/semesterlyv1"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// adminMethods - методы, которые, как и административные REST-эндпоинты, требуют ADMIN_TOKEN
+var adminMethods = map[string]bool{
+	pb.ScheduleService_TriggerSync_FullMethodName: true,
+}
+
+// syncCheckInterval - как часто поток прогресса проверяет, что синхронизация еще идет. Последнее
+// обновление приходит не всегда: синхронизация может завершиться ошибкой, флаг может держать
+// загрузка расписания без прогресса, а обновление может быть пропущено при переполнении подписки
+var syncCheckInterval = time.Second
+
+// Server реализует ScheduleService поверх тех же запросов, что и REST-обработчики
+type Server struct {
+	pb.UnimplementedScheduleServiceServer
+	app     *handlers.App
+	syncing func() bool // Идет ли синхронизация
+}
+
+// New создает gRPC-сервер с зарегистрированным ScheduleService. Административные методы принимают
+// токен adminToken в метаданных authorization в формате "Bearer <token>", без токена они отключены
+func New(app *handlers.App, adminToken string) *grpc.Server {
+	return newServer(&Server{app: app, syncing: app.Syncing}, adminToken)
+}
+
+func newServer(srv *Server, adminToken string) *grpc.Server {
+	s := grpc.NewServer(grpc.UnaryInterceptor(adminAuth(adminToken)))
+	pb.RegisterScheduleServiceServer(s, srv)
+	return s
+}
+
+// adminAuth проверяет токен администратора у вызовов adminMethods
+func adminAuth(adminToken string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !adminMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		if adminToken == "" {
+			return nil, status.Error(codes.Unauthenticated, "admin methods are disabled, ADMIN_TOKEN is not set")
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, value := range md.Get("authorization") {
+			key, ok := strings.CutPrefix(value, "Bearer ")
+			if ok && subtle.ConstantTimeCompare([]byte(key), []byte(adminToken)) == 1 {
+				return handler(ctx, req)
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid or missing admin token")
+	}
+}
+
+func (s *Server) ListGroups(ctx context.Context, _ *pb.ListGroupsRequest) (*pb.ListGroupsResponse, error) {
+	groups, err := repository.Groups(s.app.DB.WithContext(ctx))
+	if err != nil {
+		log.Printf("gRPC ListGroups failed: %v", err)
+		return nil, status.Error(codes.Internal, "failed to fetch groups")
+	}
+	return &pb.ListGroupsResponse{Groups: convertGroups(groups)}, nil
+}
+
+func (s *Server) GetGroupSchedule(ctx context.Context, req *pb.GetGroupScheduleRequest) (*pb.GetGroupScheduleResponse, error) {
+	items, err := s.schedule(ctx, req.GetUuid(), s.app.GroupSchedule)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetGroupScheduleResponse{Items: items}, nil
+}
+
+func (s *Server) GetTeacherSchedule(ctx context.Context, req *pb.GetTeacherScheduleRequest) (*pb.GetTeacherScheduleResponse, error) {
+	items, err := s.schedule(ctx, req.GetUuid(), s.app.TeacherSchedule)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetTeacherScheduleResponse{Items: items}, nil
+}
+
+func (s *Server) GetAudienceSchedule(ctx context.Context, req *pb.GetAudienceScheduleRequest) (*pb.GetAudienceScheduleResponse, error) {
+	items, err := s.schedule(ctx, req.GetUuid(), s.app.AudienceSchedule)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetAudienceScheduleResponse{Items: items}, nil
+}
+
+func (s *Server) schedule(ctx context.Context, uuid string, load func(context.Context, string) ([]models.ScheduleItem, error)) ([]*pb.ScheduleItem, error) {
+	if uuid == "" {
+		return nil, status.Error(codes.InvalidArgument, "uuid is required")
+	}
+
+	scheduleItems, err := load(ctx, uuid)
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if err != nil {
+		log.Printf("gRPC schedule request for %s failed: %v", uuid, err)
+		return nil, status.Error(codes.Internal, "failed to fetch schedule items")
+	}
+	return convertScheduleItems(scheduleItems), nil
+}
+
+func (s *Server) TriggerSync(context.Context, *pb.TriggerSyncRequest) (*pb.TriggerSyncResponse, error) {
+	if err := s.app.StartSync(); err != nil {
+		if errors.Is(err, handlers.ErrSyncInProgress) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.TriggerSyncResponse{}, nil
+}
+
+func (s *Server) StreamSyncProgress(_ *pb.StreamSyncProgressRequest, stream grpc.ServerStreamingServer[pb.StreamSyncProgressResponse]) error {
+	updates, unsubscribe := s.app.Hub.Subscribe()
+	defer unsubscribe()
+
+	// Подписка создана до проверки, поэтому обновления начавшейся синхронизации не теряются
+	if !s.syncing() {
+		return nil
+	}
+
+	ticker := time.NewTicker(syncCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+			if !s.syncing() {
+				return nil
+			}
+		case update := <-updates:
+			// Через тот же Hub идет прогресс выгрузок, поток передает только синхронизацию
+			if update.Type == handlers.ExportProgressType {
+				continue
+			}
+			if err := stream.Send(convertProgress(update)); err != nil {
+				return err
+			}
+			// Последнее обновление синхронизации завершает поток
+			if update.TotalItems > 0 && update.CompletedItems == update.TotalItems {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/handlers"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	pb "github.com/kosttiik/semesterly_backend/internal/pb/semesterlyv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, srv *Server) pb.ScheduleServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	server := newServer(srv, "secret")
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewScheduleServiceClient(conn)
+}
+
+func TestStreamSyncProgress(t *testing.T) {
+	hub := handlers.NewWebSocketHub()
+	go hub.Run()
+	client := newTestClient(t, &Server{app: &handlers.App{Hub: hub}, syncing: func() bool { return true }})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamSyncProgress(ctx, &pb.StreamSyncProgressRequest{})
+	require.NoError(t, err)
+
+	// Подписка создается в обработчике потока, ждем ее перед рассылкой
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		hub.BroadcastProgress(handlers.ProgressUpdate{CompletedItems: 1, TotalItems: 2, Percentage: 50})
+		hub.BroadcastProgress(handlers.ProgressUpdate{CompletedItems: 2, TotalItems: 2, Percentage: 100})
+	}()
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), first.GetCompletedItems())
+
+	last, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, last.GetPercentage())
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestStreamSyncProgressWithoutSync(t *testing.T) {
+	hub := handlers.NewWebSocketHub()
+	go hub.Run()
+	client := newTestClient(t, &Server{app: &handlers.App{Hub: hub}, syncing: func() bool { return false }})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamSyncProgress(ctx, &pb.StreamSyncProgressRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestStreamSyncProgressEndsWithSync(t *testing.T) {
+	syncCheckInterval = 10 * time.Millisecond
+	t.Cleanup(func() { syncCheckInterval = time.Second })
+
+	hub := handlers.NewWebSocketHub()
+	go hub.Run()
+	var syncing atomic.Bool
+	syncing.Store(true)
+	client := newTestClient(t, &Server{app: &handlers.App{Hub: hub}, syncing: syncing.Load})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamSyncProgress(ctx, &pb.StreamSyncProgressRequest{})
+	require.NoError(t, err)
+
+	// Синхронизация завершилась ошибкой, не отправив последнего обновления
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		hub.BroadcastProgress(handlers.ProgressUpdate{CompletedItems: 1, TotalItems: 2, Percentage: 50})
+		syncing.Store(false)
+	}()
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), first.GetCompletedItems())
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.NoError(t, ctx.Err())
+}
+
+func TestAdminAuth(t *testing.T) {
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	call := func(token, method string, auth ...string) error {
+		ctx := context.Background()
+		if len(auth) > 0 {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", auth[0]))
+		}
+		_, err := adminAuth(token)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	assert.NoError(t, call("secret", pb.ScheduleService_ListGroups_FullMethodName))
+	assert.NoError(t, call("secret", pb.ScheduleService_TriggerSync_FullMethodName, "Bearer secret"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("secret", pb.ScheduleService_TriggerSync_FullMethodName)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("secret", pb.ScheduleService_TriggerSync_FullMethodName, "Bearer wrong")))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("", pb.ScheduleService_TriggerSync_FullMethodName, "Bearer ")))
+}
+
+func TestGetScheduleRequiresUUID(t *testing.T) {
+	client := newTestClient(t, &Server{app: &handlers.App{}})
+
+	_, err := client.GetGroupSchedule(context.Background(), &pb.GetGroupScheduleRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestConvertScheduleItems(t *testing.T) {
+	department := "dep"
+	items := convertScheduleItems([]models.ScheduleItem{{
+		ID:        7,
+		Day:       2,
+		Time:      3,
+		Week:      models.WeekDenominator,
+		Audiences: []models.Audience{{UUID: "a1", DepartmentUID: &department}, {UUID: "a2"}},
+	}})
+
+	require.Len(t, items, 1)
+	assert.Equal(t, uint64(7), items[0].GetId())
+	assert.Equal(t, pb.Week_WEEK_DENOMINATOR, items[0].GetWeek())
+	assert.Equal(t, "dep", items[0].GetAudiences()[0].GetDepartmentUid())
+	assert.Nil(t, items[0].GetAudiences()[1].DepartmentUid)
+	assert.Empty(t, items[0].GetGroups())
+}
@@ -0,0 +1,487 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// errDateTaken - на дату уже есть праздник или перенос, у каждой даты может быть только одна запись
+var errDateTaken = errors.New("date is already taken")
+
+// ListSemestersHandler отправляет JSON со списком семестров
+// @Summary Список семестров
+// @Tags AdminCalendar
+// @Produce json
+// @Security AdminToken
+// @Success 200 {array} models.Semester "Список семестров"
+// @Failure 500 {object} map[string]string "error: Failed to fetch entries"
+// @Router /admin/semesters [get]
+func (a *App) ListSemestersHandler(c echo.Context) error {
+	return listEntries[models.Semester](a, c, "start_date")
+}
+
+// CreateSemesterHandler добавляет семестр
+// @Summary Добавление семестра
+// @Description Первая неделя семестра считается числителем. Даты передаются в формате YYYY-MM-DD, семестры не должны пересекаться
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param semester body models.Semester true "Семестр"
+// @Success 201 {object} models.Semester "Созданный семестр"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/semesters [post]
+func (a *App) CreateSemesterHandler(c echo.Context) error {
+	return createEntry(a, c, a.semesterValidator(0))
+}
+
+// UpdateSemesterHandler изменяет семестр
+// @Summary Изменение семестра
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "ID семестра"
+// @Param semester body models.Semester true "Семестр"
+// @Success 200 {object} models.Semester "Измененный семестр"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/semesters/{id} [put]
+func (a *App) UpdateSemesterHandler(c echo.Context) error {
+	// Некорректный id отклоняет updateEntry
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	return updateEntry(a, c, a.semesterValidator(uint(id)))
+}
+
+// DeleteSemesterHandler удаляет семестр
+// @Summary Удаление семестра
+// @Tags AdminCalendar
+// @Security AdminToken
+// @Param id path int true "ID семестра"
+// @Success 204 "Семестр удален"
+// @Failure 400 {object} map[string]string "error: Invalid id"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 500 {object} map[string]string "error: Failed to delete entry"
+// @Router /admin/semesters/{id} [delete]
+func (a *App) DeleteSemesterHandler(c echo.Context) error {
+	return deleteEntry[models.Semester](a, c)
+}
+
+// ListHolidaysHandler отправляет JSON со списком праздничных дней
+// @Summary Список праздничных дней
+// @Tags AdminCalendar
+// @Produce json
+// @Security AdminToken
+// @Success 200 {array} models.Holiday "Список праздничных дней"
+// @Failure 500 {object} map[string]string "error: Failed to fetch entries"
+// @Router /admin/holidays [get]
+func (a *App) ListHolidaysHandler(c echo.Context) error {
+	return listEntries[models.Holiday](a, c, "date")
+}
+
+// CreateHolidayHandler добавляет праздничный день
+// @Summary Добавление праздничного дня
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param holiday body models.Holiday true "Праздничный день"
+// @Success 201 {object} models.Holiday "Созданный праздничный день"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 409 {object} map[string]string "error: Date is already taken"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/holidays [post]
+func (a *App) CreateHolidayHandler(c echo.Context) error {
+	return createEntry(a, c, a.holidayValidator(0))
+}
+
+// UpdateHolidayHandler изменяет праздничный день
+// @Summary Изменение праздничного дня
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "ID праздничного дня"
+// @Param holiday body models.Holiday true "Праздничный день"
+// @Success 200 {object} models.Holiday "Измененный праздничный день"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 409 {object} map[string]string "error: Date is already taken"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/holidays/{id} [put]
+func (a *App) UpdateHolidayHandler(c echo.Context) error {
+	// Некорректный id отклоняет updateEntry
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	return updateEntry(a, c, a.holidayValidator(uint(id)))
+}
+
+// DeleteHolidayHandler удаляет праздничный день
+// @Summary Удаление праздничного дня
+// @Tags AdminCalendar
+// @Security AdminToken
+// @Param id path int true "ID праздничного дня"
+// @Success 204 "Праздничный день удален"
+// @Failure 400 {object} map[string]string "error: Invalid id"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 500 {object} map[string]string "error: Failed to delete entry"
+// @Router /admin/holidays/{id} [delete]
+func (a *App) DeleteHolidayHandler(c echo.Context) error {
+	return deleteEntry[models.Holiday](a, c)
+}
+
+// ListExamSessionsHandler отправляет JSON со списком экзаменационных сессий
+// @Summary Список экзаменационных сессий
+// @Tags AdminCalendar
+// @Produce json
+// @Security AdminToken
+// @Success 200 {array} models.ExamSession "Список сессий"
+// @Failure 500 {object} map[string]string "error: Failed to fetch entries"
+// @Router /admin/exam-sessions [get]
+func (a *App) ListExamSessionsHandler(c echo.Context) error {
+	return listEntries[models.ExamSession](a, c, "start_date")
+}
+
+// CreateExamSessionHandler добавляет экзаменационную сессию
+// @Summary Добавление экзаменационной сессии
+// @Description В период сессии обычные занятия не проводятся
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param session body models.ExamSession true "Экзаменационная сессия"
+// @Success 201 {object} models.ExamSession "Созданная сессия"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/exam-sessions [post]
+func (a *App) CreateExamSessionHandler(c echo.Context) error {
+	return createEntry(a, c, validateExamSession)
+}
+
+// UpdateExamSessionHandler изменяет экзаменационную сессию
+// @Summary Изменение экзаменационной сессии
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "ID сессии"
+// @Param session body models.ExamSession true "Экзаменационная сессия"
+// @Success 200 {object} models.ExamSession "Измененная сессия"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/exam-sessions/{id} [put]
+func (a *App) UpdateExamSessionHandler(c echo.Context) error {
+	return updateEntry(a, c, validateExamSession)
+}
+
+// DeleteExamSessionHandler удаляет экзаменационную сессию
+// @Summary Удаление экзаменационной сессии
+// @Tags AdminCalendar
+// @Security AdminToken
+// @Param id path int true "ID сессии"
+// @Success 204 "Сессия удалена"
+// @Failure 400 {object} map[string]string "error: Invalid id"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 500 {object} map[string]string "error: Failed to delete entry"
+// @Router /admin/exam-sessions/{id} [delete]
+func (a *App) DeleteExamSessionHandler(c echo.Context) error {
+	return deleteEntry[models.ExamSession](a, c)
+}
+
+// ListDayTransfersHandler отправляет JSON со списком переносов дней
+// @Summary Список переносов дней
+// @Tags AdminCalendar
+// @Produce json
+// @Security AdminToken
+// @Success 200 {array} models.DayTransfer "Список переносов"
+// @Failure 500 {object} map[string]string "error: Failed to fetch entries"
+// @Router /admin/day-transfers [get]
+func (a *App) ListDayTransfersHandler(c echo.Context) error {
+	return listEntries[models.DayTransfer](a, c, "date")
+}
+
+// CreateDayTransferHandler добавляет перенос дня
+// @Summary Добавление переноса дня
+// @Description Например, суббота, в которую занятия проходят по расписанию среды: date - дата субботы, weekday = 3
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param transfer body models.DayTransfer true "Перенос дня"
+// @Success 201 {object} models.DayTransfer "Созданный перенос"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 409 {object} map[string]string "error: Date is already taken"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/day-transfers [post]
+func (a *App) CreateDayTransferHandler(c echo.Context) error {
+	return createEntry(a, c, a.dayTransferValidator(0))
+}
+
+// UpdateDayTransferHandler изменяет перенос дня
+// @Summary Изменение переноса дня
+// @Tags AdminCalendar
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "ID переноса"
+// @Param transfer body models.DayTransfer true "Перенос дня"
+// @Success 200 {object} models.DayTransfer "Измененный перенос"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 409 {object} map[string]string "error: Date is already taken"
+// @Failure 500 {object} map[string]string "error: Failed to save entry"
+// @Router /admin/day-transfers/{id} [put]
+func (a *App) UpdateDayTransferHandler(c echo.Context) error {
+	// Некорректный id отклоняет updateEntry
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	return updateEntry(a, c, a.dayTransferValidator(uint(id)))
+}
+
+// DeleteDayTransferHandler удаляет перенос дня
+// @Summary Удаление переноса дня
+// @Tags AdminCalendar
+// @Security AdminToken
+// @Param id path int true "ID переноса"
+// @Success 204 "Перенос удален"
+// @Failure 400 {object} map[string]string "error: Invalid id"
+// @Failure 404 {object} map[string]string "error: Entry not found"
+// @Failure 500 {object} map[string]string "error: Failed to delete entry"
+// @Router /admin/day-transfers/{id} [delete]
+func (a *App) DeleteDayTransferHandler(c echo.Context) error {
+	return deleteEntry[models.DayTransfer](a, c)
+}
+
+// ReloadCalendar перечитывает семестры, праздники, сессии и переносы из базы данных
+func (a *App) ReloadCalendar() error {
+	var semesters []models.Semester
+	var holidays []models.Holiday
+	var sessions []models.ExamSession
+	var transfers []models.DayTransfer
+
+	if err := a.DB.Find(&semesters).Error; err != nil {
+		return fmt.Errorf("failed to fetch semesters: %w", err)
+	}
+	if err := a.DB.Find(&holidays).Error; err != nil {
+		return fmt.Errorf("failed to fetch holidays: %w", err)
+	}
+	if err := a.DB.Find(&sessions).Error; err != nil {
+		return fmt.Errorf("failed to fetch exam sessions: %w", err)
+	}
+	if err := a.DB.Find(&transfers).Error; err != nil {
+		return fmt.Errorf("failed to fetch day transfers: %w", err)
+	}
+
+	data, err := calendar.FromModels(semesters, holidays, sessions, transfers)
+	if err != nil {
+		return err
+	}
+	a.Calendar.Update(data)
+	return nil
+}
+
+func listEntries[T any](a *App, c echo.Context, order string) error {
+	entries := make([]T, 0)
+	if err := a.DB.Order(order).Find(&entries).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch entries"})
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+func createEntry[T any](a *App, c echo.Context, validate func(*T) error) error {
+	var entry T
+	if err := c.Bind(&entry); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if err := validate(&entry); err != nil {
+		return validationFailed(c, err)
+	}
+
+	if err := a.DB.Omit("id").Create(&entry).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save entry"})
+	}
+
+	a.reloadCalendarAfterChange()
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func updateEntry[T any](a *App, c echo.Context, validate func(*T) error) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid id"})
+	}
+
+	var entry T
+	if err := c.Bind(&entry); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if err := validate(&entry); err != nil {
+		return validationFailed(c, err)
+	}
+
+	result := a.DB.Model(new(T)).
+		Where("id = ?", id).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(&entry)
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save entry"})
+	}
+	if result.RowsAffected == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Entry not found"})
+	}
+
+	var updated T
+	if err := a.DB.First(&updated, id).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch entry"})
+	}
+
+	a.reloadCalendarAfterChange()
+	return c.JSON(http.StatusOK, updated)
+}
+
+func deleteEntry[T any](a *App, c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid id"})
+	}
+
+	result := a.DB.Delete(new(T), id)
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete entry"})
+	}
+	if result.RowsAffected == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Entry not found"})
+	}
+
+	a.reloadCalendarAfterChange()
+	return c.NoContent(http.StatusNoContent)
+}
+
+// validationFailed отвечает 409, если дата записи уже занята, и 400 для остальных ошибок проверки
+func validationFailed(c echo.Context, err error) error {
+	if errors.Is(err, errDateTaken) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
+
+// reloadCalendarAfterChange перечитывает календарь и увеличивает версии всех данных:
+// праздники, семестры и переносы влияют на ICS и раскрытые по датам расписания
+func (a *App) reloadCalendarAfterChange() {
+	if err := a.ReloadCalendar(); err != nil {
+		log.Printf("Failed to reload calendar: %v", err)
+	}
+	if err := repository.BumpAllDataVersions(a.DB); err != nil {
+		log.Printf("Failed to bump data versions: %v", err)
+	}
+}
+
+func validateSemester(s *models.Semester) error {
+	return validatePeriod(s.StartDate, s.EndDate)
+}
+
+// semesterValidator проверяет даты семестра и то, что он не пересекается с другими семестрами.
+// id - семестр, который изменяется и с которым не нужно сравнивать, 0 при создании
+func (a *App) semesterValidator(id uint) func(*models.Semester) error {
+	return func(s *models.Semester) error {
+		if err := validateSemester(s); err != nil {
+			return err
+		}
+
+		// Даты хранятся в формате YYYY-MM-DD, поэтому сравниваются как строки
+		var other models.Semester
+		err := a.DB.Where("id <> ? AND start_date <= ? AND end_date >= ?", id, s.EndDate, s.StartDate).
+			Order("start_date").
+			Take(&other).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return errors.New("failed to check semesters")
+		}
+		return fmt.Errorf("semester overlaps with %q (%s - %s)", other.Title, other.StartDate, other.EndDate)
+	}
+}
+
+// holidayValidator проверяет праздничный день и то, что на его дату нет другого праздника
+func (a *App) holidayValidator(id uint) func(*models.Holiday) error {
+	return func(h *models.Holiday) error {
+		if err := validateHoliday(h); err != nil {
+			return err
+		}
+		return dateFree[models.Holiday](a, id, h.Date)
+	}
+}
+
+// dayTransferValidator проверяет перенос дня и то, что на его дату нет другого переноса
+func (a *App) dayTransferValidator(id uint) func(*models.DayTransfer) error {
+	return func(t *models.DayTransfer) error {
+		if err := validateDayTransfer(t); err != nil {
+			return err
+		}
+		return dateFree[models.DayTransfer](a, id, t.Date)
+	}
+}
+
+// dateFree проверяет, что на дату нет записи, кроме изменяемой записи id
+func dateFree[T any](a *App, id uint, date string) error {
+	var count int64
+	if err := a.DB.Model(new(T)).Where("id <> ? AND date = ?", id, date).Count(&count).Error; err != nil {
+		return errors.New("failed to check date")
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s", errDateTaken, date)
+	}
+	return nil
+}
+
+func validateExamSession(s *models.ExamSession) error {
+	return validatePeriod(s.StartDate, s.EndDate)
+}
+
+func validateHoliday(h *models.Holiday) error {
+	if _, err := time.Parse(calendar.DateLayout, h.Date); err != nil {
+		return errors.New("date must be in YYYY-MM-DD format")
+	}
+	return nil
+}
+
+func validateDayTransfer(t *models.DayTransfer) error {
+	if _, err := time.Parse(calendar.DateLayout, t.Date); err != nil {
+		return errors.New("date must be in YYYY-MM-DD format")
+	}
+	if t.Weekday < 1 || t.Weekday > 6 {
+		return errors.New("weekday must be an integer from 1 to 6")
+	}
+	if t.Parity != "" && t.Parity != models.WeekNumerator && t.Parity != models.WeekDenominator {
+		return errors.New("parity must be empty, ch or zn")
+	}
+	return nil
+}
+
+func validatePeriod(startStr, endStr string) error {
+	start, err := time.Parse(calendar.DateLayout, startStr)
+	if err != nil {
+		return errors.New("startDate must be in YYYY-MM-DD format")
+	}
+	end, err := time.Parse(calendar.DateLayout, endStr)
+	if err != nil {
+		return errors.New("endDate must be in YYYY-MM-DD format")
+	}
+	if end.Before(start) {
+		return errors.New("endDate must not be before startDate")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemesterValidator(t *testing.T) {
+	a := &App{DB: newTestDB(t, testConnector{rows: map[string]testRows{
+		"semesters": {
+			columns: []string{"id", "title", "start_date", "end_date"},
+			values:  [][]driver.Value{{int64(1), "Осенний семестр", "2026-09-01", "2026-12-31"}},
+		},
+	}})}
+
+	err := a.semesterValidator(0)(&models.Semester{StartDate: "2026-12-01", EndDate: "2027-05-31"})
+	assert.EqualError(t, err, `semester overlaps with "Осенний семестр" (2026-09-01 - 2026-12-31)`)
+
+	err = a.semesterValidator(0)(&models.Semester{StartDate: "2027-02-01", EndDate: "2027-01-31"})
+	assert.EqualError(t, err, "endDate must not be before startDate")
+
+	a.DB = newTestDB(t, testConnector{})
+	assert.NoError(t, a.semesterValidator(1)(&models.Semester{StartDate: "2027-02-01", EndDate: "2027-05-31"}))
+}
+
+func TestDeleteEntryNotFound(t *testing.T) {
+	deleteHoliday := func(a *App) int {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodDelete, "/api/v1/admin/holidays/7", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		require.NoError(t, a.DeleteHolidayHandler(c))
+		return rec.Code
+	}
+
+	cal := calendar.New(nil, calendar.Data{})
+	assert.Equal(t, http.StatusNotFound, deleteHoliday(&App{DB: newTestDB(t, testConnector{}), Calendar: cal}))
+	assert.Equal(t, http.StatusNoContent, deleteHoliday(&App{DB: newTestDB(t, testConnector{affected: 1}), Calendar: cal}))
+}
+
+func TestCreateHolidayDateTaken(t *testing.T) {
+	createHoliday := func(a *App) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/holidays",
+			strings.NewReader(`{"title":"День народного единства","date":"2026-11-04"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, a.CreateHolidayHandler(echo.New().NewContext(req, rec)))
+		return rec
+	}
+
+	cal := calendar.New(nil, calendar.Data{})
+	taken := testConnector{rows: map[string]testRows{
+		"count": {columns: []string{"count"}, values: [][]driver.Value{{int64(1)}}},
+	}}
+	rec := createHoliday(&App{DB: newTestDB(t, taken), Calendar: cal})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "date is already taken: 2026-11-04")
+
+	rec = createHoliday(&App{DB: newTestDB(t, testConnector{}), Calendar: cal})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
@@ -1,8 +1,20 @@
 package handlers
 
-import "gorm.io/gorm"
+import (
+	"sync/atomic"
+
+	"github.com/kosttiik/semesterly_backend/internal/cache"
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+)
 
 type App struct {
-	DB  *gorm.DB
-	Hub *WebSocketHub
+	DB       *gorm.DB
+	Hub      *WebSocketHub
+	Calendar *calendar.Calendar
+	Cache    *cache.Cache[[]models.ScheduleItem]
+
+	syncing atomic.Bool // Выполняется синхронизация с lks
+	exports exportJobs  // Задания выгрузки архивов
 }
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRequest - тело запроса на создание подписки на календарь
+type CalendarFeedRequest struct {
+	Kind string `json:"kind"` // group, teacher, audience или timetable
+	Ref  string `json:"ref"`  // UUID группы, преподавателя, аудитории или ID личного расписания
+}
+
+// CalendarFeedCreated - созданная подписка со ссылками для календарных приложений
+type CalendarFeedCreated struct {
+	models.CalendarFeed
+	URL       string `json:"url"`
+	WebcalURL string `json:"webcalUrl"`
+}
+
+// feedTargets проверяют, что расписание, на которое оформляется подписка, существует
+var feedTargets = map[string]func(db *gorm.DB, ref string) error{
+	kindGroup: func(db *gorm.DB, ref string) error {
+		return db.Where("uuid = ?", ref).First(&models.Group{}).Error
+	},
+	kindTeacher: func(db *gorm.DB, ref string) error {
+		return db.Where("uuid = ?", ref).First(&models.Teacher{}).Error
+	},
+	kindAudience: func(db *gorm.DB, ref string) error {
+		return db.Where("uuid = ?", ref).First(&models.Audience{}).Error
+	},
+	kindTimetable: func(db *gorm.DB, ref string) error {
+		_, err := repository.PersonalTimetable(db, ref)
+		return err
+	},
+}
+
+// CreateCalendarFeedHandler создает подписку на расписание в формате iCalendar
+// @Summary Создание подписки на календарь
+// @Description Возвращает секретную ссылку (в том числе webcal://), которую календарные приложения периодически опрашивают.
+// @Description Идентификаторы событий не меняются при повторной загрузке расписания из lks, поэтому приложения обновляют события, а не дублируют их
+// @Tags ICS
+// @Accept json
+// @Produce json
+// @Param feed body CalendarFeedRequest true "Расписание для подписки"
+// @Success 201 {object} CalendarFeedCreated "Созданная подписка"
+// @Failure 400 {object} map[string]string "error: kind must be one of group, teacher, audience, timetable"
+// @Failure 404 {object} map[string]string "error: Schedule not found"
+// @Failure 500 {object} map[string]string "error: Failed to save feed"
+// @Router /feeds [post]
+func (a *App) CreateCalendarFeedHandler(c echo.Context) error {
+	var req CalendarFeedRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	exists, ok := feedTargets[req.Kind]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "kind must be one of group, teacher, audience, timetable"})
+	}
+	if err := exists(a.DB, req.Ref); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Schedule not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save feed"})
+	}
+
+	token, err := randomToken(16)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save feed"})
+	}
+
+	feed := models.CalendarFeed{Token: token, Kind: req.Kind, Ref: req.Ref}
+	if err := a.DB.Create(&feed).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save feed"})
+	}
+
+	host := c.Request().Host
+	path := "/api/v1/feeds/" + token + ".ics"
+	return c.JSON(http.StatusCreated, CalendarFeedCreated{
+		CalendarFeed: feed,
+		URL:          c.Scheme() + "://" + host + path,
+		WebcalURL:    "webcal://" + host + path,
+	})
+}
+
+// GetCalendarFeedHandler отправляет календарь подписки
+// @Summary Календарь подписки
+// @Description Возвращает актуальное расписание подписки в формате iCalendar
+// @Tags ICS
+// @Produce text/calendar
+// @Param token path string true "Токен подписки, можно с расширением .ics"
+// @Success 200 {string} string "Календарь iCalendar"
+// @Failure 404 {object} map[string]string "error: Feed not found"
+// @Failure 500 {object} map[string]string "error: Failed to build calendar"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /feeds/{token} [get]
+func (a *App) GetCalendarFeedHandler(c echo.Context) error {
+	feed, err := repository.CalendarFeed(a.DB, strings.TrimSuffix(c.Param("token"), ".ics"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Feed not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch feed"})
+	}
+
+	if !a.Calendar.Configured() {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Semester calendar is not configured"})
+	}
+
+	body, err := a.scheduleCalendar(feed.Kind, feed.Ref)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Feed not found"})
+	}
+	if err != nil {
+		log.Printf("Failed to build calendar for %s %s: %v", feed.Kind, feed.Ref, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to build calendar"})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="schedule.ics"`)
+	return c.Blob(http.StatusOK, calendarContentType, body)
+}
+
+// DeleteCalendarFeedHandler отзывает подписку на календарь
+// @Summary Удаление подписки на календарь
+// @Description Токен подписки служит и доступом к ней, поэтому удалить подписку может любой, кто знает ссылку
+// @Tags ICS
+// @Param token path string true "Токен подписки"
+// @Success 204 "Подписка удалена"
+// @Failure 404 {object} map[string]string "error: Feed not found"
+// @Failure 500 {object} map[string]string "error: Failed to delete feed"
+// @Router /feeds/{token} [delete]
+func (a *App) DeleteCalendarFeedHandler(c echo.Context) error {
+	result := a.DB.Where("token = ?", strings.TrimSuffix(c.Param("token"), ".ics")).Delete(&models.CalendarFeed{})
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete feed"})
+	}
+	if result.RowsAffected == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Feed not found"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Виды различий в слоте
+const (
+	slotOnlyA     = "onlyA"     // Занятие есть только у первой группы
+	slotOnlyB     = "onlyB"     // Занятие есть только у второй группы
+	slotDifferent = "different" // У обеих групп разные занятия
+)
+
+// ScheduleComparison - результат сравнения расписаний двух групп
+type ScheduleComparison struct {
+	A      models.Group          `json:"a"`
+	B      models.Group          `json:"b"`
+	Shared []models.ScheduleItem `json:"shared"` // Занятия, совпадающие у обеих групп
+	OnlyA  []models.ScheduleItem `json:"onlyA"`
+	OnlyB  []models.ScheduleItem `json:"onlyB"`
+	Slots  []SlotDifference      `json:"slots"`
+}
+
+// SlotDifference описывает пару, в которую у групп разные занятия
+type SlotDifference struct {
+	Day    int                   `json:"day"`
+	Slot   int                   `json:"slot"`
+	Week   string                `json:"week"`
+	Status string                `json:"status"` // onlyA, onlyB или different
+	A      []models.ScheduleItem `json:"a"`
+	B      []models.ScheduleItem `json:"b"`
+}
+
+// CompareHandler сравнивает расписания двух групп
+// @Summary Сравнение расписаний групп
+// @Description Возвращает общие занятия двух групп, занятия только одной из групп и различия по парам.
+// @Description Занятия совпадают, если совпадают время, четность, поток, дисциплины, преподаватели и аудитории. Группы задаются UUID или названием
+// @Tags Compare
+// @Produce json
+// @Param a query string true "UUID или название первой группы"
+// @Param b query string true "UUID или название второй группы"
+// @Success 200 {object} ScheduleComparison "Результат сравнения"
+// @Failure 400 {object} map[string]string "error: Both a and b are required"
+// @Failure 404 {object} map[string]string "error: Group not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /compare [get]
+func (a *App) CompareHandler(c echo.Context) error {
+	refA, refB := c.QueryParam("a"), c.QueryParam("b")
+	if refA == "" || refB == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Both a and b are required"})
+	}
+
+	groupA, itemsA, err := a.groupWithSchedule(refA)
+	if err != nil {
+		return a.compareError(c, refA, err)
+	}
+	groupB, itemsB, err := a.groupWithSchedule(refB)
+	if err != nil {
+		return a.compareError(c, refB, err)
+	}
+
+	comparison := compareGroupSchedules(itemsA, itemsB)
+	comparison.A, comparison.B = groupA, groupB
+
+	return c.JSON(http.StatusOK, comparison)
+}
+
+func (a *App) groupWithSchedule(ref string) (models.Group, []models.ScheduleItem, error) {
+	group, err := repository.FindGroup(a.DB, ref)
+	if err != nil {
+		return group, nil, err
+	}
+	scheduleItems, err := a.loadSchedule(kindGroup, group.UUID)
+	return group, scheduleItems, err
+}
+
+func (a *App) compareError(c echo.Context, ref string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Group %s not found", ref)})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+}
+
+// lessonKey - ключ занятия без учета групп: время и поток как в slotKey, дисциплины, преподаватели и аудитории
+func lessonKey(item models.ScheduleItem) string {
+	parts := make([]string, 0, len(item.Disciplines)+len(item.Teachers)+len(item.Audiences))
+	for _, d := range item.Disciplines {
+		parts = append(parts, "d:"+d.FullName+"/"+d.ActType)
+	}
+	for _, t := range item.Teachers {
+		parts = append(parts, "t:"+t.UUID)
+	}
+	for _, aud := range item.Audiences {
+		parts = append(parts, "a:"+aud.UUID)
+	}
+	sort.Strings(parts)
+	return slotKey(item) + "|" + strings.Join(parts, ",")
+}
+
+// compareGroupSchedules сравнивает занятия двух групп
+func compareGroupSchedules(itemsA, itemsB []models.ScheduleItem) ScheduleComparison {
+	keysA := lessonKeys(itemsA)
+	keysB := lessonKeys(itemsB)
+
+	result := ScheduleComparison{
+		Shared: []models.ScheduleItem{},
+		OnlyA:  []models.ScheduleItem{},
+		OnlyB:  []models.ScheduleItem{},
+		Slots:  []SlotDifference{},
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range sortedLessons(itemsA) {
+		key := lessonKey(item)
+		if keysB[key] {
+			if !seen[key] {
+				seen[key] = true
+				result.Shared = append(result.Shared, item)
+			}
+			continue
+		}
+		result.OnlyA = append(result.OnlyA, item)
+	}
+	for _, item := range sortedLessons(itemsB) {
+		if !keysA[lessonKey(item)] {
+			result.OnlyB = append(result.OnlyB, item)
+		}
+	}
+
+	result.Slots = slotDifferences(itemsA, itemsB)
+	return result
+}
+
+func lessonKeys(scheduleItems []models.ScheduleItem) map[string]bool {
+	keys := make(map[string]bool, len(scheduleItems))
+	for _, item := range scheduleItems {
+		keys[lessonKey(item)] = true
+	}
+	return keys
+}
+
+func sortedLessons(scheduleItems []models.ScheduleItem) []models.ScheduleItem {
+	sorted := append([]models.ScheduleItem(nil), scheduleItems...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Day != sorted[j].Day {
+			return sorted[i].Day < sorted[j].Day
+		}
+		if sorted[i].Time != sorted[j].Time {
+			return sorted[i].Time < sorted[j].Time
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// slotDifferences находит пары, в которые у групп разный набор занятий.
+// Если различие одинаково на числителе и знаменателе, оно возвращается одной записью с week = all
+func slotDifferences(itemsA, itemsB []models.ScheduleItem) []SlotDifference {
+	type slot struct{ day, time int }
+	type weekItems map[string][]models.ScheduleItem
+
+	collect := func(scheduleItems []models.ScheduleItem) map[slot]weekItems {
+		slots := make(map[slot]weekItems)
+		for _, item := range sortedLessons(scheduleItems) {
+			s := slot{item.Day, item.Time}
+			if slots[s] == nil {
+				slots[s] = make(weekItems)
+			}
+			for _, week := range itemWeeks(item.Week) {
+				slots[s][week] = append(slots[s][week], item)
+			}
+		}
+		return slots
+	}
+	slotsA, slotsB := collect(itemsA), collect(itemsB)
+
+	all := make(map[slot]bool)
+	for s := range slotsA {
+		all[s] = true
+	}
+	for s := range slotsB {
+		all[s] = true
+	}
+	ordered := make([]slot, 0, len(all))
+	for s := range all {
+		ordered = append(ordered, s)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].day != ordered[j].day {
+			return ordered[i].day < ordered[j].day
+		}
+		return ordered[i].time < ordered[j].time
+	})
+
+	var differences []SlotDifference
+	for _, s := range ordered {
+		var perWeek []SlotDifference
+		for _, week := range []string{models.WeekNumerator, models.WeekDenominator} {
+			a, b := slotsA[s][week], slotsB[s][week]
+			if sameLessons(a, b) {
+				continue
+			}
+			perWeek = append(perWeek, SlotDifference{
+				Day:    s.day,
+				Slot:   s.time,
+				Week:   week,
+				Status: slotStatus(a, b),
+				A:      nonNil(a),
+				B:      nonNil(b),
+			})
+		}
+
+		if len(perWeek) == 2 && sameLessons(perWeek[0].A, perWeek[1].A) && sameLessons(perWeek[0].B, perWeek[1].B) {
+			perWeek[0].Week = models.WeekAll
+			perWeek = perWeek[:1]
+		}
+		differences = append(differences, perWeek...)
+	}
+
+	if differences == nil {
+		differences = []SlotDifference{}
+	}
+	return differences
+}
+
+func sameLessons(a, b []models.ScheduleItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keysA := lessonKeys(a)
+	for _, item := range b {
+		if !keysA[lessonKey(item)] {
+			return false
+		}
+	}
+	return true
+}
+
+func slotStatus(a, b []models.ScheduleItem) string {
+	switch {
+	case len(b) == 0:
+		return slotOnlyA
+	case len(a) == 0:
+		return slotOnlyB
+	default:
+		return slotDifferent
+	}
+}
+
+func nonNil(scheduleItems []models.ScheduleItem) []models.ScheduleItem {
+	if scheduleItems == nil {
+		return []models.ScheduleItem{}
+	}
+	return scheduleItems
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareGroupSchedules(t *testing.T) {
+	lecture := func(id uint, group string) models.ScheduleItem {
+		return models.ScheduleItem{
+			ID: id, Day: 1, Time: 1, Week: models.WeekAll, Stream: "s1",
+			Groups:      []models.Group{{UUID: group}},
+			Teachers:    []models.Teacher{{UUID: "t1"}},
+			Disciplines: []models.Discipline{{FullName: "Матанализ", ActType: "lecture"}},
+		}
+	}
+	seminarA := models.ScheduleItem{ID: 10, Day: 2, Time: 3, Week: models.WeekNumerator,
+		Disciplines: []models.Discipline{{FullName: "Физика", ActType: "seminar"}}}
+	seminarB := models.ScheduleItem{ID: 11, Day: 2, Time: 3, Week: models.WeekNumerator,
+		Disciplines: []models.Discipline{{FullName: "Химия", ActType: "seminar"}}}
+	labB := models.ScheduleItem{ID: 12, Day: 3, Time: 4, Week: models.WeekAll}
+
+	result := compareGroupSchedules(
+		[]models.ScheduleItem{lecture(1, "a"), seminarA},
+		[]models.ScheduleItem{lecture(2, "b"), seminarB, labB},
+	)
+
+	// Лекция совпадает по содержанию, хотя это разные записи с разными группами
+	require.Len(t, result.Shared, 1)
+	assert.Equal(t, uint(1), result.Shared[0].ID)
+	require.Len(t, result.OnlyA, 1)
+	assert.Equal(t, uint(10), result.OnlyA[0].ID)
+	assert.Len(t, result.OnlyB, 2)
+
+	require.Len(t, result.Slots, 2)
+	assert.Equal(t, SlotDifference{
+		Day: 2, Slot: 3, Week: models.WeekNumerator, Status: slotDifferent,
+		A: []models.ScheduleItem{seminarA}, B: []models.ScheduleItem{seminarB},
+	}, result.Slots[0])
+	assert.Equal(t, models.WeekAll, result.Slots[1].Week) // Одинаковое различие на обеих неделях
+	assert.Equal(t, slotOnlyB, result.Slots[1].Status)
+	assert.Empty(t, result.Slots[1].A)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/export"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const csvBatchSize = 500
+
+// csvDelimiters - допустимые значения параметра delimiter
+var csvDelimiters = map[string]rune{
+	"":          ',',
+	",":         ',',
+	"comma":     ',',
+	";":         ';',
+	"semicolon": ';',
+	"tab":       '\t',
+	"\t":        '\t',
+	"|":         '|',
+}
+
+// csvFilter - выборка занятий для CSV по параметрам запроса
+type csvFilter struct {
+	kind string // Пустой вид означает все расписание
+	id   string
+	day  int
+	week string
+}
+
+// ExportCSVHandler передает расписание в формате CSV по мере выборки из базы данных
+// @Summary Экспорт расписания в CSV
+// @Description Передает занятия потоково, не сохраняя файл на сервере. Без group, teacher, audience и timetable выгружается все расписание.
+// @Description Фильтр week=ch или week=zn оставляет также занятия каждую неделю. Все дисциплины занятия перечисляются через точку с запятой.
+// @Description Доступные столбцы: type, day, time, week, date, startTime, endTime, stream, disciplineAbbr, disciplineActType, disciplineFullName, disciplineShortName, teachers, audiences, groups
+// @Tags Export
+// @Produce text/csv
+// @Param group query string false "UUID группы"
+// @Param teacher query string false "UUID преподавателя"
+// @Param audience query string false "UUID аудитории"
+// @Param timetable query string false "ID личного расписания"
+// @Param day query int false "День недели (1-6)"
+// @Param week query string false "Неделя: all, ch или zn"
+// @Param exams query bool false "Добавить экзамены строками с type = exam"
+// @Param columns query string false "Столбцы через запятую, по умолчанию все"
+// @Param delimiter query string false "Разделитель: comma (по умолчанию), semicolon, tab или |"
+// @Param encoding query string false "Кодировка: utf-8 (по умолчанию), utf-8-bom или cp1251"
+// @Success 200 {file} file "Файл CSV"
+// @Failure 400 {object} map[string]string "error: Invalid export parameters"
+// @Failure 404 {object} map[string]string "error: Timetable not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /export/schedule.csv [get]
+func (a *App) ExportCSVHandler(c echo.Context) error {
+	filter, err := parseCSVFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	delimiter, ok := csvDelimiters[c.QueryParam("delimiter")]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "delimiter must be one of comma, semicolon, tab, |"})
+	}
+	var columns []string
+	if value := c.QueryParam("columns"); value != "" {
+		for _, column := range strings.Split(value, ",") {
+			columns = append(columns, strings.TrimSpace(column))
+		}
+	}
+	withExams, _ := strconv.ParseBool(c.QueryParam("exams"))
+
+	// Выборку конкретного расписания проверяем до начала передачи, чтобы вернуть код ошибки
+	var scheduleItems []models.ScheduleItem
+	if filter.kind != "" {
+		if scheduleItems, err = a.loadSchedule(filter.kind, filter.id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "Timetable not found"})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+		}
+	}
+
+	res := c.Response()
+	writer, err := export.NewCSVWriter(res, export.CSVOptions{
+		Delimiter: delimiter,
+		Encoding:  c.QueryParam("encoding"),
+		Columns:   columns,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	charset := "utf-8"
+	if c.QueryParam("encoding") == export.EncodingCP1251 {
+		charset = "windows-1251"
+	}
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset="+charset)
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="schedule.csv"`)
+	res.WriteHeader(http.StatusOK)
+
+	// Заголовки ответа уже отправлены, поэтому при ошибке соединение обрывается:
+	// иначе клиент получит 200 и примет неполный файл за целый
+	if err := a.streamCSV(c, writer, filter, scheduleItems, withExams); err != nil {
+		log.Printf("Failed to stream CSV export: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("Failed to finish CSV export: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
+
+func (a *App) streamCSV(c echo.Context, writer *export.CSVWriter, filter csvFilter, scheduleItems []models.ScheduleItem, withExams bool) error {
+	if err := writer.WriteHeader(); err != nil {
+		return err
+	}
+
+	writeBatch := func(batch []models.ScheduleItem) error {
+		for _, item := range batch {
+			if !filter.matches(item) {
+				continue
+			}
+			if err := writer.WriteItem(item); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		c.Response().Flush()
+		return nil
+	}
+
+	if filter.kind == "" {
+		if err := repository.EachScheduleBatch(a.DB, csvBatchSize, writeBatch); err != nil {
+			return err
+		}
+	} else if err := writeBatch(scheduleItems); err != nil {
+		return err
+	}
+
+	if !withExams {
+		return nil
+	}
+
+	var exams []models.Exam
+	var err error
+	if filter.kind == "" {
+		exams, err = repository.AllExams(a.DB)
+	} else {
+		exams, err = a.loadExams(filter.kind, filter.id)
+	}
+	if err != nil {
+		return err
+	}
+	for _, exam := range exams {
+		if err := writer.WriteExam(exam); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func parseCSVFilter(c echo.Context) (csvFilter, error) {
+	var filter csvFilter
+	for _, param := range []struct{ name, kind string }{
+		{"group", kindGroup},
+		{"teacher", kindTeacher},
+		{"audience", kindAudience},
+		{"timetable", kindTimetable},
+	} {
+		value := c.QueryParam(param.name)
+		if value == "" {
+			continue
+		}
+		if filter.kind != "" {
+			return filter, errors.New("only one of group, teacher, audience, timetable may be set")
+		}
+		filter.kind, filter.id = param.kind, value
+	}
+
+	if value := c.QueryParam("day"); value != "" {
+		day, err := strconv.Atoi(value)
+		if err != nil || day < 1 || day > 7 {
+			return filter, fmt.Errorf("invalid day %q", value)
+		}
+		filter.day = day
+	}
+
+	filter.week = c.QueryParam("week")
+	switch filter.week {
+	case "", models.WeekAll, models.WeekNumerator, models.WeekDenominator:
+	default:
+		return filter, errors.New("week must be one of all, ch, zn")
+	}
+	return filter, nil
+}
+
+func (f csvFilter) matches(item models.ScheduleItem) bool {
+	if f.day != 0 && item.Day != f.day {
+		return false
+	}
+	switch f.week {
+	case "":
+		return true
+	case models.WeekAll:
+		return item.Week == models.WeekAll
+	default:
+		return item.Week == f.week || item.Week == models.WeekAll
+	}
+}
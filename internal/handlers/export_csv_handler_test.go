@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportCSVAbortsOnStreamError(t *testing.T) {
+	// Экзамены выбираются после занятий, когда ответ уже начат
+	a := &App{DB: newTestDB(t, testConnector{fail: "exams"})}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export/schedule.csv?exams=true", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { _ = a.ExportCSVHandler(c) })
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/csv")
+
+	// Без экзаменов выгрузка завершается обычным образом
+	rec = httptest.NewRecorder()
+	c = echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/export/schedule.csv", nil), rec)
+	assert.NoError(t, a.ExportCSVHandler(c))
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/export"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	pdfContentType  = "application/pdf"
+)
+
+// Способы разбивки экспорта на листы
+const (
+	sheetByGroup      = "group"
+	sheetByDepartment = "department"
+	sheetByCourse     = "course"
+)
+
+var errInvalidSheetBy = errors.New("sheet must be one of group, department, course")
+
+// ExportXLSXHandler отправляет расписание групп в виде книги Excel
+// @Summary Экспорт расписания в XLSX
+// @Description Формирует книгу Excel в классическом виде: дни сверху вниз, пары слева направо, числитель и знаменатель в отдельных строках.
+// @Description Занятия каждую неделю и потоковые занятия соседних групп выводятся в объединенных ячейках.
+// @Description Группы выбираются списком groups или узлом структуры node (факультет, кафедра, курс), по умолчанию - все группы
+// @Tags Export
+// @Produce octet-stream
+// @Param groups query string false "UUID групп через запятую"
+// @Param node query string false "UUID узла структуры, в который входят группы"
+// @Param sheet query string false "Разбивка на листы: group (по умолчанию), department или course"
+// @Success 200 {file} file "Книга Excel"
+// @Failure 400 {object} map[string]string "error: sheet must be one of group, department, course"
+// @Failure 404 {object} map[string]string "error: No groups found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /export/schedule.xlsx [get]
+func (a *App) ExportXLSXHandler(c echo.Context) error {
+	sheets, err := a.exportSheets(c)
+	if err != nil {
+		return exportError(c, err)
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteXLSX(&buf, sheets, a.bells()); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to build workbook"})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="schedule.xlsx"`)
+	return c.Blob(http.StatusOK, xlsxContentType, buf.Bytes())
+}
+
+// ExportPDFHandler отправляет расписание групп в PDF для печати
+// @Summary Экспорт расписания в PDF
+// @Description Формирует PDF формата A4 альбомной ориентации со встроенными кириллическими шрифтами в той же сетке, что и XLSX.
+// @Description Каждый лист (группа, кафедра или курс) начинается с новой страницы, поэтому node с UUID кафедры дает расписания всех ее групп одним файлом
+// @Tags Export
+// @Produce application/pdf
+// @Param groups query string false "UUID групп через запятую"
+// @Param node query string false "UUID узла структуры, в который входят группы"
+// @Param sheet query string false "Разбивка на страницы: group (по умолчанию), department или course"
+// @Success 200 {file} file "Документ PDF"
+// @Failure 400 {object} map[string]string "error: sheet must be one of group, department, course"
+// @Failure 404 {object} map[string]string "error: No groups found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /export/schedule.pdf [get]
+func (a *App) ExportPDFHandler(c echo.Context) error {
+	sheets, err := a.exportSheets(c)
+	if err != nil {
+		return exportError(c, err)
+	}
+	return a.sendPDF(c, sheets)
+}
+
+// GetGroupPDFHandler отправляет расписание группы в PDF
+// @Summary Расписание группы в PDF
+// @Tags Export
+// @Produce application/pdf
+// @Param uuid path string true "UUID группы"
+// @Success 200 {file} file "Документ PDF"
+// @Failure 404 {object} map[string]string "error: Group not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /groups/{uuid}/schedule.pdf [get]
+func (a *App) GetGroupPDFHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindGroup, "pdf")
+}
+
+// GetTeacherPDFHandler отправляет расписание преподавателя в PDF
+// @Summary Расписание преподавателя в PDF
+// @Tags Export
+// @Produce application/pdf
+// @Param uuid path string true "UUID преподавателя"
+// @Success 200 {file} file "Документ PDF"
+// @Failure 404 {object} map[string]string "error: Teacher not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /teachers/{uuid}/schedule.pdf [get]
+func (a *App) GetTeacherPDFHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindTeacher, "pdf")
+}
+
+// GetAudiencePDFHandler отправляет расписание аудитории в PDF
+// @Summary Расписание аудитории в PDF
+// @Tags Export
+// @Produce application/pdf
+// @Param uuid path string true "UUID аудитории"
+// @Success 200 {file} file "Документ PDF"
+// @Failure 404 {object} map[string]string "error: Audience not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /audiences/{uuid}/schedule.pdf [get]
+func (a *App) GetAudiencePDFHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindAudience, "pdf")
+}
+
+// scheduleSheet готовит лист экспорта с одним расписанием группы, преподавателя или аудитории
+func (a *App) scheduleSheet(kind, id, rowLabel string) (export.Sheet, error) {
+	scheduleItems, err := a.loadSchedule(kind, id)
+	if err != nil {
+		return export.Sheet{}, err
+	}
+	title, err := a.scheduleTitle(scheduleItems, kind, id)
+	if err != nil {
+		return export.Sheet{}, err
+	}
+
+	return export.Sheet{
+		Name:       title,
+		RowLabel:   rowLabel,
+		ShowGroups: kind != kindGroup,
+		Schedules:  []export.Schedule{{Title: title, Items: scheduleItems}},
+	}, nil
+}
+
+func (a *App) sendPDF(c echo.Context, sheets []export.Sheet) error {
+	var buf bytes.Buffer
+	if err := export.WritePDF(&buf, sheets, a.bells()); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to build document"})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="schedule.pdf"`)
+	return c.Blob(http.StatusOK, pdfContentType, buf.Bytes())
+}
+
+// exportSheets выбирает группы по параметрам запроса, загружает их расписания и раскладывает по листам
+func (a *App) exportSheets(c echo.Context) ([]export.Sheet, error) {
+	sheetBy := c.QueryParam("sheet")
+	if sheetBy == "" {
+		sheetBy = sheetByGroup
+	}
+	if sheetBy != sheetByGroup && sheetBy != sheetByDepartment && sheetBy != sheetByCourse {
+		return nil, errInvalidSheetBy
+	}
+
+	groups, err := repository.Groups(a.DB)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := repository.StructureNodes(a.DB)
+	if err != nil {
+		return nil, err
+	}
+	tree := newStructureIndex(nodes)
+
+	groups = selectExportGroups(groups, tree, c.QueryParam("groups"), c.QueryParam("node"))
+	if len(groups) == 0 {
+		return nil, errNoExportGroups
+	}
+
+	var sheets []export.Sheet
+	index := make(map[string]int)
+	for _, group := range groups {
+		scheduleItems, err := a.loadSchedule(kindGroup, group.UUID)
+		if err != nil {
+			return nil, err
+		}
+
+		key, name := group.UUID, group.Name
+		switch sheetBy {
+		case sheetByDepartment:
+			key, name = tree.department(group)
+		case sheetByCourse:
+			key, name = tree.course(group)
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(sheets)
+			index[key] = i
+			sheets = append(sheets, export.Sheet{Name: name})
+		}
+		sheets[i].Schedules = append(sheets[i].Schedules, export.Schedule{Title: group.Name, Items: scheduleItems})
+	}
+
+	return sheets, nil
+}
+
+var errNoExportGroups = errors.New("no groups found")
+
+func exportError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidSheetBy):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, errNoExportGroups):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "No groups found"})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+}
+
+// selectExportGroups оставляет группы из списка UUID или из поддерева узла структуры.
+// Группы без повторов упорядочиваются по названию
+func selectExportGroups(groups []models.Group, tree structureIndex, uuids, node string) []models.Group {
+	var wanted map[string]bool
+	switch {
+	case uuids != "":
+		wanted = make(map[string]bool)
+		for _, uuid := range strings.Split(uuids, ",") {
+			wanted[strings.TrimSpace(uuid)] = true
+		}
+	case node != "":
+		wanted = tree.descendants(node)
+	}
+
+	seen := make(map[string]bool)
+	result := make([]models.Group, 0, len(groups))
+	for _, group := range groups {
+		if seen[group.UUID] || (wanted != nil && !wanted[group.UUID]) {
+			continue
+		}
+		seen[group.UUID] = true
+		result = append(result, group)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// structureIndex - дерево структуры университета для поиска кафедры и курса группы
+type structureIndex struct {
+	nodes    map[string]models.StructureNode
+	children map[string][]string
+}
+
+func newStructureIndex(nodes []models.StructureNode) structureIndex {
+	index := structureIndex{
+		nodes:    make(map[string]models.StructureNode, len(nodes)),
+		children: make(map[string][]string),
+	}
+	for _, node := range nodes {
+		index.nodes[node.UUID] = node
+		if node.ParentUUID != nil {
+			index.children[*node.ParentUUID] = append(index.children[*node.ParentUUID], node.UUID)
+		}
+	}
+	return index
+}
+
+// descendants возвращает UUID всех узлов поддерева, включая сам узел
+func (s structureIndex) descendants(uuid string) map[string]bool {
+	result := map[string]bool{uuid: true}
+	queue := []string{uuid}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range s.children[current] {
+			if !result[child] {
+				result[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	return result
+}
+
+// ancestor возвращает ближайшего предка узла с типом nodeType
+func (s structureIndex) ancestor(uuid, nodeType string) (models.StructureNode, bool) {
+	node, ok := s.nodes[uuid]
+	for depth := 0; ok && node.ParentUUID != nil && depth < len(s.nodes); depth++ {
+		node, ok = s.nodes[*node.ParentUUID]
+		if ok && node.NodeType != nil && *node.NodeType == nodeType {
+			return node, true
+		}
+	}
+	return models.StructureNode{}, false
+}
+
+// department возвращает ключ и название листа кафедры группы
+func (s structureIndex) department(group models.Group) (string, string) {
+	if node, ok := s.ancestor(group.UUID, "department"); ok {
+		return node.UUID, nodeTitle(node)
+	}
+	if node, ok := s.nodes[group.DepartmentUID]; ok {
+		return node.UUID, nodeTitle(node)
+	}
+	if group.DepartmentUID != "" {
+		return group.DepartmentUID, group.DepartmentUID
+	}
+	return "", "Без кафедры"
+}
+
+// course возвращает ключ и название листа курса группы, например «ИУ7, 2 курс»
+func (s structureIndex) course(group models.Group) (string, string) {
+	node, ok := s.ancestor(group.UUID, "course")
+	if !ok {
+		return "", "Без курса"
+	}
+
+	title := nodeTitle(node)
+	if node.Course != nil {
+		title = fmt.Sprintf("%d курс", *node.Course)
+		if department, ok := s.ancestor(node.UUID, "department"); ok {
+			title = nodeTitle(department) + ", " + title
+		}
+	}
+	return node.UUID, title
+}
+
+func nodeTitle(node models.StructureNode) string {
+	if node.Abbr != "" {
+		return node.Abbr
+	}
+	return node.Name
+}
@@ -0,0 +1,506 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+)
+
+// Состояния задания выгрузки
+const (
+	ExportJobPending = "pending"
+	ExportJobRunning = "running"
+	ExportJobDone    = "done"
+	ExportJobFailed  = "failed"
+)
+
+// exportJobTTL - сколько хранится архив завершенного задания
+const exportJobTTL = 24 * time.Hour
+
+var (
+	ErrExportJobRunning = errors.New("export job is already running")
+	ErrUnknownFormat    = errors.New("unknown schedule format")
+	ErrUnknownKind      = errors.New("unknown schedule kind")
+	ErrNodeNotFound     = errors.New("structure node not found")
+)
+
+// ExportJob - фоновая выгрузка всех расписаний в ZIP-архив
+type ExportJob struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	Formats    []string   `json:"formats"`
+	Kinds      []string   `json:"kinds"`
+	Node       string     `json:"node,omitempty"`
+	Total      int        `json:"total"`     // Количество расписаний
+	Completed  int        `json:"completed"` // Обработано расписаний
+	Errors     []string   `json:"errors,omitempty"`
+	Error      string     `json:"error,omitempty"` // Причина, по которой задание не выполнено
+	Size       int64      `json:"size,omitempty"`  // Размер архива в байтах
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	path string
+}
+
+// ExportJobRequest - параметры задания выгрузки
+type ExportJobRequest struct {
+	Formats []string `json:"formats"`         // json, html, csv, ics, xlsx, pdf
+	Kinds   []string `json:"kinds,omitempty"` // group, teacher, audience, по умолчанию все
+	Node    string   `json:"node,omitempty"`  // UUID узла структуры, по умолчанию весь университет
+}
+
+// exportJobs хранит задания выгрузки в памяти процесса, нулевое значение готово к использованию
+type exportJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*ExportJob
+}
+
+// exportTarget - расписание, которое попадет в архив
+type exportTarget struct {
+	kind, uuid, name string
+}
+
+// exportDirs - папки архива по видам расписаний
+var exportDirs = map[string]string{
+	kindGroup:    "groups",
+	kindTeacher:  "teachers",
+	kindAudience: "audiences",
+}
+
+// exportManifest - manifest.json в корне архива
+type exportManifest struct {
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Formats     []string        `json:"formats"`
+	Node        string          `json:"node,omitempty"`
+	Schedules   []manifestEntry `json:"schedules"`
+	Skipped     int             `json:"skipped"` // Расписания без занятий, в архив не попали
+}
+
+type manifestEntry struct {
+	Kind   string            `json:"kind"`
+	UUID   string            `json:"uuid"`
+	Name   string            `json:"name"`
+	Items  int               `json:"items"`
+	Files  map[string]string `json:"files"` // Формат -> путь в архиве
+	Errors []string          `json:"errors,omitempty"`
+}
+
+// StartExportJob проверяет параметры и запускает выгрузку в фоне. Одновременно выполняется одно задание
+func (a *App) StartExportJob(req ExportJobRequest) (ExportJob, error) {
+	if len(req.Formats) == 0 {
+		return ExportJob{}, fmt.Errorf("%w: at least one format is required", ErrUnknownFormat)
+	}
+	formats := make([]string, 0, len(req.Formats))
+	for _, format := range req.Formats {
+		formats = append(formats, strings.ToLower(format))
+	}
+	formats = uniqueStrings(formats)
+	for _, format := range formats {
+		if _, ok := findScheduleFormat(format); !ok {
+			return ExportJob{}, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+		}
+		if format == "ics" && !a.Calendar.Configured() {
+			return ExportJob{}, calendar.ErrNotConfigured
+		}
+	}
+	kinds := uniqueStrings(req.Kinds)
+	if len(kinds) == 0 {
+		kinds = []string{kindGroup, kindTeacher, kindAudience}
+	}
+	for _, kind := range kinds {
+		if _, ok := exportDirs[kind]; !ok {
+			return ExportJob{}, fmt.Errorf("%w %q", ErrUnknownKind, kind)
+		}
+	}
+
+	id, err := randomToken(8)
+	if err != nil {
+		return ExportJob{}, err
+	}
+	job := &ExportJob{
+		ID:        id,
+		Status:    ExportJobPending,
+		Formats:   formats,
+		Kinds:     kinds,
+		Node:      req.Node,
+		CreatedAt: time.Now(),
+	}
+
+	targets, err := a.exportTargets(kinds, req.Node)
+	if err != nil {
+		return ExportJob{}, err
+	}
+	job.Total = len(targets)
+
+	a.exports.mu.Lock()
+	a.exports.removeExpired()
+	for _, other := range a.exports.jobs {
+		if other.Status == ExportJobPending || other.Status == ExportJobRunning {
+			a.exports.mu.Unlock()
+			return ExportJob{}, ErrExportJobRunning
+		}
+	}
+	if a.exports.jobs == nil {
+		a.exports.jobs = make(map[string]*ExportJob)
+	}
+	a.exports.jobs[id] = job
+	snapshot := job.snapshot()
+	a.exports.mu.Unlock()
+
+	go a.runExportJob(job, targets)
+	return snapshot, nil
+}
+
+// GetExportJob возвращает состояние задания
+func (a *App) GetExportJob(id string) (ExportJob, bool) {
+	a.exports.mu.Lock()
+	defer a.exports.mu.Unlock()
+	a.exports.removeExpired()
+	job, ok := a.exports.jobs[id]
+	if !ok {
+		return ExportJob{}, false
+	}
+	return job.snapshot(), true
+}
+
+// ListExportJobs возвращает все задания, начиная с новых
+func (a *App) ListExportJobs() []ExportJob {
+	a.exports.mu.Lock()
+	defer a.exports.mu.Unlock()
+	a.exports.removeExpired()
+	jobs := make([]ExportJob, 0, len(a.exports.jobs))
+	for _, job := range a.exports.jobs {
+		jobs = append(jobs, job.snapshot())
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// DeleteExportJob удаляет завершенное задание и его архив
+func (a *App) DeleteExportJob(id string) (bool, error) {
+	a.exports.mu.Lock()
+	defer a.exports.mu.Unlock()
+	job, ok := a.exports.jobs[id]
+	if !ok {
+		return false, nil
+	}
+	if job.Status == ExportJobPending || job.Status == ExportJobRunning {
+		return true, ErrExportJobRunning
+	}
+	job.removeArchive()
+	delete(a.exports.jobs, id)
+	return true, nil
+}
+
+// exportArchive возвращает путь к архиву завершенного задания
+func (a *App) exportArchive(id string) (string, ExportJob, error) {
+	a.exports.mu.Lock()
+	defer a.exports.mu.Unlock()
+	job, ok := a.exports.jobs[id]
+	if !ok {
+		return "", ExportJob{}, os.ErrNotExist
+	}
+	if job.Status != ExportJobDone {
+		return "", job.snapshot(), ErrExportJobRunning
+	}
+	_, err := os.Stat(job.path)
+	return job.path, job.snapshot(), err
+}
+
+// exportTargets перечисляет расписания для выгрузки. Для узла структуры берутся его группы,
+// а также преподаватели и аудитории, которые встречаются в расписаниях этих групп
+func (a *App) exportTargets(kinds []string, node string) ([]exportTarget, error) {
+	wanted := make(map[string]bool)
+	for _, kind := range kinds {
+		wanted[kind] = true
+	}
+
+	groups, err := repository.Groups(a.DB)
+	if err != nil {
+		return nil, err
+	}
+	if node != "" {
+		nodes, err := repository.StructureNodes(a.DB)
+		if err != nil {
+			return nil, err
+		}
+		tree := newStructureIndex(nodes)
+		if _, ok := tree.nodes[node]; !ok {
+			return nil, ErrNodeNotFound
+		}
+		groups = selectExportGroups(groups, tree, "", node)
+	} else {
+		groups = selectExportGroups(groups, structureIndex{}, "", "")
+	}
+
+	var targets []exportTarget
+	if wanted[kindGroup] {
+		for _, group := range groups {
+			targets = append(targets, exportTarget{kindGroup, group.UUID, group.Name})
+		}
+	}
+	if !wanted[kindTeacher] && !wanted[kindAudience] {
+		return targets, nil
+	}
+
+	var teachers []models.Teacher
+	var audiences []models.Audience
+	if node == "" {
+		if teachers, err = repository.Teachers(a.DB); err != nil {
+			return nil, err
+		}
+		if audiences, err = repository.Audiences(a.DB); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, group := range groups {
+			scheduleItems, err := a.loadSchedule(kindGroup, group.UUID)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range scheduleItems {
+				teachers = append(teachers, item.Teachers...)
+				audiences = append(audiences, item.Audiences...)
+			}
+		}
+	}
+
+	var people, rooms []exportTarget
+	seen := make(map[string]bool)
+	for _, t := range teachers {
+		if !seen[kindTeacher+t.UUID] {
+			seen[kindTeacher+t.UUID] = true
+			people = append(people, exportTarget{kindTeacher, t.UUID, strings.TrimSpace(t.LastName + " " + t.FirstName + " " + t.MiddleName)})
+		}
+	}
+	for _, au := range audiences {
+		if !seen[kindAudience+au.UUID] {
+			seen[kindAudience+au.UUID] = true
+			rooms = append(rooms, exportTarget{kindAudience, au.UUID, au.Name})
+		}
+	}
+	sort.SliceStable(people, func(i, j int) bool { return people[i].name < people[j].name })
+	sort.SliceStable(rooms, func(i, j int) bool { return rooms[i].name < rooms[j].name })
+
+	if wanted[kindTeacher] {
+		targets = append(targets, people...)
+	}
+	if wanted[kindAudience] {
+		targets = append(targets, rooms...)
+	}
+	return targets, nil
+}
+
+func (a *App) runExportJob(job *ExportJob, targets []exportTarget) {
+	a.updateExportJob(job, func(j *ExportJob) { j.Status = ExportJobRunning })
+
+	archivePath, err := a.writeExportArchive(job, targets)
+	if err != nil {
+		log.Printf("Export job %s failed: %v", job.ID, err)
+		a.updateExportJob(job, func(j *ExportJob) {
+			now := time.Now()
+			j.Status, j.Error, j.FinishedAt = ExportJobFailed, err.Error(), &now
+		})
+		return
+	}
+
+	var size int64
+	if info, err := os.Stat(archivePath); err == nil {
+		size = info.Size()
+	}
+	errorCount := 0
+	a.updateExportJob(job, func(j *ExportJob) {
+		now := time.Now()
+		j.Status, j.path, j.Size, j.FinishedAt = ExportJobDone, archivePath, size, &now
+		errorCount = len(j.Errors)
+	})
+	log.Printf("Export job %s finished: %d schedules, %d errors", job.ID, len(targets), errorCount)
+}
+
+func (a *App) writeExportArchive(job *ExportJob, targets []exportTarget) (string, error) {
+	file, err := os.CreateTemp("", "semesterly-export-*.zip")
+	if err != nil {
+		return "", err
+	}
+	archivePath := file.Name()
+	fail := func(err error) (string, error) {
+		file.Close()
+		os.Remove(archivePath)
+		return "", err
+	}
+
+	zw := zip.NewWriter(file)
+	manifest := exportManifest{GeneratedAt: time.Now(), Formats: job.Formats, Node: job.Node, Schedules: []manifestEntry{}}
+	names := make(map[string]bool)
+	startTime := time.Now()
+
+	for i, target := range targets {
+		scheduleItems, err := a.loadSchedule(target.kind, target.uuid)
+		switch {
+		case err != nil:
+			a.exportJobError(job, fmt.Sprintf("%s %s: %v", target.kind, target.uuid, err))
+		case len(scheduleItems) == 0:
+			manifest.Skipped++
+		default:
+			entry := manifestEntry{Kind: target.kind, UUID: target.uuid, Name: target.name, Items: len(scheduleItems), Files: make(map[string]string)}
+			base := exportFileName(exportDirs[target.kind], target, names)
+			for _, format := range job.Formats {
+				serializer, _ := findScheduleFormat(format)
+				name := base + "." + serializer.ext
+				// Файл попадает в архив, только если формат отрисован без ошибок
+				var buf bytes.Buffer
+				if err := serializer.render(a, &buf, target, renderOptions{date: job.CreatedAt}); err != nil {
+					message := fmt.Sprintf("%s: %v", format, err)
+					entry.Errors = append(entry.Errors, message)
+					a.exportJobError(job, fmt.Sprintf("%s %s %s", target.kind, target.uuid, message))
+					continue
+				}
+				w, err := zw.Create(name)
+				if err != nil {
+					return fail(err)
+				}
+				if _, err := buf.WriteTo(w); err != nil {
+					return fail(err)
+				}
+				entry.Files[format] = name
+			}
+			manifest.Schedules = append(manifest.Schedules, entry)
+		}
+
+		completed := i + 1
+		a.updateExportJob(job, func(j *ExportJob) { j.Completed = completed })
+		elapsed := time.Since(startTime)
+		eta := time.Duration(float64(len(targets)-completed) / (float64(completed) / elapsed.Seconds()) * float64(time.Second))
+		a.Hub.BroadcastProgress(ProgressUpdate{
+			Type:           ExportProgressType,
+			JobID:          job.ID,
+			CurrentItem:    completed,
+			TotalItems:     len(targets),
+			CompletedItems: completed,
+			Percentage:     float64(completed) / float64(len(targets)) * 100,
+			ETA:            eta.Round(time.Second).String(),
+		})
+	}
+
+	w, err := zw.Create("manifest.json")
+	if err != nil {
+		return fail(err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fail(err)
+	}
+	if err := zw.Close(); err != nil {
+		return fail(err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(archivePath)
+		return "", err
+	}
+
+	// Финальное состояние прогресса, в том числе для пустой выгрузки
+	a.Hub.BroadcastProgress(ProgressUpdate{
+		Type:           ExportProgressType,
+		JobID:          job.ID,
+		CurrentItem:    len(targets),
+		TotalItems:     len(targets),
+		CompletedItems: len(targets),
+		Percentage:     100,
+		ETA:            "0s",
+	})
+	return archivePath, nil
+}
+
+// exportFileName возвращает путь файла расписания в архиве без расширения: папка вида и название.
+// Совпадающие названия различаются началом UUID
+func exportFileName(dir string, target exportTarget, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(target.name))
+	if name == "" {
+		name = target.uuid
+	}
+
+	candidate := path.Join(dir, name)
+	if used[strings.ToLower(candidate)] {
+		suffix := target.uuid
+		if len(suffix) > 8 {
+			suffix = suffix[:8]
+		}
+		candidate = path.Join(dir, name+"_"+suffix)
+		for n := 2; used[strings.ToLower(candidate)]; n++ {
+			candidate = path.Join(dir, fmt.Sprintf("%s_%s_%d", name, suffix, n))
+		}
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func (a *App) updateExportJob(job *ExportJob, update func(*ExportJob)) {
+	a.exports.mu.Lock()
+	defer a.exports.mu.Unlock()
+	update(job)
+}
+
+func (a *App) exportJobError(job *ExportJob, message string) {
+	log.Printf("Export job %s: %s", job.ID, message)
+	a.updateExportJob(job, func(j *ExportJob) { j.Errors = append(j.Errors, message) })
+}
+
+// removeExpired удаляет завершенные задания старше exportJobTTL вместе с архивами. Вызывается под mu
+func (e *exportJobs) removeExpired() {
+	for id, job := range e.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > exportJobTTL {
+			job.removeArchive()
+			delete(e.jobs, id)
+		}
+	}
+}
+
+func (j *ExportJob) removeArchive() {
+	if j.path == "" {
+		return
+	}
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Failed to remove export archive %s: %v", j.path, err)
+	}
+}
+
+// snapshot копирует задание для передачи за пределы блокировки
+func (j *ExportJob) snapshot() ExportJob {
+	copied := *j
+	copied.Formats = append([]string(nil), j.Formats...)
+	copied.Kinds = append([]string(nil), j.Kinds...)
+	copied.Errors = append([]string(nil), j.Errors...)
+	return copied
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/labstack/echo/v4"
+)
+
+// CreateExportJobHandler запускает выгрузку всех расписаний в ZIP-архив
+// @Summary Запуск выгрузки архива расписаний
+// @Description Для каждой группы, преподавателя и аудитории (или для групп узла структуры и встречающихся в их расписаниях
+// @Description преподавателей и аудиторий) формирует файлы в выбранных форматах: json, html, csv, ics, xlsx, pdf.
+// @Description Архив содержит папки groups, teachers, audiences и manifest.json. Прогресс рассылается через /ws сообщениями
+// @Description с type = exportProgress и jobId. Одновременно выполняется одно задание, архив хранится 24 часа
+// @Tags ExportJobs
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param job body ExportJobRequest true "Форматы, виды расписаний и узел структуры"
+// @Success 202 {object} ExportJob "Созданное задание"
+// @Failure 400 {object} map[string]string "error: unknown schedule format"
+// @Failure 404 {object} map[string]string "error: Structure node not found"
+// @Failure 409 {object} map[string]string "error: Export job is already running"
+// @Failure 500 {object} map[string]string "error: Failed to start export job"
+// @Router /admin/export-jobs [post]
+func (a *App) CreateExportJobHandler(c echo.Context) error {
+	var req ExportJobRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	job, err := a.StartExportJob(req)
+	switch {
+	case errors.Is(err, ErrUnknownFormat), errors.Is(err, ErrUnknownKind):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, calendar.ErrNotConfigured):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Semester calendar is not configured, ics is unavailable"})
+	case errors.Is(err, ErrNodeNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Structure node not found"})
+	case errors.Is(err, ErrExportJobRunning):
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Export job is already running"})
+	case err != nil:
+		log.Printf("Failed to start export job: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start export job"})
+	}
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// ListExportJobsHandler возвращает задания выгрузки
+// @Summary Список заданий выгрузки
+// @Tags ExportJobs
+// @Produce json
+// @Security AdminToken
+// @Success 200 {array} ExportJob "Задания, начиная с новых"
+// @Router /admin/export-jobs [get]
+func (a *App) ListExportJobsHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, a.ListExportJobs())
+}
+
+// GetExportJobHandler возвращает состояние задания выгрузки
+// @Summary Состояние задания выгрузки
+// @Tags ExportJobs
+// @Produce json
+// @Security AdminToken
+// @Param id path string true "ID задания"
+// @Success 200 {object} ExportJob "Задание"
+// @Failure 404 {object} map[string]string "error: Export job not found"
+// @Router /admin/export-jobs/{id} [get]
+func (a *App) GetExportJobHandler(c echo.Context) error {
+	job, ok := a.GetExportJob(c.Param("id"))
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Export job not found"})
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+// DownloadExportJobHandler отправляет архив завершенного задания
+// @Summary Скачивание архива выгрузки
+// @Tags ExportJobs
+// @Produce application/zip
+// @Security AdminToken
+// @Param id path string true "ID задания"
+// @Success 200 {file} file "Архив ZIP"
+// @Failure 404 {object} map[string]string "error: Export job not found"
+// @Failure 409 {object} map[string]string "error: Export job is not finished"
+// @Router /admin/export-jobs/{id}/archive [get]
+func (a *App) DownloadExportJobHandler(c echo.Context) error {
+	archivePath, job, err := a.exportArchive(c.Param("id"))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Export job not found"})
+	case errors.Is(err, ErrExportJobRunning):
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Export job is not finished", "status": job.Status})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to open archive"})
+	}
+
+	filename := "semesterly-" + job.CreatedAt.Format("2006-01-02") + "-" + job.ID + ".zip"
+	return c.Attachment(archivePath, filename)
+}
+
+// DeleteExportJobHandler удаляет завершенное задание и его архив
+// @Summary Удаление задания выгрузки
+// @Tags ExportJobs
+// @Security AdminToken
+// @Param id path string true "ID задания"
+// @Success 204 "Задание удалено"
+// @Failure 404 {object} map[string]string "error: Export job not found"
+// @Failure 409 {object} map[string]string "error: Export job is already running"
+// @Router /admin/export-jobs/{id} [delete]
+func (a *App) DeleteExportJobHandler(c echo.Context) error {
+	found, err := a.DeleteExportJob(c.Param("id"))
+	if !found {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Export job not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Export job is already running"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/cache"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFileName(t *testing.T) {
+	used := make(map[string]bool)
+	assert.Equal(t, "groups/ИУ7-11Б", exportFileName("groups", exportTarget{kindGroup, "0123456789", "ИУ7-11Б"}, used))
+	// Совпадающее название различается началом UUID
+	assert.Equal(t, "groups/иу7-11б_abcdef01", exportFileName("groups", exportTarget{kindGroup, "abcdef0123", "иу7-11б"}, used))
+	assert.Equal(t, "audiences/a_b", exportFileName("audiences", exportTarget{kindAudience, "x", "a/b"}, used))
+	assert.Equal(t, "teachers/t-1", exportFileName("teachers", exportTarget{kindTeacher, "t-1", " "}, used))
+}
+
+func TestWriteExportArchive(t *testing.T) {
+	item := models.ScheduleItem{ID: 1, Day: 1, Time: 1, Week: models.WeekAll, Groups: []models.Group{{UUID: "g-1", Name: "ИУ7-11Б"}}}
+	a := &App{Hub: NewWebSocketHub(), Cache: cache.New[[]models.ScheduleItem](10, time.Minute)}
+	a.Cache.Set(cacheKey(kindGroup, "g-1"), []models.ScheduleItem{item})
+	a.Cache.Set(cacheKey(kindGroup, "g-2"), []models.ScheduleItem{})
+
+	job := &ExportJob{ID: "job", Formats: []string{"json"}}
+	targets := []exportTarget{{kindGroup, "g-1", "ИУ7-11Б"}, {kindGroup, "g-2", "ИУ7-12Б"}}
+	archivePath, err := a.writeExportArchive(job, targets)
+	require.NoError(t, err)
+	defer os.Remove(archivePath)
+	assert.Equal(t, 2, job.Completed)
+
+	zr, err := zip.OpenReader(archivePath)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+	}
+	require.Contains(t, files, "groups/ИУ7-11Б.json")
+	assert.NotContains(t, files, "groups/ИУ7-12Б.json", "schedules without lessons are skipped")
+
+	var manifest exportManifest
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, 1, manifest.Skipped)
+	require.Len(t, manifest.Schedules, 1)
+	assert.Equal(t, manifestEntry{Kind: kindGroup, UUID: "g-1", Name: "ИУ7-11Б", Items: 1,
+		Files: map[string]string{"json": "groups/ИУ7-11Б.json"}}, manifest.Schedules[0])
+}
+
+func TestWriteExportArchiveSkipsFailedRenders(t *testing.T) {
+	defer func(formats []scheduleFormat) { scheduleFormats = formats }(scheduleFormats)
+	scheduleFormats = append(scheduleFormats, scheduleFormat{name: "broken", ext: "txt",
+		render: func(a *App, w io.Writer, t exportTarget, _ renderOptions) error {
+			io.WriteString(w, "partial")
+			return errors.New("render failed")
+		}})
+
+	item := models.ScheduleItem{ID: 1, Day: 1, Time: 1, Week: models.WeekAll, Groups: []models.Group{{UUID: "g-1", Name: "ИУ7-11Б"}}}
+	a := &App{Hub: NewWebSocketHub(), Cache: cache.New[[]models.ScheduleItem](10, time.Minute)}
+	a.Cache.Set(cacheKey(kindGroup, "g-1"), []models.ScheduleItem{item})
+
+	job := &ExportJob{ID: "job", Formats: []string{"broken", "json"}}
+	archivePath, err := a.writeExportArchive(job, []exportTarget{{kindGroup, "g-1", "ИУ7-11Б"}})
+	require.NoError(t, err)
+	defer os.Remove(archivePath)
+
+	zr, err := zip.OpenReader(archivePath)
+	require.NoError(t, err)
+	defer zr.Close()
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"groups/ИУ7-11Б.json", "manifest.json"}, names)
+	assert.Len(t, job.Errors, 1)
+}
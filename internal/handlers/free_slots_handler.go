@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	lessonDays   = timetable.Days
+	slotsPerDay  = timetable.SlotsPerDay
+	maxRangeDays = 180
+)
+
+// FreeSlotsRequest описывает участников, для которых ищутся общие окна
+type FreeSlotsRequest struct {
+	Groups   []string `json:"groups"`
+	Teachers []string `json:"teachers"`
+	From     string   `json:"from,omitempty"` // YYYY-MM-DD
+	To       string   `json:"to,omitempty"`   // YYYY-MM-DD
+	Rank     string   `json:"rank,omitempty"` // chronological (по умолчанию) или gaps
+}
+
+// FreeSlot описывает окно, в которое ни у одного из участников нет занятий
+type FreeSlot struct {
+	Date string `json:"date,omitempty"`
+	Day  int    `json:"day"`
+	Slot int    `json:"slot"`
+	Week string `json:"week"`
+	// Изменение количества окон у студентов, если занять этот слот
+	Gaps int `json:"gaps"`
+}
+
+// FreeSlotsHandler ищет общие свободные окна для групп и преподавателей
+// @Summary Поиск общих свободных окон
+// @Description Возвращает пары (день, номер пары, четность недели), в которые ни у одной из групп и ни у одного из преподавателей нет занятий.
+// @Description При указании диапазона дат окна возвращаются для конкретных дат, даты вне семестров пропускаются. Параметр rank=gaps сортирует окна по количеству создаваемых у студентов окон.
+// @Tags FreeSlots
+// @Accept json
+// @Produce json
+// @Param request body FreeSlotsRequest true "Участники и диапазон дат"
+// @Success 200 {array} FreeSlot "Список свободных окон"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /free-slots [post]
+func (a *App) FreeSlotsHandler(c echo.Context) error {
+	var req FreeSlotsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if len(req.Groups) == 0 && len(req.Teachers) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "At least one group or teacher is required"})
+	}
+	if req.Rank != "" && req.Rank != "chronological" && req.Rank != "gaps" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "rank must be one of chronological, gaps"})
+	}
+	if (req.From == "") != (req.To == "") {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Both from and to must be set"})
+	}
+
+	scheduleItems, err := a.participantsSchedule(req.Groups, req.Teachers)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+	}
+
+	occupancy := newSlotOccupancy(scheduleItems, req.Groups)
+
+	var slots []FreeSlot
+	if req.From != "" {
+		from, err := a.Calendar.ParseDate(req.From)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		to, err := a.Calendar.ParseDate(req.To)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if to.Before(from) || to.Sub(from).Hours()/24 > maxRangeDays {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid date range"})
+		}
+
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			day, week, err := a.Calendar.Resolve(date)
+			switch {
+			case errors.Is(err, calendar.ErrOutOfSemester):
+				continue // Каникулы: диапазон может захватывать даты до или после семестра
+			case errors.Is(err, calendar.ErrNotConfigured):
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Semester calendar is not configured"})
+			case err != nil:
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			if day == 0 {
+				continue // Воскресенье, праздник или сессия
+			}
+			for slot := 1; slot <= slotsPerDay; slot++ {
+				if occupancy.busy(day, slot, week) {
+					continue
+				}
+				slots = append(slots, FreeSlot{
+					Date: date.Format("2006-01-02"),
+					Day:  day,
+					Slot: slot,
+					Week: week,
+					Gaps: occupancy.gapsDelta(day, slot, week),
+				})
+			}
+		}
+	} else {
+		for day := 1; day <= lessonDays; day++ {
+			for slot := 1; slot <= slotsPerDay; slot++ {
+				freeNumerator := !occupancy.busy(day, slot, models.WeekNumerator)
+				freeDenominator := !occupancy.busy(day, slot, models.WeekDenominator)
+
+				var week string
+				switch {
+				case freeNumerator && freeDenominator:
+					week = models.WeekAll
+				case freeNumerator:
+					week = models.WeekNumerator
+				case freeDenominator:
+					week = models.WeekDenominator
+				default:
+					continue
+				}
+				slots = append(slots, FreeSlot{
+					Day:  day,
+					Slot: slot,
+					Week: week,
+					Gaps: occupancy.gapsDelta(day, slot, week),
+				})
+			}
+		}
+	}
+
+	if req.Rank == "gaps" {
+		// Сортировка стабильная, поэтому внутри одинакового количества окон сохраняется хронологический порядок
+		sort.SliceStable(slots, func(i, j int) bool {
+			return slots[i].Gaps < slots[j].Gaps
+		})
+	}
+
+	if slots == nil {
+		slots = []FreeSlot{}
+	}
+	return c.JSON(http.StatusOK, slots)
+}
+
+// participantsSchedule загружает занятия всех указанных групп и преподавателей
+func (a *App) participantsSchedule(groupUUIDs, teacherUUIDs []string) ([]models.ScheduleItem, error) {
+	var scheduleItems []models.ScheduleItem
+
+	query := a.DB.Preload("Groups")
+	switch {
+	case len(groupUUIDs) > 0 && len(teacherUUIDs) > 0:
+		query = query.Where("schedule_items.id IN (?) OR schedule_items.id IN (?)",
+			a.groupItemIDs(groupUUIDs), a.teacherItemIDs(teacherUUIDs))
+	case len(groupUUIDs) > 0:
+		query = query.Where("schedule_items.id IN (?)", a.groupItemIDs(groupUUIDs))
+	default:
+		query = query.Where("schedule_items.id IN (?)", a.teacherItemIDs(teacherUUIDs))
+	}
+
+	if err := query.Find(&scheduleItems).Error; err != nil {
+		return nil, err
+	}
+	return scheduleItems, nil
+}
+
+func (a *App) groupItemIDs(uuids []string) any {
+	return a.DB.
+		Table("schedule_item_groups").
+		Select("schedule_item_groups.schedule_item_id").
+		Joins("JOIN groups ON groups.id = schedule_item_groups.group_id").
+		Where("groups.uuid IN ?", uuids)
+}
+
+func (a *App) teacherItemIDs(uuids []string) any {
+	return a.DB.
+		Table("schedule_item_teachers").
+		Select("schedule_item_teachers.schedule_item_id").
+		Joins("JOIN teachers ON teachers.id = schedule_item_teachers.teacher_id").
+		Where("teachers.uuid IN ?", uuids)
+}
+
+// slotOccupancy хранит занятость слотов участников по дням и четности недели
+type slotOccupancy struct {
+	// Занятые слоты: день -> номер пары -> четность
+	occupied map[int]map[int]map[string]bool
+	// Пары групп: UUID группы -> день -> четность -> номера пар
+	groupSlots map[string]map[int]map[string]map[int]bool
+}
+
+func newSlotOccupancy(scheduleItems []models.ScheduleItem, groupUUIDs []string) *slotOccupancy {
+	o := &slotOccupancy{
+		occupied:   make(map[int]map[int]map[string]bool),
+		groupSlots: make(map[string]map[int]map[string]map[int]bool),
+	}
+
+	requested := make(map[string]bool, len(groupUUIDs))
+	for _, uuid := range groupUUIDs {
+		requested[uuid] = true
+	}
+
+	for _, item := range scheduleItems {
+		for _, week := range itemWeeks(item.Week) {
+			if o.occupied[item.Day] == nil {
+				o.occupied[item.Day] = make(map[int]map[string]bool)
+			}
+			if o.occupied[item.Day][item.Time] == nil {
+				o.occupied[item.Day][item.Time] = make(map[string]bool)
+			}
+			o.occupied[item.Day][item.Time][week] = true
+
+			for _, group := range item.Groups {
+				if !requested[group.UUID] {
+					continue
+				}
+				if o.groupSlots[group.UUID] == nil {
+					o.groupSlots[group.UUID] = make(map[int]map[string]map[int]bool)
+				}
+				if o.groupSlots[group.UUID][item.Day] == nil {
+					o.groupSlots[group.UUID][item.Day] = make(map[string]map[int]bool)
+				}
+				if o.groupSlots[group.UUID][item.Day][week] == nil {
+					o.groupSlots[group.UUID][item.Day][week] = make(map[int]bool)
+				}
+				o.groupSlots[group.UUID][item.Day][week][item.Time] = true
+			}
+		}
+	}
+
+	return o
+}
+
+// busy сообщает, занят ли слот хотя бы у одного участника на указанной неделе
+func (o *slotOccupancy) busy(day, slot int, week string) bool {
+	for _, w := range itemWeeks(week) {
+		if o.occupied[day][slot][w] {
+			return true
+		}
+	}
+	return false
+}
+
+// gapsDelta считает, на сколько изменится суммарное количество окон у групп, если занять слот
+func (o *slotOccupancy) gapsDelta(day, slot int, week string) int {
+	delta := 0
+	for _, days := range o.groupSlots {
+		for _, w := range itemWeeks(week) {
+			slots := days[day][w]
+			if len(slots) == 0 {
+				continue // В этот день у группы нет занятий, окна не появятся
+			}
+			before := countGaps(slots)
+			slots[slot] = true
+			after := countGaps(slots)
+			delete(slots, slot)
+			delta += after - before
+		}
+	}
+	return delta
+}
+
+// countGaps считает количество свободных пар между первой и последней парой дня
+func countGaps(slots map[int]bool) int {
+	first, last := 0, 0
+	for slot := range slots {
+		if first == 0 || slot < first {
+			first = slot
+		}
+		if slot > last {
+			last = slot
+		}
+	}
+	return last - first + 1 - len(slots)
+}
+
+// itemWeeks раскрывает четность занятия в список недель, на которых оно проходит
+func itemWeeks(week string) []string {
+	switch week {
+	case models.WeekNumerator:
+		return []string{models.WeekNumerator}
+	case models.WeekDenominator:
+		return []string{models.WeekDenominator}
+	default:
+		return []string{models.WeekNumerator, models.WeekDenominator}
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotOccupancy(t *testing.T) {
+	group := models.Group{UUID: "group-1"}
+	scheduleItems := []models.ScheduleItem{
+		{Day: 1, Time: 1, Week: models.WeekAll, Groups: []models.Group{group}},
+		{Day: 1, Time: 3, Week: models.WeekNumerator, Groups: []models.Group{group}},
+		{Day: 2, Time: 2, Week: models.WeekDenominator},
+	}
+
+	o := newSlotOccupancy(scheduleItems, []string{group.UUID})
+
+	assert.True(t, o.busy(1, 1, models.WeekDenominator))
+	assert.True(t, o.busy(1, 3, models.WeekAll))
+	assert.False(t, o.busy(1, 3, models.WeekDenominator))
+	assert.False(t, o.busy(2, 2, models.WeekNumerator))
+
+	// Вторая пара закрывает окно на числителе и продолжает день на знаменателе
+	assert.Equal(t, -1, o.gapsDelta(1, 2, models.WeekAll))
+	// Пятая пара на знаменателе создает три окна
+	assert.Equal(t, 3, o.gapsDelta(1, 5, models.WeekDenominator))
+	// В день без занятий окна не появляются
+	assert.Equal(t, 0, o.gapsDelta(3, 4, models.WeekAll))
+}
+
+func TestFreeSlotsSkipsDatesOutsideSemester(t *testing.T) {
+	a := newResourceTestApp(t)
+	a.Calendar = calendar.New(time.UTC, calendar.Data{
+		Semesters: []calendar.Period{{
+			Start: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+		}},
+	})
+
+	// Семестр начинается с понедельника первой недели, 31 августа. Даты до него пропускаются
+	body := `{"groups": ["g1"], "from": "2026-08-27", "to": "2026-08-31"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/free-slots", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, a.FreeSlotsHandler(echo.New().NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var slots []FreeSlot
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &slots))
+	require.Len(t, slots, slotsPerDay)
+	for _, slot := range slots {
+		assert.Equal(t, "2026-08-31", slot.Date)
+		assert.Equal(t, 1, slot.Day)
+		assert.Equal(t, models.WeekNumerator, slot.Week)
+	}
+}
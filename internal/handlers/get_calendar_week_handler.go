@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/labstack/echo/v4"
+)
+
+// GetCalendarWeekHandler отправляет JSON с номером и четностью учебной недели для даты
+// @Summary Учебная неделя
+// @Description Возвращает номер учебной недели и ее четность (ch - числитель, zn - знаменатель) для указанной даты.
+// @Description Без параметра date используется текущая дата.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param date query string false "Дата в формате YYYY-MM-DD"
+// @Success 200 {object} calendar.WeekInfo "Информация о неделе"
+// @Failure 400 {object} map[string]string "error: Invalid date"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /calendar/week [get]
+func (a *App) GetCalendarWeekHandler(c echo.Context) error {
+	date := time.Now()
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		var err error
+		if date, err = a.Calendar.ParseDate(dateStr); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	info, err := a.Calendar.Week(date)
+	if errors.Is(err, calendar.ErrNotConfigured) {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Semester calendar is not configured"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, info)
+}
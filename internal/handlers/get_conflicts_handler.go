@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/kosttiik/semesterly_backend/internal/conflicts"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// GetConflictsHandler отправляет JSON со списком конфликтов в расписании
+// @Summary Конфликты в расписании
+// @Description Возвращает преподавателей, ведущих два занятия одновременно, аудитории с двумя несвязанными занятиями
+// @Description и группы с пересекающимися занятиями. Учитывается четность недель, занятия одного потока конфликтом не считаются.
+// @Tags Conflicts
+// @Accept json
+// @Produce json
+// @Param type query string false "Тип конфликта: teacher, audience или group"
+// @Success 200 {array} conflicts.Conflict "Список конфликтов"
+// @Failure 400 {object} map[string]string "error: Invalid conflict type"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /conflicts [get]
+func (a *App) GetConflictsHandler(c echo.Context) error {
+	conflictType := c.QueryParam("type")
+	switch conflictType {
+	case "", conflicts.TypeTeacher, conflicts.TypeAudience, conflicts.TypeGroup:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "type must be one of teacher, audience, group"})
+	}
+
+	scheduleItems, err := repository.AllSchedule(a.DB)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+	}
+
+	result := make([]conflicts.Conflict, 0)
+	for _, conflict := range conflicts.Detect(scheduleItems) {
+		if conflictType == "" || conflict.Type == conflictType {
+			result = append(result, conflict)
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// detectConflicts ищет конфликты во всем расписании для отчета о синхронизации
+func (a *App) detectConflicts() []conflicts.Conflict {
+	scheduleItems, err := repository.AllSchedule(a.DB)
+	if err != nil {
+		log.Printf("Failed to fetch schedule items for conflict detection: %v", err)
+		return nil
+	}
+
+	result := conflicts.Detect(scheduleItems)
+	if len(result) > 0 {
+		log.Printf("Detected %d schedule conflicts", len(result))
+	}
+	return result
+}
+
+// detectGroupConflicts ищет конфликты с участием занятий группы. Проверяются только занятия,
+// связанные с группой общими группами, преподавателями или аудиториями, а не все расписание
+func (a *App) detectGroupConflicts(uuid string) []conflicts.Conflict {
+	groupItems, err := repository.GroupSchedule(a.DB, uuid)
+	if err != nil {
+		log.Printf("Failed to fetch schedule items of group %s for conflict detection: %v", uuid, err)
+		return nil
+	}
+	related, err := repository.RelatedSchedule(a.DB, groupItems)
+	if err != nil {
+		log.Printf("Failed to fetch related schedule items of group %s for conflict detection: %v", uuid, err)
+		return nil
+	}
+
+	ids := make(map[uint]bool, len(groupItems))
+	for _, item := range groupItems {
+		ids[item.ID] = true
+	}
+	result := conflicts.Involving(conflicts.Detect(related), ids)
+	if len(result) > 0 {
+		log.Printf("Detected %d schedule conflicts for group %s", len(result), uuid)
+	}
+	return result
+}
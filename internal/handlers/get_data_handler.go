@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
 	"github.com/labstack/echo/v4"
 )
 
@@ -13,18 +14,21 @@ import (
 // @Tags GetData
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag ранее полученного ответа"
+// @Param If-Modified-Since header string false "Дата Last-Modified ранее полученного ответа"
 // @Success 200 {array} models.ScheduleItem "Список элементов расписания"
+// @Header 200 {string} ETag "Версия данных"
+// @Header 200 {string} Last-Modified "Время последнего изменения данных"
+// @Success 304 "Данные не изменились"
 // @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
 // @Router /get-data [get]
 func (a *App) GetDataHandler(c echo.Context) error {
-	var scheduleItems []models.ScheduleItem
+	if a.notModified(c, models.GlobalDataScope) {
+		return c.NoContent(http.StatusNotModified)
+	}
 
-	if err := a.DB.
-		Preload("Groups").
-		Preload("Teachers").
-		Preload("Audiences").
-		Preload("Disciplines").
-		Find(&scheduleItems).Error; err != nil {
+	scheduleItems, err := repository.AllSchedule(a.DB)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
 	}
 
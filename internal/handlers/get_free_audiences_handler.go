@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// GetFreeAudiencesHandler отправляет JSON со списком аудиторий, свободных в указанную пару
+// @Summary Поиск свободных аудиторий
+// @Description Возвращает аудитории, в которых нет занятий в указанный день и пару с учетом четности недели.
+// @Description Вместо day и week можно передать date, тогда день недели и четность будут вычислены по дате.
+// @Tags Audiences
+// @Accept json
+// @Produce json
+// @Param day query int false "День недели (1 - понедельник, 6 - суббота)"
+// @Param slot query int true "Номер пары"
+// @Param week query string false "Четность недели: all, ch (числитель) или zn (знаменатель)"
+// @Param building query string false "Корпус"
+// @Param date query string false "Дата в формате YYYY-MM-DD"
+// @Success 200 {array} models.Audience "Список свободных аудиторий"
+// @Failure 400 {object} map[string]string "error: Invalid query parameters"
+// @Failure 500 {object} map[string]string "error: Failed to fetch audiences"
+// @Router /audiences/free [get]
+func (a *App) GetFreeAudiencesHandler(c echo.Context) error {
+	slot, err := strconv.Atoi(c.QueryParam("slot"))
+	if err != nil || slot <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "slot must be a positive integer"})
+	}
+
+	var day int
+	week := c.QueryParam("week")
+
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		date, err := a.Calendar.ParseDate(dateStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		// С учетом праздников и переносов; в дни без занятий свободны все аудитории
+		if day, week, err = a.Calendar.Resolve(date); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	} else {
+		day, err = strconv.Atoi(c.QueryParam("day"))
+		if err != nil || day < 1 || day > 6 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "day must be an integer from 1 to 6"})
+		}
+	}
+
+	var busyWeeks []string
+	switch week {
+	case "", models.WeekAll:
+		// Аудитория должна быть свободна на обеих неделях, подходит любое занятие
+	case models.WeekNumerator, models.WeekDenominator:
+		busyWeeks = []string{models.WeekAll, week}
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "week must be one of all, ch, zn"})
+	}
+
+	// Аудитории, занятые в указанную пару
+	busy := a.DB.
+		Table("schedule_item_audiences").
+		Select("schedule_item_audiences.audience_id").
+		Joins("JOIN schedule_items ON schedule_items.id = schedule_item_audiences.schedule_item_id").
+		Where("schedule_items.day = ? AND schedule_items.time = ?", day, slot)
+	if len(busyWeeks) > 0 {
+		busy = busy.Where("schedule_items.week IN ?", busyWeeks)
+	}
+
+	query := a.DB.Where("id NOT IN (?)", busy)
+	if building := c.QueryParam("building"); building != "" {
+		query = query.Where("building = ?", building)
+	}
+
+	var audiences []models.Audience
+	if err := query.Order("name").Find(&audiences).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch audiences"})
+	}
+
+	return c.JSON(http.StatusOK, audiences)
+}
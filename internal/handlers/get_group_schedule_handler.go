@@ -3,35 +3,47 @@ package handlers
 import (
 	"net/http"
 
-	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
 	"github.com/labstack/echo/v4"
 )
 
 // GetGroupScheduleHandler отправляет JSON с расписанием конкретной группы из базы данных
 // @Summary Получение расписания группы
-// @Description Возвращает данные расписания конкретной группы из базы данных в формате JSON
+// @Description Возвращает данные расписания конкретной группы из базы данных в формате JSON.
+// @Description При layout=grid расписание возвращается сеткой дни × пары, где каждая ячейка содержит занятия по всем неделям, числителям и знаменателям
 // @Tags GetData
 // @Accept json
 // @Produce json
 // @Param uuid path string true "UUID группы"
-// @Success 200 {array} models.ScheduleItem "Список элементов расписания"
+// @Param layout query string false "Формат ответа: flat (по умолчанию) или grid"
+// @Param If-None-Match header string false "ETag ранее полученного ответа"
+// @Param If-Modified-Since header string false "Дата Last-Modified ранее полученного ответа"
+// @Success 200 {array} models.ScheduleItem "Список элементов расписания; при layout=grid - объект timetable.Grid"
+// @Header 200 {string} ETag "Версия данных"
+// @Header 200 {string} Last-Modified "Время последнего изменения данных"
+// @Success 304 "Данные не изменились"
+// @Failure 400 {object} map[string]string "error: layout must be one of flat, grid"
 // @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
 // @Router /get-group-schedule/{uuid} [get]
 func (a *App) GetGroupScheduleHandler(c echo.Context) error {
 	uuid := c.Param("uuid")
-	var scheduleItems []models.ScheduleItem
 
-	if err := a.DB.
-		Preload("Groups").
-		Preload("Teachers").
-		Preload("Audiences").
-		Preload("Disciplines").
-		Joins("JOIN schedule_item_groups ON schedule_item_groups.schedule_item_id = schedule_items.id").
-		Joins("JOIN groups ON groups.id = schedule_item_groups.group_id").
-		Where("groups.uuid = ?", uuid).
-		Find(&scheduleItems).Error; err != nil {
+	layout := c.QueryParam("layout")
+	if layout != "" && layout != "flat" && layout != "grid" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "layout must be one of flat, grid"})
+	}
+
+	if a.notModified(c, uuid) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	scheduleItems, err := a.loadSchedule(kindGroup, uuid)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
 	}
 
+	if layout == "grid" {
+		return c.JSON(http.StatusOK, timetable.Build(scheduleItems, a.bells()))
+	}
 	return c.JSON(http.StatusOK, scheduleItems)
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
 	"github.com/labstack/echo/v4"
 )
 
@@ -13,13 +14,21 @@ import (
 // @Tags GetGroups
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag ранее полученного ответа"
+// @Param If-Modified-Since header string false "Дата Last-Modified ранее полученного ответа"
 // @Success 200 {array} models.Group "Список групп"
+// @Header 200 {string} ETag "Версия данных"
+// @Header 200 {string} Last-Modified "Время последнего изменения данных"
+// @Success 304 "Данные не изменились"
 // @Failure 500 {object} map[string]string "error: Failed to fetch groups"
 // @Router /get-groups [get]
 func (a *App) GetGroupsHandler(c echo.Context) error {
-	var groups []models.Group
+	if a.notModified(c, models.GlobalDataScope) {
+		return c.NoContent(http.StatusNotModified)
+	}
 
-	if err := a.DB.Find(&groups).Error; err != nil {
+	groups, err := repository.Groups(a.DB)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch groups"})
 	}
 
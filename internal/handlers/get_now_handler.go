@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/labstack/echo/v4"
+)
+
+// Насколько далеко вперед искать следующее занятие
+const nextLessonLookahead = 14 * 24 * time.Hour
+
+// NowResponse описывает текущее и следующее занятие
+type NowResponse struct {
+	At      time.Time  `json:"at"`
+	Current *NowLesson `json:"current"`
+	Next    *NowLesson `json:"next"`
+}
+
+// NowLesson описывает занятие с оставшимся временем: до конца для текущего и до начала для следующего
+type NowLesson struct {
+	calendar.Occurrence
+	Remaining        string `json:"remaining"`
+	RemainingSeconds int64  `json:"remainingSeconds"`
+}
+
+// GetNowHandler отправляет JSON с текущим и следующим занятием группы, преподавателя или аудитории
+// @Summary Текущее и следующее занятие
+// @Description Возвращает занятие, идущее в момент at, следующее занятие и оставшееся время.
+// @Description Нужно указать ровно один из параметров group, teacher или audience. Без at используется текущее время.
+// @Tags Occurrences
+// @Accept json
+// @Produce json
+// @Param group query string false "UUID группы"
+// @Param teacher query string false "UUID преподавателя"
+// @Param audience query string false "UUID аудитории"
+// @Param at query string false "Момент времени в формате RFC 3339"
+// @Success 200 {object} NowResponse "Текущее и следующее занятие"
+// @Failure 400 {object} map[string]string "error: Invalid query parameters"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /now [get]
+func (a *App) GetNowHandler(c echo.Context) error {
+	if !a.Calendar.Configured() {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Semester calendar is not configured"})
+	}
+
+	var kind, uuid string
+	for _, param := range []string{kindGroup, kindTeacher, kindAudience} {
+		if value := c.QueryParam(param); value != "" {
+			if kind != "" {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "Only one of group, teacher or audience is allowed"})
+			}
+			kind, uuid = param, value
+		}
+	}
+	if kind == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "One of group, teacher or audience is required"})
+	}
+
+	at := time.Now()
+	if atStr := c.QueryParam("at"); atStr != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, atStr); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "at must be in RFC 3339 format"})
+		}
+	}
+	at = at.In(a.Calendar.Location())
+
+	scheduleItems, err := a.loadSchedule(kind, uuid)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+	}
+
+	occurrences, err := a.Calendar.Expand(scheduleItems, at, at.Add(nextLessonLookahead))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := NowResponse{At: at}
+	for _, o := range occurrences {
+		switch {
+		case response.Current == nil && !o.Start.After(at) && o.End.After(at):
+			response.Current = newNowLesson(o, o.End.Sub(at))
+		case response.Next == nil && o.Start.After(at):
+			response.Next = newNowLesson(o, o.Start.Sub(at))
+		}
+		if response.Next != nil {
+			break
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func newNowLesson(o calendar.Occurrence, remaining time.Duration) *NowLesson {
+	remaining = remaining.Round(time.Second)
+	return &NowLesson{
+		Occurrence:       o,
+		Remaining:        remaining.String(),
+		RemainingSeconds: int64(remaining.Seconds()),
+	}
+}
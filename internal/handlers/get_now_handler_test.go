@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNowHandler(t *testing.T) {
+	a := newResourceTestApp(t)
+	// Первая неделя семестра, с 31 августа, - числитель
+	a.Calendar = calendar.New(time.UTC, calendar.Data{
+		Semesters: []calendar.Period{{
+			Start: time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+		}},
+	})
+	a.Cache.Set(cacheKey(kindGroup, "g1"), []models.ScheduleItem{
+		{ID: 1, Day: 1, Time: 1, Week: models.WeekAll, StartTime: "09:00", EndTime: "10:30"},
+		{ID: 2, Day: 1, Time: 2, Week: models.WeekAll, StartTime: "10:45", EndTime: "12:20"},
+		{ID: 3, Day: 2, Time: 1, Week: models.WeekDenominator, StartTime: "09:00", EndTime: "10:30"},
+		{ID: 4, Day: 3, Time: 3, Week: models.WeekNumerator, StartTime: "12:50", EndTime: "14:25"},
+	})
+
+	cases := []struct {
+		name          string
+		at            string
+		current, next uint // ID занятия, 0 - занятия нет
+		remaining     string
+		nextStart     string
+	}{
+		{"during lesson", "2026-08-31T09:30:00Z", 1, 2, "1h0m0s", "2026-08-31T10:45:00Z"},
+		{"break", "2026-08-31T10:35:00Z", 0, 2, "", "2026-08-31T10:45:00Z"},
+		{"after last lesson", "2026-08-31T13:00:00Z", 0, 4, "", "2026-09-02T12:50:00Z"},
+		{"sunday", "2026-09-06T12:00:00Z", 0, 1, "", "2026-09-07T09:00:00Z"},
+		{"other parity", "2026-09-01T09:30:00Z", 0, 4, "", "2026-09-02T12:50:00Z"},
+		// Среда второй недели - знаменатель, занятие числителя 9 сентября пропускается
+		{"matching parity", "2026-09-08T09:30:00Z", 3, 1, "1h0m0s", "2026-09-14T09:00:00Z"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/now?group=g1&at="+tc.at, nil)
+			rec := httptest.NewRecorder()
+			require.NoError(t, a.GetNowHandler(echo.New().NewContext(req, rec)))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var response NowResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+			if tc.current == 0 {
+				assert.Nil(t, response.Current)
+			} else if assert.NotNil(t, response.Current) {
+				assert.Equal(t, tc.current, response.Current.Item.ID)
+				assert.Equal(t, tc.remaining, response.Current.Remaining)
+			}
+
+			require.NotNil(t, response.Next)
+			assert.Equal(t, tc.next, response.Next.Item.ID)
+			assert.Equal(t, tc.nextStart, response.Next.Start.UTC().Format(time.RFC3339))
+		})
+	}
+}
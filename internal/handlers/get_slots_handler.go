@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/labstack/echo/v4"
+)
+
+// GetSlotsHandler отправляет JSON с расписанием звонков
+// @Summary Расписание звонков
+// @Description Возвращает пары с временем начала и окончания. Пары выводятся из занятий при загрузке данных, занятия ссылаются на них через slotId
+// @Tags Slots
+// @Produce json
+// @Success 200 {array} models.TimeSlot "Список пар"
+// @Failure 500 {object} map[string]string "error: Failed to fetch time slots"
+// @Router /slots [get]
+func (a *App) GetSlotsHandler(c echo.Context) error {
+	slots, err := repository.TimeSlots(a.DB)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch time slots"})
+	}
+	return c.JSON(http.StatusOK, slots)
+}
+
+// RefreshTimeSlots выводит расписание звонков из занятий и связывает с ним занятия.
+// Возвращает занятия, время которых расходится с расписанием звонков
+func (a *App) RefreshTimeSlots() ([]repository.SlotMismatch, error) {
+	mismatches, updated, err := repository.DeriveTimeSlots(a.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range mismatches {
+		log.Printf("Slot %d: %d items at %s-%s, expected %s-%s",
+			m.Slot, m.Actual.Items, m.Actual.StartTime, m.Actual.EndTime, m.Expected.StartTime, m.Expected.EndTime)
+	}
+
+	// Ссылки на пары есть в каждом ответе с занятиями
+	if updated {
+		if a.Cache != nil {
+			a.Cache.DeletePrefix("")
+		}
+		if err := repository.BumpAllDataVersions(a.DB); err != nil {
+			log.Printf("Failed to bump data versions: %v", err)
+		}
+	}
+
+	return mismatches, nil
+}
+
+// bells возвращает расписание звонков для сетки расписания
+func (a *App) bells() map[int]timetable.Bell {
+	slots, err := repository.TimeSlots(a.DB)
+	if err != nil || len(slots) == 0 {
+		return timetable.DefaultBells
+	}
+
+	bells := make(map[int]timetable.Bell, len(slots))
+	for _, slot := range slots {
+		bells[slot.Number] = timetable.Bell{StartTime: slot.StartTime, EndTime: slot.EndTime}
+	}
+	return bells
+}
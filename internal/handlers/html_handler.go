@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/pages"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/labstack/echo/v4"
+)
+
+const maxPageRefresh = 24 * 60 * 60
+
+// GetGroupHTMLHandler отправляет HTML-страницу с расписанием группы
+// @Summary Расписание группы в HTML
+// @Description Страница для киосков, ссылок в письмах и браузеров без приложения. Подходит для телефонов и печати.
+// @Description Занятия текущей недели (числителя или знаменателя) подсвечиваются, занятия другой недели приглушаются
+// @Tags Pages
+// @Produce html
+// @Param uuid path string true "UUID группы"
+// @Param date query string false "Дата в формате YYYY-MM-DD, неделя которой подсвечивается, по умолчанию сегодня"
+// @Param refresh query int false "Интервал автообновления страницы в секундах"
+// @Success 200 {string} string "Страница HTML"
+// @Failure 400 {object} map[string]string "error: Invalid date"
+// @Failure 404 {object} map[string]string "error: Group not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /groups/{uuid}/schedule.html [get]
+func (a *App) GetGroupHTMLHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindGroup, "html")
+}
+
+// GetTeacherHTMLHandler отправляет HTML-страницу с расписанием преподавателя
+// @Summary Расписание преподавателя в HTML
+// @Tags Pages
+// @Produce html
+// @Param uuid path string true "UUID преподавателя"
+// @Param date query string false "Дата в формате YYYY-MM-DD, неделя которой подсвечивается, по умолчанию сегодня"
+// @Param refresh query int false "Интервал автообновления страницы в секундах"
+// @Success 200 {string} string "Страница HTML"
+// @Failure 400 {object} map[string]string "error: Invalid date"
+// @Failure 404 {object} map[string]string "error: Teacher not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /teachers/{uuid}/schedule.html [get]
+func (a *App) GetTeacherHTMLHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindTeacher, "html")
+}
+
+// GetAudienceHTMLHandler отправляет HTML-страницу с расписанием аудитории
+// @Summary Расписание аудитории в HTML
+// @Tags Pages
+// @Produce html
+// @Param uuid path string true "UUID аудитории"
+// @Param date query string false "Дата в формате YYYY-MM-DD, неделя которой подсвечивается, по умолчанию сегодня"
+// @Param refresh query int false "Интервал автообновления страницы в секундах"
+// @Success 200 {string} string "Страница HTML"
+// @Failure 400 {object} map[string]string "error: Invalid date"
+// @Failure 404 {object} map[string]string "error: Audience not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Router /audiences/{uuid}/schedule.html [get]
+func (a *App) GetAudienceHTMLHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindAudience, "html")
+}
+
+// pageOptions читает параметры страницы date и refresh
+func (a *App) pageOptions(c echo.Context) (renderOptions, error) {
+	opts := renderOptions{date: time.Now()}
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		var err error
+		if opts.date, err = a.Calendar.ParseDate(dateStr); err != nil {
+			return opts, err
+		}
+	}
+	if value := c.QueryParam("refresh"); value != "" {
+		var err error
+		if opts.refresh, err = strconv.Atoi(value); err != nil || opts.refresh < 0 || opts.refresh > maxPageRefresh {
+			return opts, errors.New("refresh must be a number of seconds between 0 and 86400")
+		}
+	}
+	return opts, nil
+}
+
+// renderSchedulePage записывает страницу HTML с расписанием вида kind
+func (a *App) renderSchedulePage(w io.Writer, kind, id string, opts renderOptions) error {
+	scheduleItems, err := a.loadSchedule(kind, id)
+	if err != nil {
+		return err
+	}
+	title, err := a.scheduleTitle(scheduleItems, kind, id)
+	if err != nil {
+		return err
+	}
+
+	page := pages.Page{
+		Title:      title,
+		Kind:       scheduleRowLabels[kind],
+		Grid:       timetable.Build(scheduleItems, a.bells()),
+		ShowGroups: kind != kindGroup,
+		Refresh:    opts.refresh,
+	}
+	if opts.links {
+		// Ссылки относительно страницы .../schedule.html или .../schedule, в том числе личного расписания
+		page.Links = []pages.Link{{Title: "PDF для печати", URL: "schedule?format=pdf"}}
+	}
+	// Без календаря страница показывается без подсветки недели
+	if info, err := a.Calendar.Week(opts.date); err == nil {
+		page.Week = &info
+		if opts.links {
+			page.Links = append(page.Links, pages.Link{Title: "Добавить в календарь", URL: "schedule?format=ics"})
+		}
+	}
+	return pages.Render(w, page)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// notModified выставляет заголовки ETag и Last-Modified по версии данных scope и сообщает,
+// что у клиента актуальная копия и можно ответить 304 Not Modified
+func (a *App) notModified(c echo.Context, scope string) bool {
+	return a.notModifiedVariant(c, scope, "")
+}
+
+// notModifiedVariant - notModified для ресурса с несколькими представлениями: variant входит в ETag,
+// чтобы ETag одного формата не подходил для другого
+func (a *App) notModifiedVariant(c echo.Context, scope, variant string) bool {
+	version, err := repository.DataVersionOf(a.DB, scope)
+	if err != nil {
+		log.Printf("Failed to fetch data version for %s: %v", scope, err)
+		return false
+	}
+
+	// Слабый ETag, так как тело ответа может быть сжато по-разному
+	etag := fmt.Sprintf(`W/"%s-%d"`, scope, version.Version)
+	if variant != "" {
+		etag = fmt.Sprintf(`W/"%s-%d-%s"`, scope, version.Version, variant)
+	}
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "no-cache")
+	if !version.UpdatedAt.IsZero() {
+		header.Set("Last-Modified", version.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+
+	req := c.Request()
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince := req.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !version.UpdatedAt.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !version.UpdatedAt.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// etagMatches сравнивает ETag из If-None-Match со значением etag без учета признака слабого ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtagMatches(t *testing.T) {
+	etag := `W/"global-3"`
+
+	assert.True(t, etagMatches(`W/"global-3"`, etag))
+	assert.True(t, etagMatches(`"global-3"`, etag))
+	assert.True(t, etagMatches(`W/"global-2", W/"global-3"`, etag))
+	assert.True(t, etagMatches(`*`, etag))
+	assert.False(t, etagMatches(`W/"global-2"`, etag))
+}
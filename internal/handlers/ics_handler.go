@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/kosttiik/semesterly_backend/internal/ical"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+var examLoaders = map[string]func(db *gorm.DB, uuid string) ([]models.Exam, error){
+	kindGroup:    repository.GroupExams,
+	kindTeacher:  repository.TeacherExams,
+	kindAudience: repository.AudienceExams,
+}
+
+// GetGroupICSHandler отправляет расписание группы в формате iCalendar
+// @Summary Расписание группы в формате iCalendar
+// @Description Возвращает файл .ics с повторяющимися занятиями группы на все семестры и экзаменами.
+// @Description Занятия по числителю и знаменателю повторяются раз в две недели, праздники и сессии исключаются через EXDATE.
+// @Tags ICS
+// @Produce text/calendar
+// @Param uuid path string true "UUID группы"
+// @Success 200 {string} string "Календарь iCalendar"
+// @Failure 404 {object} map[string]string "error: Group not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /groups/{uuid}/schedule.ics [get]
+func (a *App) GetGroupICSHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindGroup, "ics")
+}
+
+// GetTeacherICSHandler отправляет расписание преподавателя в формате iCalendar
+// @Summary Расписание преподавателя в формате iCalendar
+// @Description Возвращает файл .ics с повторяющимися занятиями преподавателя на все семестры и принимаемыми экзаменами.
+// @Description Занятия по числителю и знаменателю повторяются раз в две недели, праздники и сессии исключаются через EXDATE.
+// @Tags ICS
+// @Produce text/calendar
+// @Param uuid path string true "UUID преподавателя"
+// @Success 200 {string} string "Календарь iCalendar"
+// @Failure 404 {object} map[string]string "error: Teacher not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /teachers/{uuid}/schedule.ics [get]
+func (a *App) GetTeacherICSHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindTeacher, "ics")
+}
+
+// GetAudienceICSHandler отправляет расписание аудитории в формате iCalendar
+// @Summary Расписание аудитории в формате iCalendar
+// @Description Возвращает файл .ics с повторяющимися занятиями в аудитории на все семестры и экзаменами в ней.
+// @Description Занятия по числителю и знаменателю повторяются раз в две недели, праздники и сессии исключаются через EXDATE.
+// @Tags ICS
+// @Produce text/calendar
+// @Param uuid path string true "UUID аудитории"
+// @Success 200 {string} string "Календарь iCalendar"
+// @Failure 404 {object} map[string]string "error: Audience not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /audiences/{uuid}/schedule.ics [get]
+func (a *App) GetAudienceICSHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindAudience, "ics")
+}
+
+// scheduleCalendar собирает календарь iCalendar из расписания и экзаменов вида kind
+func (a *App) scheduleCalendar(kind, id string) ([]byte, error) {
+	scheduleItems, err := a.loadSchedule(kind, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schedule items: %w", err)
+	}
+
+	exams, err := a.loadExams(kind, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exams: %w", err)
+	}
+
+	events, err := ical.ScheduleEvents(a.Calendar, scheduleItems)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, ical.ExamEvents(a.Calendar.Location(), exams)...)
+
+	title, err := a.scheduleTitle(scheduleItems, kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	cal := ical.Calendar{Name: title, Location: a.Calendar.Location(), Events: events}
+	if err := cal.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode calendar: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// loadExams возвращает экзамены вида kind, для личного расписания - экзамены всех его групп
+func (a *App) loadExams(kind, id string) ([]models.Exam, error) {
+	if kind != kindTimetable {
+		return examLoaders[kind](a.DB, id)
+	}
+
+	timetable, err := repository.PersonalTimetable(a.DB, id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool)
+	var result []models.Exam
+	for _, source := range timetable.Sources {
+		exams, err := repository.GroupExams(a.DB, source.Group)
+		if err != nil {
+			return nil, err
+		}
+		for _, exam := range exams {
+			if !seen[exam.ID] {
+				seen[exam.ID] = true
+				result = append(result, exam)
+			}
+		}
+	}
+	return result, nil
+}
+
+// scheduleTitle подбирает название расписания по имени группы, преподавателя, аудитории или личного расписания
+func (a *App) scheduleTitle(scheduleItems []models.ScheduleItem, kind, id string) (string, error) {
+	if kind == kindTimetable {
+		timetable, err := repository.PersonalTimetable(a.DB, id)
+		if err != nil {
+			return "", err
+		}
+		if timetable.Title != "" {
+			return timetable.Title, nil
+		}
+		return "Личное расписание", nil
+	}
+
+	for _, item := range scheduleItems {
+		switch kind {
+		case kindGroup:
+			for _, g := range item.Groups {
+				if g.UUID == id {
+					return g.Name, nil
+				}
+			}
+		case kindTeacher:
+			for _, t := range item.Teachers {
+				if t.UUID == id {
+					return t.LastName + " " + t.FirstName + " " + t.MiddleName, nil
+				}
+			}
+		case kindAudience:
+			for _, au := range item.Audiences {
+				if au.UUID == id {
+					return au.Name, nil
+				}
+			}
+		}
+	}
+	return "Расписание", nil
+}
@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kosttiik/semesterly_backend/internal/importer"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const maxSourceTag = 64
+
+// ImportScheduleHandler загружает расписание из файла для подразделений, которых нет в lks
+// @Summary Загрузка расписания из файла
+// @Description Принимает CSV (столбцы прежней выгрузки writeToCSV или /export/schedule.csv), сетку XLSX в раскладке /export/schedule.xlsx
+// @Description или JSON в формате ответа lks. Занятия сохраняются тем же путем, что и данные lks, и помечаются источником upload:<source>.
+// @Description Повторная загрузка с тем же source заменяет занятия этого источника у групп из файла, синхронизация с lks их не затрагивает.
+// @Description Если хотя бы одна строка не прошла проверку, ничего не сохраняется и возвращаются ошибки строк.
+// @Description Группы, преподаватели и аудитории без UUID сопоставляются с существующими по названию, новым назначается постоянный UUID.
+// @Description Экзамен группы, которой нет ни в файле, ни в базе данных, считается ошибкой строки
+// @Tags Import
+// @Accept multipart/form-data
+// @Produce json
+// @Security AdminToken
+// @Param file formData file true "Файл расписания"
+// @Param format formData string false "Формат: csv, xlsx или json, по умолчанию по расширению файла"
+// @Param source formData string false "Тег источника, по умолчанию имя файла"
+// @Param dryRun formData bool false "Только проверить файл, не сохраняя"
+// @Success 200 {object} map[string]any "message: Schedule imported successfully, source, items, exams, groups, conflicts: [conflicts.Conflict], slotMismatches: [repository.SlotMismatch]"
+// @Failure 400 {object} map[string]string "error: Invalid import file"
+// @Failure 409 {object} map[string]string "error: Sync is already in progress"
+// @Failure 422 {object} map[string]any "error: Invalid rows, rowErrors: [importer.RowError]"
+// @Failure 500 {object} map[string]string "error: Failed to save schedule"
+// @Router /admin/import [post]
+func (a *App) ImportScheduleHandler(c echo.Context) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "File is required"})
+	}
+	format := c.FormValue("format")
+	if format == "" {
+		format = importer.FormatFromName(file.Filename)
+	}
+	if format == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unknown file format, set format to csv, xlsx or json"})
+	}
+	tag := strings.TrimSpace(c.FormValue("source"))
+	if tag == "" {
+		tag = strings.TrimSuffix(filepath.Base(file.Filename), filepath.Ext(file.Filename))
+	}
+	if r := []rune(tag); len(r) > maxSourceTag {
+		tag = string(r[:maxSourceTag])
+	}
+	dryRun, _ := strconv.ParseBool(c.FormValue("dryRun"))
+
+	src, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to read file"})
+	}
+	defer src.Close()
+
+	result, err := importer.Parse(format, src)
+	switch {
+	case errors.Is(err, importer.ErrUnknownFormat), errors.Is(err, importer.ErrInvalidFile):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to read file"})
+	}
+	if len(result.Errors) > 0 {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]any{
+			"error":     "Invalid rows",
+			"rowErrors": result.Errors,
+		})
+	}
+	if len(result.Items) == 0 && len(result.Exams) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "No schedule items found"})
+	}
+	if dryRun {
+		return c.JSON(http.StatusOK, map[string]any{
+			"message": "File is valid",
+			"items":   len(result.Items),
+			"exams":   len(result.Exams),
+		})
+	}
+
+	// Загрузка и синхронизация с lks не выполняются одновременно
+	if !a.syncing.CompareAndSwap(false, true) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Sync is already in progress"})
+	}
+	defer a.syncing.Store(false)
+
+	if err := a.resolveImportRefs(result.Items, result.Exams); err != nil {
+		log.Printf("Failed to resolve import references: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to resolve groups, teachers and audiences"})
+	}
+
+	source := models.SourceUploadPrefix + tag
+	groupUUIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for i := range result.Items {
+		result.Items[i].Source = source
+		for _, group := range result.Items[i].Groups {
+			if !seen[group.UUID] {
+				seen[group.UUID] = true
+				groupUUIDs = append(groupUUIDs, group.UUID)
+			}
+		}
+	}
+
+	examGroups := make(map[string][]models.Exam)
+	for _, exam := range result.Exams {
+		for _, group := range exam.Groups {
+			examGroups[group.UUID] = append(examGroups[group.UUID], exam)
+		}
+	}
+	rowErrors, err := a.examsWithoutGroups(result, seen)
+	if err != nil {
+		log.Printf("Failed to check exam groups: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to resolve groups, teachers and audiences"})
+	}
+	if len(rowErrors) > 0 {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]any{
+			"error":     "Invalid rows",
+			"rowErrors": rowErrors,
+		})
+	}
+
+	// Занятия источника заменяются целиком: при ошибке сохранения остаются прежние данные
+	var removed []models.ScheduleItem
+	var mu sync.Mutex
+	errs := make([]string, 0)
+	if err := a.DB.Transaction(func(tx *gorm.DB) error {
+		for _, uuid := range groupUUIDs {
+			items, err := deleteGroupItems(tx, uuid, source)
+			if err != nil {
+				return err
+			}
+			removed = append(removed, items...)
+		}
+
+		if err := insertToDatabase(tx, result.Items, result.Exams, &mu, &errs); err != nil {
+			return err
+		}
+		if len(errs) > 0 {
+			return errors.New(strings.Join(errs, "; "))
+		}
+		for uuid, exams := range examGroups {
+			if err := linkExamsToGroup(tx, uuid, exams); err != nil {
+				return fmt.Errorf("failed to link exams to group %s: %w", uuid, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		log.Printf("Failed to replace schedule items of source %s: %v", source, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save schedule"})
+	}
+
+	for uuid := range examGroups {
+		if !seen[uuid] {
+			a.dataChanged(uuid)
+		}
+	}
+	if len(groupUUIDs) > 0 {
+		a.dataChanged(groupUUIDs[0], removed, result.Items)
+	}
+
+	mismatches, err := a.RefreshTimeSlots()
+	if err != nil {
+		log.Printf("Failed to refresh time slots: %v", err)
+	}
+	log.Printf("Imported %d schedule items and %d exams from %s", len(result.Items), len(result.Exams), source)
+
+	response := map[string]any{
+		"message":        "Schedule imported successfully",
+		"source":         source,
+		"items":          len(result.Items),
+		"exams":          len(result.Exams),
+		"groups":         len(groupUUIDs),
+		"conflicts":      a.detectConflicts(),
+		"slotMismatches": mismatches,
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// examsWithoutGroups возвращает ошибки строк экзаменов, группы которых нет ни в файле, ни в базе данных:
+// группа появляется вместе с занятиями, и без них экзамен не с чем связать
+func (a *App) examsWithoutGroups(result importer.Result, uploaded map[string]bool) ([]importer.RowError, error) {
+	known := make(map[string]bool, len(uploaded))
+	for uuid := range uploaded {
+		known[uuid] = true
+	}
+
+	rowErrors := make([]importer.RowError, 0)
+	for i, exam := range result.Exams {
+		for _, group := range exam.Groups {
+			if !known[group.UUID] {
+				var count int64
+				if err := a.DB.Model(&models.Group{}).Where("uuid = ?", group.UUID).Count(&count).Error; err != nil {
+					return nil, err
+				}
+				if count == 0 {
+					rowErrors = append(rowErrors, importer.RowError{
+						Row:     result.ExamRows[i],
+						Column:  "groups",
+						Message: fmt.Sprintf("group %q has no lessons, upload its schedule first", group.Name),
+					})
+					continue
+				}
+				known[group.UUID] = true
+			}
+		}
+	}
+	return rowErrors, nil
+}
+
+// resolveImportRefs подставляет UUID группам, преподавателям и аудиториям, известным только по названию:
+// существующую запись с тем же названием или постоянный UUID для новой
+func (a *App) resolveImportRefs(items []models.ScheduleItem, exams []models.Exam) error {
+	groups := make(map[string]models.Group)
+	resolveGroup := func(group *models.Group) error {
+		if group.UUID != "" {
+			return nil
+		}
+		if cached, ok := groups[group.Name]; ok {
+			*group = cached
+			return nil
+		}
+		var dbGroup models.Group
+		err := a.DB.Where("name = ?", group.Name).First(&dbGroup).Error
+		switch {
+		case err == nil:
+			group.UUID, group.DepartmentUID = dbGroup.UUID, dbGroup.DepartmentUID
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			group.UUID = importer.StableUUID("group", group.Name)
+		default:
+			return err
+		}
+		groups[group.Name] = *group
+		return nil
+	}
+
+	teachers := make(map[string]models.Teacher)
+	audiences := make(map[string]models.Audience)
+	for i := range items {
+		for j := range items[i].Groups {
+			if err := resolveGroup(&items[i].Groups[j]); err != nil {
+				return err
+			}
+		}
+
+		for j := range items[i].Teachers {
+			teacher := &items[i].Teachers[j]
+			if teacher.UUID != "" {
+				continue
+			}
+			name := strings.Join([]string{teacher.LastName, teacher.FirstName, teacher.MiddleName}, " ")
+			if cached, ok := teachers[name]; ok {
+				*teacher = cached
+				continue
+			}
+			match, err := a.findTeacher(*teacher)
+			if err != nil {
+				return err
+			}
+			if match != nil {
+				*teacher = *match
+			} else {
+				teacher.UUID = importer.StableUUID("teacher", name)
+			}
+			teachers[name] = *teacher
+		}
+
+		for j := range items[i].Audiences {
+			audience := &items[i].Audiences[j]
+			if audience.UUID != "" {
+				continue
+			}
+			if cached, ok := audiences[audience.Name]; ok {
+				*audience = cached
+				continue
+			}
+			var dbAudience models.Audience
+			err := a.DB.Where("name = ?", audience.Name).First(&dbAudience).Error
+			switch {
+			case err == nil:
+				*audience = dbAudience
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				audience.UUID = importer.StableUUID("audience", audience.Name)
+			default:
+				return err
+			}
+			audiences[audience.Name] = *audience
+		}
+	}
+
+	for i := range exams {
+		for j := range exams[i].Groups {
+			if err := resolveGroup(&exams[i].Groups[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findTeacher ищет преподавателя по полному имени, а если вместо имени и отчества инициалы (Иванов И. И.) -
+// по фамилии и первым буквам, если такой преподаватель единственный
+func (a *App) findTeacher(teacher models.Teacher) (*models.Teacher, error) {
+	var matches []models.Teacher
+	query := a.DB.Where("last_name = ?", teacher.LastName)
+	if strings.HasSuffix(teacher.FirstName, ".") {
+		if initial := strings.TrimSuffix(teacher.FirstName, "."); initial != "" {
+			query = query.Where("first_name LIKE ?", initial+"%")
+		}
+		if initial := strings.TrimSuffix(teacher.MiddleName, "."); initial != "" {
+			query = query.Where("middle_name LIKE ?", initial+"%")
+		}
+	} else {
+		query = query.Where("first_name = ? AND middle_name = ?", teacher.FirstName, teacher.MiddleName)
+	}
+	if err := query.Limit(2).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	if len(matches) != 1 {
+		return nil, nil
+	}
+	return &matches[0], nil
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/importer"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExamsWithoutGroups(t *testing.T) {
+	result := importer.Result{
+		Exams: []models.Exam{
+			{Groups: []models.Group{{UUID: "g1", Name: "ИУ7-11Б"}}},
+			{Groups: []models.Group{{UUID: "g2", Name: "ИУ7-12Б"}}},
+		},
+		ExamRows: []int{3, 4},
+	}
+
+	// Группы g2 нет в базе данных, g1 загружается вместе с занятиями
+	a := &App{DB: newTestDB(t, testConnector{})}
+	rowErrors, err := a.examsWithoutGroups(result, map[string]bool{"g1": true})
+	require.NoError(t, err)
+	assert.Equal(t, []importer.RowError{{
+		Row: 4, Column: "groups", Message: `group "ИУ7-12Б" has no lessons, upload its schedule first`,
+	}}, rowErrors)
+
+	a.DB = newTestDB(t, testConnector{rows: map[string]testRows{
+		"count": {columns: []string{"count"}, values: [][]driver.Value{{int64(1)}}},
+	}})
+	rowErrors, err = a.examsWithoutGroups(result, nil)
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+}
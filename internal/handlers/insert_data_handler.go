@@ -1,135 +1,63 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"sync"
-	"time"
 
 	"github.com/kosttiik/semesterly_backend/internal/models"
 	"github.com/kosttiik/semesterly_backend/internal/utils"
 	"github.com/labstack/echo/v4"
-	"golang.org/x/time/rate"
 	"gorm.io/gorm"
 )
 
 // InsertDataHandler обрабатывает вставку данных в базу данных
 // @Summary Вставка данных
-// @Description Вставляет данные расписания и экзаменов в базу данных
+// @Description Вставляет данные расписания и экзаменов в базу данных и проверяет расписание на конфликты
 // @Tags InsertData
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]string "message: Data inserted successfully"
+// @Success 200 {object} map[string]interface{} "message: Data inserted successfully, conflicts: [conflicts.Conflict], slotMismatches: [repository.SlotMismatch]"
+// @Failure 409 {object} map[string]string "error: Sync is already in progress"
 // @Failure 500 {object} map[string]interface{} "errors: [error messages]"
 // @Router /insert-data [post]
 func (a *App) InsertDataHandler(c echo.Context) error {
-	structureURL := "https://lks.bmstu.ru/lks-back/api/v1/structure"
-	var structure models.Structure
-
-	if err := utils.FetchJSON(structureURL, &structure); err != nil {
-		log.Printf("Failed to fetch structure: %v", err)
+	result, err := a.Sync()
+	switch {
+	case errors.Is(err, ErrSyncInProgress):
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Sync is already in progress"})
+	case errors.Is(err, ErrFetchStructure):
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch structure"})
-	}
-
-	groupUUIDs := utils.ExtractGroupUUIDs(structure.Data.Children)
-	log.Printf("Fetched %d group UUIDs", len(groupUUIDs))
-
-	totalItems := len(groupUUIDs)
-	if totalItems == 0 {
+	case errors.Is(err, ErrNoGroups):
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "No groups found"})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	completed := 0
-	startTime := time.Now()
-
-	var mu sync.Mutex
-
-	// Отправляем начальное состояние прогресса
-	mu.Lock()
-	a.Hub.BroadcastProgress(ProgressUpdate{
-		Type:           "insertProgress",
-		CurrentItem:    0,
-		TotalItems:     totalItems,
-		CompletedItems: 0,
-		Percentage:     0,
-		ETA:            "Calculating...",
-	})
-	mu.Unlock()
-
-	var wg sync.WaitGroup
-	errors := make([]string, 0)
-	sem := make(chan struct{}, 10)                                   // Ограничение в 10 горутин
-	limiter := rate.NewLimiter(rate.Every(100*time.Millisecond), 10) // 10 запросов в 100 миллисекунд
-
-	for _, uuid := range groupUUIDs {
-		sem <- struct{}{}
-		wg.Add(1)
-		go func(uuid string) {
-			defer wg.Done()
-			defer func() { <-sem }()
-
-			// Ожидание разрешения от rate limiter
-			if err := limiter.Wait(context.Background()); err != nil {
-				log.Printf("Rate limiter error for group %s: %v", uuid, err)
-				utils.AppendError(&mu, &errors, fmt.Sprintf("Rate limiter error for group %s: %v", uuid, err))
-				return
-			}
-
-			if err := a.processGroupData(uuid, &mu, &errors); err != nil {
-				log.Printf("Failed to process data for group %s: %v", uuid, err)
-				utils.AppendError(&mu, &errors, fmt.Sprintf("Group %s: %v", uuid, err))
-			}
-
-			mu.Lock()
-			completed++
-			elapsed := time.Since(startTime)
-			itemsPerSecond := float64(completed) / elapsed.Seconds()
-			remainingItems := totalItems - completed
-			eta := time.Duration(float64(remainingItems)/itemsPerSecond) * time.Second
-
-			// Отправляем состояние прогресса
-			a.Hub.BroadcastProgress(ProgressUpdate{
-				Type:           "insertProgress",
-				CurrentItem:    completed,
-				TotalItems:     totalItems,
-				CompletedItems: completed,
-				Percentage:     float64(completed) / float64(totalItems) * 100,
-				ETA:            eta.Round(time.Second).String(),
-			})
-			mu.Unlock()
-		}(uuid)
-	}
-
-	wg.Wait()
-
-	// Финальное состояние прогресса
-	a.Hub.BroadcastProgress(ProgressUpdate{
-		Type:           "insertProgress",
-		CurrentItem:    totalItems,
-		TotalItems:     totalItems,
-		CompletedItems: totalItems,
-		Percentage:     100,
-		ETA:            "0s",
-	})
-
-	if len(errors) > 0 {
-		if completed == 0 {
+	if len(result.Errors) > 0 {
+		if result.Completed == 0 {
 			return c.JSON(http.StatusInternalServerError, map[string]string{
 				"error":   "All groups failed to process",
-				"details": fmt.Sprintf("%v", errors),
+				"details": fmt.Sprintf("%v", result.Errors),
 			})
 		}
 		return c.JSON(http.StatusOK, map[string]interface{}{
-			"message":   "Partially completed with errors",
-			"completed": completed,
-			"total":     totalItems,
-			"errors":    errors,
+			"message":        "Partially completed with errors",
+			"completed":      result.Completed,
+			"total":          result.Total,
+			"errors":         result.Errors,
+			"conflicts":      a.detectConflicts(),
+			"slotMismatches": result.SlotMismatches,
 		})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "Data inserted successfully"})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":        "Data inserted successfully",
+		"conflicts":      a.detectConflicts(),
+		"slotMismatches": result.SlotMismatches,
+	})
 }
 
 func (a *App) processGroupData(uuid string, mu *sync.Mutex, errors *[]string) error {
@@ -158,7 +86,7 @@ func (a *App) processGroupData(uuid string, mu *sync.Mutex, errors *[]string) er
 		Preload("Disciplines").
 		Joins("JOIN schedule_item_groups ON schedule_item_groups.schedule_item_id = schedule_items.id").
 		Joins("JOIN groups ON groups.id = schedule_item_groups.group_id").
-		Where("groups.uuid = ?", uuid).
+		Where("groups.uuid = ? AND schedule_items.source = ?", uuid, models.SourceLKS).
 		Find(&existingSchedules).Error; err != nil {
 		utils.AppendError(mu, errors, fmt.Sprintf("Failed to fetch existing schedules for group %s", err))
 		return err
@@ -183,44 +111,15 @@ func (a *App) processGroupData(uuid string, mu *sync.Mutex, errors *[]string) er
 		changes = true
 
 		if err := a.DB.Transaction(func(tx *gorm.DB) error {
-			var existingItems []models.ScheduleItem
-			if err := tx.
-				Preload("Groups", "groups.uuid = ?", uuid).
-				Joins("JOIN schedule_item_groups ON schedule_items.id = schedule_item_groups.schedule_item_id").
-				Joins("JOIN groups ON groups.id = schedule_item_groups.group_id").
-				Where("groups.uuid = ?", uuid).
-				Find(&existingItems).Error; err != nil {
-				return err
-			}
-
-			for _, item := range existingItems {
-				// Удаляем ассоциации с группами, преподавателями, аудиториями и дисциплинами
-				if err := tx.Model(&item).Association("Disciplines").Clear(); err != nil {
-					return err
-				}
-				if err := tx.Model(&item).Association("Teachers").Clear(); err != nil {
-					return err
-				}
-				if err := tx.Model(&item).Association("Audiences").Clear(); err != nil {
-					return err
-				}
-				if err := tx.Model(&item).Association("Groups").Clear(); err != nil {
-					return err
-				}
-
-				// Удаляем элемент расписания
-				if err := tx.Delete(&item).Error; err != nil {
-					return err
-				}
-			}
-			return nil
+			_, err := deleteGroupItems(tx, uuid, models.SourceLKS)
+			return err
 		}); err != nil {
 			utils.AppendError(mu, errors, fmt.Sprintf("Failed to clean up old schedule items for group %s: %v", uuid, err))
 			return err
 		}
 
 		// Вставляем новые данные
-		if err := a.insertToDatabase(schedule.Data.Schedule, exams.Data, mu, errors); err != nil {
+		if err := insertToDatabase(a.DB, schedule.Data.Schedule, exams.Data, mu, errors); err != nil {
 			return err
 		}
 	} else {
@@ -234,7 +133,12 @@ func (a *App) processGroupData(uuid string, mu *sync.Mutex, errors *[]string) er
 		}
 	}
 
+	if err := linkExamsToGroup(a.DB, uuid, exams.Data); err != nil {
+		utils.AppendError(mu, errors, fmt.Sprintf("Failed to link exams to group %s: %v", uuid, err))
+	}
+
 	if changes {
+		a.dataChanged(uuid, existingSchedules, schedule.Data.Schedule)
 		log.Printf("Updated data for group %s - found changes", uuid)
 	} else {
 		log.Printf("No changes needed for group %s", uuid)
@@ -243,6 +147,51 @@ func (a *App) processGroupData(uuid string, mu *sync.Mutex, errors *[]string) er
 	return nil
 }
 
+// deleteGroupItems удаляет занятия группы из источника source вместе с их связями и возвращает удаленные занятия
+func deleteGroupItems(tx *gorm.DB, uuid, source string) ([]models.ScheduleItem, error) {
+	var existingItems []models.ScheduleItem
+	if err := tx.
+		Preload("Groups").
+		Preload("Teachers").
+		Preload("Audiences").
+		Joins("JOIN schedule_item_groups ON schedule_items.id = schedule_item_groups.schedule_item_id").
+		Joins("JOIN groups ON groups.id = schedule_item_groups.group_id").
+		Where("groups.uuid = ? AND schedule_items.source = ?", uuid, source).
+		Find(&existingItems).Error; err != nil {
+		return nil, err
+	}
+
+	for _, item := range existingItems {
+		// Удаляем ассоциации с группами, преподавателями, аудиториями и дисциплинами
+		if err := tx.Model(&item).Association("Disciplines").Clear(); err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&item).Association("Teachers").Clear(); err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&item).Association("Audiences").Clear(); err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&item).Association("Groups").Clear(); err != nil {
+			return nil, err
+		}
+
+		// Удаляем элемент расписания
+		if err := tx.Delete(&item).Error; err != nil {
+			return nil, err
+		}
+	}
+	return existingItems, nil
+}
+
+// itemSource возвращает источник занятия, по умолчанию lks
+func itemSource(item models.ScheduleItem) string {
+	if item.Source == "" {
+		return models.SourceLKS
+	}
+	return item.Source
+}
+
 // compareSchedules сравнивает два слайса элементов расписания
 func compareSchedules(existing []models.ScheduleItem, new []models.ScheduleItem) bool {
 	if len(existing) != len(new) {
@@ -252,17 +201,12 @@ func compareSchedules(existing []models.ScheduleItem, new []models.ScheduleItem)
 	// Создаем map для быстрого поиска
 	existingMap := make(map[string]models.ScheduleItem)
 	for _, item := range existing {
-		key := fmt.Sprintf("%d-%d-%s-%s-%s-%s",
-			item.Day, item.Time, item.Week, item.StartTime, item.EndTime, item.Stream)
-		existingMap[key] = item
+		existingMap[slotKey(item)] = item
 	}
 
 	// Сравниваем каждый новый элемент
 	for _, item := range new {
-		key := fmt.Sprintf("%d-%d-%s-%s-%s-%s",
-			item.Day, item.Time, item.Week, item.StartTime, item.EndTime, item.Stream)
-
-		existingItem, exists := existingMap[key]
+		existingItem, exists := existingMap[slotKey(item)]
 		if !exists {
 			return false
 		}
@@ -279,6 +223,12 @@ func compareSchedules(existing []models.ScheduleItem, new []models.ScheduleItem)
 	return true
 }
 
+// slotKey - ключ занятия по времени и потоку, без участников
+func slotKey(item models.ScheduleItem) string {
+	return fmt.Sprintf("%d-%d-%s-%s-%s-%s",
+		item.Day, item.Time, item.Week, item.StartTime, item.EndTime, item.Stream)
+}
+
 func compareGroups(a, b []models.Group) bool {
 	if len(a) != len(b) {
 		return false
@@ -368,6 +318,43 @@ func compareExams(existing []models.Exam, new []models.Exam) bool {
 	return true
 }
 
+// linkExamsToGroup связывает экзамены с группой, для которой они загружены. Существующие связи не дублируются
+func linkExamsToGroup(db *gorm.DB, uuid string, examItems []models.Exam) error {
+	if len(examItems) == 0 {
+		return nil
+	}
+
+	var group models.Group
+	if err := db.Where("uuid = ?", uuid).First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Группа появляется вместе с занятиями, без них связывать не с чем
+		}
+		return err
+	}
+
+	for _, item := range examItems {
+		var exam models.Exam
+		err := db.Where(&models.Exam{
+			Room:       item.Room,
+			ExamDate:   item.ExamDate,
+			ExamTime:   item.ExamTime,
+			LastName:   item.LastName,
+			FirstName:  item.FirstName,
+			MiddleName: item.MiddleName,
+		}).First(&exam).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := db.Model(&exam).Association("Groups").Append(&group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *App) insertExamsToDatabase(examItems []models.Exam, mu *sync.Mutex, errors *[]string) error {
 	for _, item := range examItems {
 		// Сохраняем дисциплину
@@ -412,13 +399,13 @@ func (a *App) insertExamsToDatabase(examItems []models.Exam, mu *sync.Mutex, err
 	return nil
 }
 
-func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []models.Exam, mu *sync.Mutex, errors *[]string) error {
+func insertToDatabase(db *gorm.DB, scheduleItems []models.ScheduleItem, examItems []models.Exam, mu *sync.Mutex, errors *[]string) error {
 	var insertedScheduleItems, insertedExamItems int
 
 	for _, item := range scheduleItems {
 		// Сохраняем дисциплину
 		var dbDiscipline models.Discipline
-		if err := a.DB.Where("abbr = ? AND act_type = ? AND full_name = ? AND short_name = ?",
+		if err := db.Where("abbr = ? AND act_type = ? AND full_name = ? AND short_name = ?",
 			item.DisciplineRaw.Abbr, item.DisciplineRaw.ActType, item.DisciplineRaw.FullName, item.DisciplineRaw.ShortName).
 			FirstOrCreate(&dbDiscipline, models.Discipline{
 				Abbr:      item.DisciplineRaw.Abbr,
@@ -439,11 +426,12 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 			StartTime:  item.StartTime,
 			EndTime:    item.EndTime,
 			Permission: item.Permission,
+			Source:     itemSource(item),
 		}
 
 		// Ищем или создаем элемент расписания
 		var existingItem models.ScheduleItem
-		if err := a.DB.Where(&models.ScheduleItem{
+		if err := db.Where(&models.ScheduleItem{
 			Day:        newItem.Day,
 			Time:       newItem.Time,
 			Week:       newItem.Week,
@@ -451,6 +439,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 			StartTime:  newItem.StartTime,
 			EndTime:    newItem.EndTime,
 			Permission: newItem.Permission,
+			Source:     newItem.Source,
 		}).FirstOrCreate(&existingItem).Error; err != nil {
 			utils.AppendError(mu, errors, fmt.Sprintf("Failed to insert schedule item: %v", err))
 			continue
@@ -458,14 +447,14 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 		insertedScheduleItems++
 
 		// Связываем дисциплину с элементом расписания
-		if err := a.DB.Model(&existingItem).Association("Disciplines").Append(&dbDiscipline); err != nil {
+		if err := db.Model(&existingItem).Association("Disciplines").Append(&dbDiscipline); err != nil {
 			utils.AppendError(mu, errors, fmt.Sprintf("Failed to associate discipline with schedule item: %v", err))
 		}
 
 		// Ассоциация с группами
 		for _, group := range item.Groups {
 			var dbGroup models.Group
-			if err := a.DB.Where("uuid = ?", group.UUID).FirstOrCreate(&dbGroup, models.Group{
+			if err := db.Where("uuid = ?", group.UUID).FirstOrCreate(&dbGroup, models.Group{
 				Name:          group.Name,
 				UUID:          group.UUID,
 				DepartmentUID: group.DepartmentUID,
@@ -473,7 +462,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 				utils.AppendError(mu, errors, fmt.Sprintf("Failed to insert group %s: %v", group.UUID, err))
 				continue
 			}
-			if err := a.DB.Model(&existingItem).Association("Groups").Append(&dbGroup); err != nil {
+			if err := db.Model(&existingItem).Association("Groups").Append(&dbGroup); err != nil {
 				utils.AppendError(mu, errors, fmt.Sprintf("Failed to associate group %s with schedule item: %v", group.UUID, err))
 			}
 		}
@@ -481,7 +470,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 		// Ассоциация с преподавателями
 		for _, teacher := range item.Teachers {
 			var dbTeacher models.Teacher
-			if err := a.DB.Where("uuid = ?", teacher.UUID).FirstOrCreate(&dbTeacher, models.Teacher{
+			if err := db.Where("uuid = ?", teacher.UUID).FirstOrCreate(&dbTeacher, models.Teacher{
 				UUID:       teacher.UUID,
 				LastName:   teacher.LastName,
 				FirstName:  teacher.FirstName,
@@ -490,7 +479,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 				utils.AppendError(mu, errors, fmt.Sprintf("Failed to insert teacher %s: %v", teacher.UUID, err))
 				continue
 			}
-			if err := a.DB.Model(&existingItem).Association("Teachers").Append(&dbTeacher); err != nil {
+			if err := db.Model(&existingItem).Association("Teachers").Append(&dbTeacher); err != nil {
 				utils.AppendError(mu, errors, fmt.Sprintf("Failed to associate teacher %s with schedule item: %v", teacher.UUID, err))
 			}
 		}
@@ -498,7 +487,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 		// Ассоциация с аудиториями
 		for _, audience := range item.Audiences {
 			var dbAudience models.Audience
-			if err := a.DB.Where("uuid = ?", audience.UUID).FirstOrCreate(&dbAudience, models.Audience{
+			if err := db.Where("uuid = ?", audience.UUID).FirstOrCreate(&dbAudience, models.Audience{
 				Name:          audience.Name,
 				UUID:          audience.UUID,
 				Building:      audience.Building,
@@ -507,7 +496,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 				utils.AppendError(mu, errors, fmt.Sprintf("Failed to insert audience %s: %v", audience.UUID, err))
 				continue
 			}
-			if err := a.DB.Model(&existingItem).Association("Audiences").Append(&dbAudience); err != nil {
+			if err := db.Model(&existingItem).Association("Audiences").Append(&dbAudience); err != nil {
 				utils.AppendError(mu, errors, fmt.Sprintf("Failed to associate audience %s with schedule item: %v", audience.UUID, err))
 			}
 		}
@@ -516,7 +505,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 	for _, item := range examItems {
 		// Сохраняем дисциплину
 		var dbDiscipline models.Discipline
-		if err := a.DB.Where("full_name = ?", item.DisciplineRaw).
+		if err := db.Where("full_name = ?", item.DisciplineRaw).
 			FirstOrCreate(&dbDiscipline, models.Discipline{
 				FullName: item.DisciplineRaw,
 			}).Error; err != nil {
@@ -535,7 +524,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 		}
 
 		var existingExam models.Exam
-		if err := a.DB.Where(&models.Exam{
+		if err := db.Where(&models.Exam{
 			Room:       newExam.Room,
 			ExamDate:   newExam.ExamDate,
 			ExamTime:   newExam.ExamTime,
@@ -549,7 +538,7 @@ func (a *App) insertToDatabase(scheduleItems []models.ScheduleItem, examItems []
 		insertedExamItems++
 
 		// Связываем дисциплину с экзаменом
-		if err := a.DB.Model(&existingExam).Association("Disciplines").Append(&dbDiscipline); err != nil {
+		if err := db.Model(&existingExam).Association("Disciplines").Append(&dbDiscipline); err != nil {
 			utils.AppendError(mu, errors, fmt.Sprintf("Failed to associate discipline with exam: %v", err))
 		}
 	}
@@ -13,12 +13,12 @@ import (
 
 // InsertGroupScheduleHandler обрабатывает вставку расписания для конкретной группы в базу данных
 // @Summary Вставка расписания группы
-// @Description Вставляет данные расписания и экзаменов для конкретной группы в базу данных
+// @Description Вставляет данные расписания и экзаменов для конкретной группы в базу данных и проверяет занятия группы на конфликты
 // @Tags InsertGroupSchedule
 // @Accept json
 // @Produce json
 // @Param uuid path string true "UUID группы"
-// @Success 200 {object} map[string]string "message: Group schedule inserted successfully"
+// @Success 200 {object} map[string]any "message: Group schedule inserted successfully, conflicts: [conflicts.Conflict], slotMismatches: [repository.SlotMismatch]"
 // @Failure 500 {object} map[string]interface{} "errors: [error messages]"
 // @Router /insert-group-schedule/{uuid} [post]
 func (a *App) InsertGroupScheduleHandler(c echo.Context) error {
@@ -41,7 +41,16 @@ func (a *App) InsertGroupScheduleHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]any{"errors": errors})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "Group schedule inserted successfully"})
+	mismatches, err := a.RefreshTimeSlots()
+	if err != nil {
+		log.Printf("Failed to refresh time slots: %v", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":        "Group schedule inserted successfully",
+		"conflicts":      a.detectGroupConflicts(uuid),
+		"slotMismatches": mismatches,
+	})
 }
 
 // processGroupScheduleData обрабатывает данные расписания и экзаменов для группы
@@ -67,6 +76,10 @@ func (a *App) processGroupScheduleData(uuid string, mu *sync.Mutex, errors *[]st
 	if err := a.insertGroupToDatabase(schedule.Data.Schedule, exams.Data, mu, errors); err != nil {
 		return err
 	}
+	if err := linkExamsToGroup(a.DB, uuid, exams.Data); err != nil {
+		utils.AppendError(mu, errors, fmt.Sprintf("Failed to link exams to group %s: %v", uuid, err))
+	}
+	a.dataChanged(uuid, schedule.Data.Schedule)
 
 	return nil
 }
@@ -99,6 +112,7 @@ func (a *App) insertGroupToDatabase(scheduleItems []models.ScheduleItem, examIte
 			StartTime:  item.StartTime,
 			EndTime:    item.EndTime,
 			Permission: item.Permission,
+			Source:     itemSource(item),
 		}
 
 		// Ищем или создаем элемент расписания
@@ -111,6 +125,7 @@ func (a *App) insertGroupToDatabase(scheduleItems []models.ScheduleItem, examIte
 			StartTime:  newItem.StartTime,
 			EndTime:    newItem.EndTime,
 			Permission: newItem.Permission,
+			Source:     newItem.Source,
 		}).FirstOrCreate(&existingItem).Error; err != nil {
 			utils.AppendError(mu, errors, fmt.Sprintf("Failed to insert schedule item: %v", err))
 			continue
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Эндпоинты /lks-back/api/v1/... повторяют публичное API lks, чтобы сторонние инструменты могли
+// обращаться к серверу вместо портала университета. Ответы собираются из базы данных
+// в форматах models.Structure, models.Schedule и models.ExamResponse
+
+// lksScheduleItem - занятие в том виде, в котором его отдает lks: с одной дисциплиной в поле discipline.
+// models.ScheduleItem сериализуется в собственном формате API со списком disciplines
+type lksScheduleItem struct {
+	Day        int               `json:"day"`
+	Time       int               `json:"time"`
+	Week       string            `json:"week"`
+	Groups     []models.Group    `json:"groups"`
+	Stream     string            `json:"stream"`
+	EndTime    string            `json:"endTime"`
+	Teachers   []models.Teacher  `json:"teachers"`
+	Audiences  []models.Audience `json:"audiences"`
+	StartTime  string            `json:"startTime"`
+	Discipline models.Discipline `json:"discipline"`
+	Permission string            `json:"permission"`
+}
+
+// lksSchedule повторяет models.Schedule с занятиями в формате lks
+type lksSchedule struct {
+	Data struct {
+		Type     string            `json:"type"`
+		UUID     string            `json:"uuid"`
+		Title    string            `json:"title"`
+		Schedule []lksScheduleItem `json:"schedule"`
+	} `json:"data"`
+	Date time.Time `json:"date"`
+}
+
+// lksExam - экзамен в том виде, в котором его отдает lks: с названием дисциплины в поле discipline,
+// без id и списков дисциплин и групп, которые есть у models.Exam
+type lksExam struct {
+	Room       string `json:"room"`
+	ExamDate   string `json:"examDate"`
+	ExamTime   string `json:"examTime"`
+	LastName   string `json:"lastName"`
+	FirstName  string `json:"firstName"`
+	MiddleName string `json:"middleName"`
+	Discipline string `json:"discipline"`
+}
+
+// lksExamResponse повторяет models.ExamResponse с экзаменами в формате lks
+type lksExamResponse struct {
+	Data []lksExam `json:"data"`
+	Date string    `json:"date"`
+}
+
+// GetMirrorStructureHandler отдает сохраненное дерево структуры университета как GET /lks-back/api/v1/structure
+func (a *App) GetMirrorStructureHandler(c echo.Context) error {
+	if a.notModified(c, models.GlobalDataScope) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	structure, err := repository.LoadStructure(a.DB)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch structure"})
+	}
+	return c.JSON(http.StatusOK, structure)
+}
+
+// GetMirrorGroupScheduleHandler отдает расписание группы как GET /lks-back/api/v1/schedules/groups/:uuid/public
+func (a *App) GetMirrorGroupScheduleHandler(c echo.Context) error {
+	uuid := c.Param("uuid")
+
+	group, err := a.mirrorGroup(uuid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch group"})
+	}
+
+	if a.notModified(c, uuid) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	scheduleItems, err := a.loadSchedule(kindGroup, uuid)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+	}
+
+	return c.JSON(http.StatusOK, newLKSSchedule(group, scheduleItems, a.mirrorDate(uuid)))
+}
+
+// GetMirrorGroupExamsHandler отдает экзамены группы как GET /lks-back/api/v1/schedules/exams/:uuid/public
+func (a *App) GetMirrorGroupExamsHandler(c echo.Context) error {
+	uuid := c.Param("uuid")
+
+	if _, err := a.mirrorGroup(uuid); errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch group"})
+	}
+
+	if a.notModified(c, uuid) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	exams, err := repository.GroupExams(a.DB, uuid)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch exams"})
+	}
+
+	return c.JSON(http.StatusOK, lksExamResponse{
+		Data: lksExams(exams),
+		Date: a.mirrorDate(uuid).Format(time.RFC3339),
+	})
+}
+
+func (a *App) mirrorGroup(uuid string) (models.Group, error) {
+	var group models.Group
+	err := a.DB.Where("uuid = ?", uuid).First(&group).Error
+	return group, err
+}
+
+// mirrorDate - время последнего изменения данных группы, если оно известно
+func (a *App) mirrorDate(uuid string) time.Time {
+	if version, err := repository.DataVersionOf(a.DB, uuid); err == nil && !version.UpdatedAt.IsZero() {
+		return version.UpdatedAt
+	}
+	return time.Now()
+}
+
+// newLKSSchedule собирает ответ lks для группы. Занятие с несколькими дисциплинами
+// разворачивается обратно в занятия по одной дисциплине, как они приходят из lks
+func newLKSSchedule(group models.Group, scheduleItems []models.ScheduleItem, date time.Time) lksSchedule {
+	var schedule lksSchedule
+	schedule.Data.Type = "group"
+	schedule.Data.UUID = group.UUID
+	schedule.Data.Title = group.Name
+	schedule.Data.Schedule = make([]lksScheduleItem, 0, len(scheduleItems))
+	schedule.Date = date
+
+	for _, item := range scheduleItems {
+		groups := make([]models.Group, len(item.Groups))
+		for i, g := range item.Groups {
+			groups[i] = models.Group{Name: g.Name, UUID: g.UUID, DepartmentUID: g.DepartmentUID}
+		}
+		teachers := make([]models.Teacher, len(item.Teachers))
+		for i, t := range item.Teachers {
+			teachers[i] = models.Teacher{UUID: t.UUID, LastName: t.LastName, FirstName: t.FirstName, MiddleName: t.MiddleName}
+		}
+		audiences := make([]models.Audience, len(item.Audiences))
+		for i, au := range item.Audiences {
+			audiences[i] = models.Audience{Name: au.Name, UUID: au.UUID, Building: au.Building, DepartmentUID: au.DepartmentUID}
+		}
+
+		for _, d := range item.Disciplines {
+			schedule.Data.Schedule = append(schedule.Data.Schedule, lksScheduleItem{
+				Day:        item.Day,
+				Time:       item.Time,
+				Week:       item.Week,
+				Groups:     groups,
+				Stream:     item.Stream,
+				EndTime:    item.EndTime,
+				Teachers:   teachers,
+				Audiences:  audiences,
+				StartTime:  item.StartTime,
+				Discipline: models.Discipline{Abbr: d.Abbr, ActType: d.ActType, FullName: d.FullName, ShortName: d.ShortName},
+				Permission: item.Permission,
+			})
+		}
+	}
+	return schedule
+}
+
+// lksExams возвращает экзамены в формате lks: по одному на дисциплину, с названием в поле discipline
+func lksExams(exams []models.Exam) []lksExam {
+	result := make([]lksExam, 0, len(exams))
+	for _, exam := range exams {
+		for _, d := range exam.Disciplines {
+			result = append(result, lksExam{
+				Room:       exam.Room,
+				ExamDate:   exam.ExamDate,
+				ExamTime:   exam.ExamTime,
+				LastName:   exam.LastName,
+				FirstName:  exam.FirstName,
+				MiddleName: exam.MiddleName,
+				Discipline: d.FullName,
+			})
+		}
+	}
+	return result
+}
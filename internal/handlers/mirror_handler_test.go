@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLKSScheduleDecodesAsUpstream(t *testing.T) {
+	group := models.Group{ID: 7, Name: "ИУ7-11Б", UUID: "g-1", DepartmentUID: "d-1"}
+	item := models.ScheduleItem{
+		ID: 3, Day: 2, Time: 1, Week: models.WeekNumerator, Stream: "s", StartTime: "08:30", EndTime: "10:05",
+		Groups:    []models.Group{group},
+		Teachers:  []models.Teacher{{ID: 4, UUID: "t-1", LastName: "Иванов", FirstName: "Иван", MiddleName: "Иванович"}},
+		Audiences: []models.Audience{{ID: 5, UUID: "a-1", Name: "501ю"}},
+		Disciplines: []models.Discipline{
+			{ID: 1, FullName: "Физика", ActType: "lab"},
+			{ID: 2, FullName: "Химия", ActType: "lab"},
+		},
+		Source: models.SourceLKS,
+	}
+	date := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	data, err := json.Marshal(newLKSSchedule(group, []models.ScheduleItem{item}, date))
+	require.NoError(t, err)
+
+	// Ответ читается тем же кодом, что и данные lks при синхронизации
+	var schedule models.Schedule
+	require.NoError(t, json.Unmarshal(data, &schedule))
+	assert.Equal(t, "group", schedule.Data.Type)
+	assert.Equal(t, "g-1", schedule.Data.UUID)
+	assert.Equal(t, "ИУ7-11Б", schedule.Data.Title)
+	assert.True(t, date.Equal(schedule.Date))
+
+	require.Len(t, schedule.Data.Schedule, 2)
+	first := schedule.Data.Schedule[0]
+	assert.Equal(t, models.Discipline{FullName: "Физика", ActType: "lab"}, first.DisciplineRaw)
+	assert.Equal(t, "Химия", schedule.Data.Schedule[1].DisciplineRaw.FullName)
+	assert.Equal(t, []models.Group{{Name: "ИУ7-11Б", UUID: "g-1", DepartmentUID: "d-1"}}, first.Groups)
+	assert.Zero(t, first.ID)
+	assert.Zero(t, first.Teachers[0].ID)
+	assert.Empty(t, first.Disciplines)
+	assert.Empty(t, first.Source)
+	assert.NotContains(t, string(data), `"source"`)
+}
+
+func TestLKSExams(t *testing.T) {
+	exams := lksExams([]models.Exam{{
+		ID: 9, Room: "218л", ExamDate: "2025-01-15", ExamTime: "09:00", LastName: "Петров",
+		Disciplines: []models.Discipline{{ID: 1, FullName: "Математический анализ"}},
+		Groups:      []models.Group{{UUID: "g-1"}},
+	}})
+
+	data, err := json.Marshal(exams)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"room":"218л","examDate":"2025-01-15","examTime":"09:00",`+
+		`"lastName":"Петров","firstName":"","middleName":"","discipline":"Математический анализ"}]`, string(data))
+}
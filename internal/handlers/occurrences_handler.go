@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetGroupOccurrencesHandler отправляет JSON с занятиями группы на конкретные даты
+// @Summary Занятия группы по датам
+// @Description Раскрывает расписание группы в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.
+// @Description Без параметров from и to возвращаются занятия на ближайшие 7 дней.
+// @Tags Occurrences
+// @Accept json
+// @Produce json
+// @Param uuid path string true "UUID группы"
+// @Param from query string false "Начало периода в формате YYYY-MM-DD"
+// @Param to query string false "Конец периода в формате YYYY-MM-DD"
+// @Success 200 {array} calendar.Occurrence "Список занятий"
+// @Failure 400 {object} map[string]string "error: Invalid date range"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /groups/{uuid}/occurrences [get]
+func (a *App) GetGroupOccurrencesHandler(c echo.Context) error {
+	return a.occurrences(c, kindGroup)
+}
+
+// GetTeacherOccurrencesHandler отправляет JSON с занятиями преподавателя на конкретные даты
+// @Summary Занятия преподавателя по датам
+// @Description Раскрывает расписание преподавателя в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.
+// @Description Без параметров from и to возвращаются занятия на ближайшие 7 дней.
+// @Tags Occurrences
+// @Accept json
+// @Produce json
+// @Param uuid path string true "UUID преподавателя"
+// @Param from query string false "Начало периода в формате YYYY-MM-DD"
+// @Param to query string false "Конец периода в формате YYYY-MM-DD"
+// @Success 200 {array} calendar.Occurrence "Список занятий"
+// @Failure 400 {object} map[string]string "error: Invalid date range"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /teachers/{uuid}/occurrences [get]
+func (a *App) GetTeacherOccurrencesHandler(c echo.Context) error {
+	return a.occurrences(c, kindTeacher)
+}
+
+// GetAudienceOccurrencesHandler отправляет JSON с занятиями в аудитории на конкретные даты
+// @Summary Занятия в аудитории по датам
+// @Description Раскрывает расписание аудитории в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.
+// @Description Без параметров from и to возвращаются занятия на ближайшие 7 дней.
+// @Tags Occurrences
+// @Accept json
+// @Produce json
+// @Param uuid path string true "UUID аудитории"
+// @Param from query string false "Начало периода в формате YYYY-MM-DD"
+// @Param to query string false "Конец периода в формате YYYY-MM-DD"
+// @Success 200 {array} calendar.Occurrence "Список занятий"
+// @Failure 400 {object} map[string]string "error: Invalid date range"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /audiences/{uuid}/occurrences [get]
+func (a *App) GetAudienceOccurrencesHandler(c echo.Context) error {
+	return a.occurrences(c, kindAudience)
+}
+
+// occurrences раскрывает расписание вида kind в занятия на даты из параметров запроса
+func (a *App) occurrences(c echo.Context, kind string) error {
+	if !a.Calendar.Configured() {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Semester calendar is not configured"})
+	}
+
+	from, to, err := a.dateRange(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	id := c.Param("uuid")
+	if kind == kindTimetable {
+		id = c.Param("id")
+	}
+
+	scheduleItems, err := a.loadSchedule(kind, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Timetable not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+	}
+
+	occurrences, err := a.Calendar.Expand(scheduleItems, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, occurrences)
+}
+
+// dateRange читает период из параметров from и to, по умолчанию - ближайшие 7 дней
+func (a *App) dateRange(c echo.Context) (time.Time, time.Time, error) {
+	now := time.Now().In(a.Calendar.Location())
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 0, 6)
+
+	var err error
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		if from, err = a.Calendar.ParseDate(fromStr); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = from.AddDate(0, 0, 6)
+	}
+	if toStr := c.QueryParam("to"); toStr != "" {
+		if to, err = a.Calendar.ParseDate(toStr); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if to.Before(from) || to.Sub(from).Hours()/24 > maxRangeDays {
+		return time.Time{}, time.Time{}, errors.New("invalid date range")
+	}
+	return from, to, nil
+}
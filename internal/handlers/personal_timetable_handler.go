@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTimetableSources = 20
+	maxTimetableTitle   = 200 // Максимальная длина названия в символах
+	editTokenHeader     = "X-Edit-Token"
+)
+
+// PersonalTimetableRequest - тело запроса на создание или изменение личного расписания
+type PersonalTimetableRequest struct {
+	Title   string                   `json:"title"`
+	Sources []models.TimetableSource `json:"sources"`
+}
+
+// PersonalTimetableCreated - созданное личное расписание с токеном для изменения
+type PersonalTimetableCreated struct {
+	models.PersonalTimetable
+	EditToken string `json:"editToken"`
+}
+
+// CreatePersonalTimetableHandler создает личное расписание
+// @Summary Создание личного расписания
+// @Description Сохраняет набор групп с фильтрами по дисциплинам и типам занятий под публичным ID.
+// @Description Токен editToken из ответа нужен для изменения и удаления расписания, он возвращается только один раз.
+// @Description Название - не длиннее 200 символов, групп - не больше 20
+// @Tags PersonalTimetables
+// @Accept json
+// @Produce json
+// @Param timetable body PersonalTimetableRequest true "Личное расписание"
+// @Success 201 {object} PersonalTimetableCreated "Созданное расписание"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 500 {object} map[string]string "error: Failed to save timetable"
+// @Router /timetables [post]
+func (a *App) CreatePersonalTimetableHandler(c echo.Context) error {
+	var req PersonalTimetableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if err := a.validateTimetable(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	publicID, err := randomToken(8)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save timetable"})
+	}
+	editToken, err := randomToken(16)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save timetable"})
+	}
+
+	pt := models.PersonalTimetable{
+		PublicID:  publicID,
+		EditToken: editToken,
+		Title:     req.Title,
+		Sources:   req.Sources,
+	}
+	if err := a.DB.Create(&pt).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save timetable"})
+	}
+
+	return c.JSON(http.StatusCreated, PersonalTimetableCreated{PersonalTimetable: pt, EditToken: editToken})
+}
+
+// GetPersonalTimetableHandler отправляет JSON с описанием личного расписания
+// @Summary Получение личного расписания
+// @Tags PersonalTimetables
+// @Produce json
+// @Param id path string true "ID личного расписания"
+// @Success 200 {object} models.PersonalTimetable "Личное расписание"
+// @Failure 404 {object} map[string]string "error: Timetable not found"
+// @Router /timetables/{id} [get]
+func (a *App) GetPersonalTimetableHandler(c echo.Context) error {
+	pt, err := repository.PersonalTimetable(a.DB, c.Param("id"))
+	if err != nil {
+		return timetableError(c, err)
+	}
+	return c.JSON(http.StatusOK, pt)
+}
+
+// UpdatePersonalTimetableHandler изменяет личное расписание
+// @Summary Изменение личного расписания
+// @Tags PersonalTimetables
+// @Accept json
+// @Produce json
+// @Param id path string true "ID личного расписания"
+// @Param X-Edit-Token header string true "Токен, полученный при создании"
+// @Param timetable body PersonalTimetableRequest true "Личное расписание"
+// @Success 200 {object} models.PersonalTimetable "Измененное расписание"
+// @Failure 400 {object} map[string]string "error: Invalid request"
+// @Failure 403 {object} map[string]string "error: Invalid edit token"
+// @Failure 404 {object} map[string]string "error: Timetable not found"
+// @Failure 500 {object} map[string]string "error: Failed to save timetable"
+// @Router /timetables/{id} [put]
+func (a *App) UpdatePersonalTimetableHandler(c echo.Context) error {
+	pt, err := a.editableTimetable(c)
+	if err != nil {
+		return timetableError(c, err)
+	}
+
+	var req PersonalTimetableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if err := a.validateTimetable(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	pt.Title = req.Title
+	pt.Sources = req.Sources
+	if err := a.DB.Save(pt).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save timetable"})
+	}
+	a.timetableChanged(pt.PublicID)
+
+	return c.JSON(http.StatusOK, pt)
+}
+
+// DeletePersonalTimetableHandler удаляет личное расписание
+// @Summary Удаление личного расписания
+// @Tags PersonalTimetables
+// @Param id path string true "ID личного расписания"
+// @Param X-Edit-Token header string true "Токен, полученный при создании"
+// @Success 204 "Расписание удалено"
+// @Failure 403 {object} map[string]string "error: Invalid edit token"
+// @Failure 404 {object} map[string]string "error: Timetable not found"
+// @Failure 500 {object} map[string]string "error: Failed to delete timetable"
+// @Router /timetables/{id} [delete]
+func (a *App) DeletePersonalTimetableHandler(c echo.Context) error {
+	pt, err := a.editableTimetable(c)
+	if err != nil {
+		return timetableError(c, err)
+	}
+
+	if err := a.DB.Delete(pt).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete timetable"})
+	}
+	a.timetableChanged(pt.PublicID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetPersonalTimetableScheduleHandler отправляет занятия личного расписания в формате, выбранном по Accept или параметру format
+// @Summary Занятия личного расписания
+// @Description Собирает занятия всех групп личного расписания с учетом фильтров. Поддерживает те же форматы и параметры, что и расписание группы:
+// @Description json, html, csv, ics, xlsx и pdf
+// @Tags PersonalTimetables
+// @Produce json,html,text/csv,text/calendar,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param id path string true "ID личного расписания"
+// @Param format query string false "Формат: json, html, csv, ics, xlsx или pdf"
+// @Param Accept header string false "Предпочитаемые типы, например text/calendar или text/html"
+// @Param date query string false "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML"
+// @Param refresh query int false "Интервал автообновления страницы HTML в секундах"
+// @Param layout query string false "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid"
+// @Param If-None-Match header string false "ETag ранее полученного ответа"
+// @Success 200 {array} models.ScheduleItem "Расписание в выбранном формате"
+// @Header 200 {string} ETag "Версия данных и формат"
+// @Success 304 "Данные не изменились"
+// @Failure 400 {object} map[string]string "error: unknown schedule format"
+// @Failure 404 {object} map[string]string "error: Timetable not found"
+// @Failure 406 {object} map[string]string "error: none of the accepted media types is supported"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /timetables/{id}/schedule [get]
+func (a *App) GetPersonalTimetableScheduleHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindTimetable, "")
+}
+
+// GetPersonalTimetableOccurrencesHandler отправляет JSON с занятиями личного расписания на конкретные даты
+// @Summary Занятия личного расписания по датам
+// @Description Раскрывает личное расписание в список занятий с датой и временем с учетом четности недель, границ семестра и праздников.
+// @Description Без параметров from и to возвращаются занятия на ближайшие 7 дней.
+// @Tags PersonalTimetables
+// @Produce json
+// @Param id path string true "ID личного расписания"
+// @Param from query string false "Начало периода в формате YYYY-MM-DD"
+// @Param to query string false "Конец периода в формате YYYY-MM-DD"
+// @Success 200 {array} calendar.Occurrence "Список занятий"
+// @Failure 400 {object} map[string]string "error: Invalid date range"
+// @Failure 404 {object} map[string]string "error: Timetable not found"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /timetables/{id}/occurrences [get]
+func (a *App) GetPersonalTimetableOccurrencesHandler(c echo.Context) error {
+	return a.occurrences(c, kindTimetable)
+}
+
+// timetableChanged увеличивает глобальную версию данных: по ней проверяется актуальность ответов личных расписаний
+func (a *App) timetableChanged(publicID string) {
+	if err := repository.BumpDataVersion(a.DB); err != nil {
+		log.Printf("Failed to bump data version for timetable %s: %v", publicID, err)
+	}
+}
+
+// timetableSchedule собирает занятия личного расписания из расписаний групп
+func (a *App) timetableSchedule(publicID string) ([]models.ScheduleItem, error) {
+	pt, err := repository.PersonalTimetable(a.DB, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool)
+	var result []models.ScheduleItem
+	for _, source := range pt.Sources {
+		scheduleItems, err := a.loadSchedule(kindGroup, source.Group)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range scheduleItems {
+			if !seen[item.ID] && sourceMatches(source, item) {
+				seen[item.ID] = true
+				result = append(result, item)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Day != result[j].Day {
+			return result[i].Day < result[j].Day
+		}
+		return result[i].Time < result[j].Time
+	})
+	if result == nil {
+		result = []models.ScheduleItem{}
+	}
+	return result, nil
+}
+
+// sourceMatches сообщает, проходит ли занятие фильтры источника личного расписания
+func sourceMatches(source models.TimetableSource, item models.ScheduleItem) bool {
+	disciplineMatches := func(names []string) bool {
+		for _, d := range item.Disciplines {
+			if containsFold(names, d.FullName) || containsFold(names, d.Abbr) {
+				return true
+			}
+		}
+		return false
+	}
+	actTypeMatches := func(actTypes []string) bool {
+		for _, d := range item.Disciplines {
+			if containsFold(actTypes, d.ActType) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(source.IncludeDisciplines) > 0 && !disciplineMatches(source.IncludeDisciplines) {
+		return false
+	}
+	if len(source.IncludeActTypes) > 0 && !actTypeMatches(source.IncludeActTypes) {
+		return false
+	}
+	return !disciplineMatches(source.ExcludeDisciplines) && !actTypeMatches(source.ExcludeActTypes)
+}
+
+func containsFold(values []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) validateTimetable(req PersonalTimetableRequest) error {
+	if utf8.RuneCountInString(req.Title) > maxTimetableTitle {
+		return fmt.Errorf("title must be at most %d characters", maxTimetableTitle)
+	}
+	return a.validateTimetableSources(req.Sources)
+}
+
+func (a *App) validateTimetableSources(sources []models.TimetableSource) error {
+	if len(sources) == 0 {
+		return errors.New("at least one source is required")
+	}
+	if len(sources) > maxTimetableSources {
+		return fmt.Errorf("at most %d sources are allowed", maxTimetableSources)
+	}
+
+	for _, source := range sources {
+		if source.Group == "" {
+			return errors.New("source group is required")
+		}
+		var count int64
+		if err := a.DB.Model(&models.Group{}).Where("uuid = ?", source.Group).Count(&count).Error; err != nil {
+			return errors.New("failed to check groups")
+		}
+		if count == 0 {
+			return fmt.Errorf("group %s not found", source.Group)
+		}
+	}
+	return nil
+}
+
+var errInvalidEditToken = errors.New("invalid edit token")
+
+// editableTimetable загружает личное расписание и проверяет токен изменения из заголовка
+func (a *App) editableTimetable(c echo.Context) (*models.PersonalTimetable, error) {
+	pt, err := repository.PersonalTimetable(a.DB, c.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	token := c.Request().Header.Get(editTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(pt.EditToken)) != 1 {
+		return nil, errInvalidEditToken
+	}
+	return &pt, nil
+}
+
+func timetableError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Timetable not found"})
+	case errors.Is(err, errInvalidEditToken):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Invalid edit token"})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
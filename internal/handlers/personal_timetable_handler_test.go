@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceMatches(t *testing.T) {
+	lecture := models.ScheduleItem{Disciplines: []models.Discipline{{FullName: "Физика", Abbr: "Физ", ActType: "lecture"}}}
+	lab := models.ScheduleItem{Disciplines: []models.Discipline{{FullName: "Физика", Abbr: "Физ", ActType: "lab"}}}
+	elective := models.ScheduleItem{Disciplines: []models.Discipline{{FullName: "Японский язык", ActType: "seminar"}}}
+
+	all := models.TimetableSource{Group: "g"}
+	assert.True(t, sourceMatches(all, lecture))
+	assert.True(t, sourceMatches(all, elective))
+
+	lecturesOnly := models.TimetableSource{Group: "g", IncludeActTypes: []string{"Lecture"}}
+	assert.True(t, sourceMatches(lecturesOnly, lecture))
+	assert.False(t, sourceMatches(lecturesOnly, lab))
+
+	physicsLabs := models.TimetableSource{Group: "g", IncludeDisciplines: []string{"физ"}, ExcludeActTypes: []string{"lecture"}}
+	assert.False(t, sourceMatches(physicsLabs, lecture))
+	assert.True(t, sourceMatches(physicsLabs, lab))
+	assert.False(t, sourceMatches(physicsLabs, elective))
+
+	noElective := models.TimetableSource{Group: "g", ExcludeDisciplines: []string{"Японский язык"}}
+	assert.False(t, sourceMatches(noElective, elective))
+	assert.True(t, sourceMatches(noElective, lab))
+}
+
+func TestValidateTimetableTitle(t *testing.T) {
+	a := &App{}
+	err := a.validateTimetable(PersonalTimetableRequest{Title: strings.Repeat("я", maxTimetableTitle+1)})
+	assert.EqualError(t, err, "title must be at most 200 characters")
+
+	// Пустой список источников отклоняется без обращения к базе
+	err = a.validateTimetable(PersonalTimetableRequest{Title: strings.Repeat("я", maxTimetableTitle)})
+	assert.EqualError(t, err, "at least one source is required")
+}
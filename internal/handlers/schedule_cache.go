@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/kosttiik/semesterly_backend/internal/cache"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Виды расписаний, которые можно получить по UUID
+const (
+	kindGroup    = "group"
+	kindTeacher  = "teacher"
+	kindAudience = "audience"
+	// Личное расписание собирается из расписаний групп и само не кешируется
+	kindTimetable = "timetable"
+)
+
+var scheduleLoaders = map[string]func(db *gorm.DB, uuid string) ([]models.ScheduleItem, error){
+	kindGroup:    repository.GroupSchedule,
+	kindTeacher:  repository.TeacherSchedule,
+	kindAudience: repository.AudienceSchedule,
+}
+
+// loadSchedule возвращает расписание группы, преподавателя или аудитории, используя кеш
+func (a *App) loadSchedule(kind, uuid string) ([]models.ScheduleItem, error) {
+	return a.loadScheduleContext(context.Background(), kind, uuid)
+}
+
+// loadScheduleContext - loadSchedule, запросы которого к базе прерываются вместе с ctx
+func (a *App) loadScheduleContext(ctx context.Context, kind, uuid string) ([]models.ScheduleItem, error) {
+	if kind == kindTimetable {
+		return a.timetableSchedule(uuid)
+	}
+
+	load, ok := scheduleLoaders[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown schedule kind %q", kind)
+	}
+
+	if a.Cache == nil {
+		return load(a.DB.WithContext(ctx), uuid)
+	}
+	return a.Cache.GetOrLoad(cacheKey(kind, uuid), func() ([]models.ScheduleItem, error) {
+		return load(a.DB.WithContext(ctx), uuid)
+	})
+}
+
+// GroupSchedule возвращает расписание группы так же, как его отдают REST-эндпоинты
+func (a *App) GroupSchedule(ctx context.Context, uuid string) ([]models.ScheduleItem, error) {
+	return a.loadScheduleContext(ctx, kindGroup, uuid)
+}
+
+// TeacherSchedule возвращает расписание преподавателя так же, как его отдают REST-эндпоинты
+func (a *App) TeacherSchedule(ctx context.Context, uuid string) ([]models.ScheduleItem, error) {
+	return a.loadScheduleContext(ctx, kindTeacher, uuid)
+}
+
+// AudienceSchedule возвращает расписание аудитории так же, как его отдают REST-эндпоинты
+func (a *App) AudienceSchedule(ctx context.Context, uuid string) ([]models.ScheduleItem, error) {
+	return a.loadScheduleContext(ctx, kindAudience, uuid)
+}
+
+func cacheKey(kind, uuid string) string {
+	return kind + ":" + uuid
+}
+
+// dataChanged отмечает изменение расписания группы uuid: увеличивает версии данных для HTTP-кеширования
+// и сбрасывает из кеша только затронутые группы, преподавателей и аудитории старых и новых занятий
+func (a *App) dataChanged(uuid string, itemSets ...[]models.ScheduleItem) {
+	seen := map[string]bool{uuid: true}
+	groupUUIDs := []string{uuid}
+	keys := []string{cacheKey(kindGroup, uuid)}
+
+	for _, scheduleItems := range itemSets {
+		for _, item := range scheduleItems {
+			for _, group := range item.Groups {
+				if !seen[group.UUID] {
+					seen[group.UUID] = true
+					groupUUIDs = append(groupUUIDs, group.UUID)
+					keys = append(keys, cacheKey(kindGroup, group.UUID))
+				}
+			}
+			for _, teacher := range item.Teachers {
+				keys = append(keys, cacheKey(kindTeacher, teacher.UUID))
+			}
+			for _, audience := range item.Audiences {
+				keys = append(keys, cacheKey(kindAudience, audience.UUID))
+			}
+		}
+	}
+
+	if a.Cache != nil {
+		a.Cache.Delete(keys...)
+	}
+	if err := repository.BumpDataVersion(a.DB, groupUUIDs...); err != nil {
+		log.Printf("Failed to bump data version for group %s: %v", uuid, err)
+	}
+}
+
+// GetCacheStatsHandler отправляет JSON со статистикой кеша расписаний
+// @Summary Статистика кеша
+// @Description Возвращает размер кеша расписаний групп, преподавателей и аудиторий, количество попаданий и промахов
+// @Tags Cache
+// @Produce json
+// @Success 200 {object} cache.Stats "Статистика кеша"
+// @Router /cache/stats [get]
+func (a *App) GetCacheStatsHandler(c echo.Context) error {
+	if a.Cache == nil {
+		return c.JSON(http.StatusOK, cache.Stats{})
+	}
+	return c.JSON(http.StatusOK, a.Cache.Stats())
+}
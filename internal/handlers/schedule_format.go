@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/export"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/labstack/echo/v4"
+)
+
+var ErrNotAcceptable = errors.New("none of the accepted media types is supported")
+
+// scheduleFormat - сериализатор расписания в одном формате. Формат, добавленный в scheduleFormats,
+// сразу отдается эндпоинтами .../{uuid}/schedule по Accept или параметру format и доступен в архивах выгрузки
+type scheduleFormat struct {
+	name        string
+	ext         string
+	contentType string   // Content-Type ответа
+	mediaTypes  []string // Типы из Accept, которым соответствует формат
+	attachment  bool     // Отдавать файлом для скачивания, а не для показа в браузере
+	volatile    bool     // Содержимое зависит от текущей даты и не кэшируется по версии данных
+	render      func(a *App, w io.Writer, t exportTarget, opts renderOptions) error
+}
+
+// renderOptions - параметры отрисовки расписания, date, refresh и links относятся к странице HTML
+type renderOptions struct {
+	date    time.Time // Неделя этой даты подсвечивается
+	refresh int       // Интервал автообновления страницы в секундах
+	links   bool      // Ссылки на PDF и календарь рядом со страницей
+	grid    bool      // JSON в виде сетки timetable.Grid вместо списка занятий
+}
+
+// scheduleFormats - зарегистрированные форматы в порядке предпочтения при одинаковом q в Accept,
+// первый отдается по умолчанию
+var scheduleFormats = []scheduleFormat{
+	{
+		name: "json", ext: "json", contentType: echo.MIMEApplicationJSONCharsetUTF8,
+		mediaTypes: []string{echo.MIMEApplicationJSON},
+		render: func(a *App, w io.Writer, t exportTarget, opts renderOptions) error {
+			scheduleItems, err := a.loadSchedule(t.kind, t.uuid)
+			if err != nil {
+				return err
+			}
+			if opts.grid {
+				return json.NewEncoder(w).Encode(timetable.Build(scheduleItems, a.bells()))
+			}
+			return json.NewEncoder(w).Encode(scheduleItems)
+		},
+	},
+	{
+		name: "html", ext: "html", contentType: echo.MIMETextHTMLCharsetUTF8,
+		mediaTypes: []string{echo.MIMETextHTML, "application/xhtml+xml"},
+		volatile:   true,
+		render: func(a *App, w io.Writer, t exportTarget, opts renderOptions) error {
+			return a.renderSchedulePage(w, t.kind, t.uuid, opts)
+		},
+	},
+	{
+		name: "csv", ext: "csv", contentType: "text/csv; charset=utf-8",
+		mediaTypes: []string{"text/csv"},
+		attachment: true,
+		render: func(a *App, w io.Writer, t exportTarget, _ renderOptions) error {
+			scheduleItems, err := a.loadSchedule(t.kind, t.uuid)
+			if err != nil {
+				return err
+			}
+			exams, err := a.loadExams(t.kind, t.uuid)
+			if err != nil {
+				return err
+			}
+			// BOM, чтобы Excel открывал файл в UTF-8
+			writer, err := export.NewCSVWriter(w, export.CSVOptions{Encoding: export.EncodingUTF8BOM})
+			if err != nil {
+				return err
+			}
+			if err := writer.WriteHeader(); err != nil {
+				return err
+			}
+			for _, item := range scheduleItems {
+				if err := writer.WriteItem(item); err != nil {
+					return err
+				}
+			}
+			for _, exam := range exams {
+				if err := writer.WriteExam(exam); err != nil {
+					return err
+				}
+			}
+			return writer.Close()
+		},
+	},
+	{
+		name: "ics", ext: "ics", contentType: calendarContentType,
+		mediaTypes: []string{"text/calendar"},
+		render: func(a *App, w io.Writer, t exportTarget, _ renderOptions) error {
+			body, err := a.scheduleCalendar(t.kind, t.uuid)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(body)
+			return err
+		},
+	},
+	{
+		name: "xlsx", ext: "xlsx", contentType: xlsxContentType,
+		mediaTypes: []string{xlsxContentType},
+		attachment: true,
+		render: func(a *App, w io.Writer, t exportTarget, _ renderOptions) error {
+			sheet, err := a.scheduleSheet(t.kind, t.uuid, scheduleRowLabels[t.kind])
+			if err != nil {
+				return err
+			}
+			return export.WriteXLSX(w, []export.Sheet{sheet}, a.bells())
+		},
+	},
+	{
+		name: "pdf", ext: "pdf", contentType: pdfContentType,
+		mediaTypes: []string{pdfContentType},
+		attachment: true,
+		render: func(a *App, w io.Writer, t exportTarget, _ renderOptions) error {
+			sheet, err := a.scheduleSheet(t.kind, t.uuid, scheduleRowLabels[t.kind])
+			if err != nil {
+				return err
+			}
+			return export.WritePDF(w, []export.Sheet{sheet}, a.bells())
+		},
+	},
+}
+
+// scheduleRowLabels - подписи расписаний в печатных форматах
+var scheduleRowLabels = map[string]string{
+	kindGroup:     "Группа",
+	kindTeacher:   "Преподаватель",
+	kindAudience:  "Аудитория",
+	kindTimetable: "Расписание",
+}
+
+// findScheduleFormat ищет формат по названию без учета регистра
+func findScheduleFormat(name string) (scheduleFormat, bool) {
+	for _, format := range scheduleFormats {
+		if strings.EqualFold(format.name, name) {
+			return format, true
+		}
+	}
+	return scheduleFormat{}, false
+}
+
+// scheduleFormatNames перечисляет зарегистрированные форматы для сообщений об ошибках
+func scheduleFormatNames() string {
+	names := make([]string, len(scheduleFormats))
+	for i, format := range scheduleFormats {
+		names[i] = format.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// negotiateScheduleFormat выбирает формат по параметру format, а без него - по заголовку Accept.
+// Каждому формату достается q самого точного подходящего диапазона (text/csv, text/*, */*),
+// из форматов с наибольшим q выбирается первый в scheduleFormats. Без Accept отдается формат по умолчанию
+func negotiateScheduleFormat(name, accept string) (scheduleFormat, error) {
+	if name != "" {
+		format, ok := findScheduleFormat(name)
+		if !ok {
+			return scheduleFormat{}, fmt.Errorf("%w %q, expected one of %s", ErrUnknownFormat, name, scheduleFormatNames())
+		}
+		return format, nil
+	}
+	if strings.TrimSpace(accept) == "" {
+		return scheduleFormats[0], nil
+	}
+
+	type mediaRange struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType, q})
+	}
+
+	best, bestQ := -1, 0.0
+	for i, format := range scheduleFormats {
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			for _, mediaType := range format.mediaTypes {
+				s := mediaRangeMatch(r.mediaType, mediaType)
+				if s > specificity {
+					q, specificity = r.q, s
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return scheduleFormat{}, ErrNotAcceptable
+	}
+	return scheduleFormats[best], nil
+}
+
+// mediaRangeMatch сообщает, насколько точно диапазон из Accept подходит типу: 2 - совпадение типа,
+// 1 - type/*, 0 - */*, -1 - не подходит
+func mediaRangeMatch(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateScheduleFormat(t *testing.T) {
+	cases := []struct {
+		format, accept, want string
+	}{
+		{"", "", "json"},
+		{"", "*/*", "json"},
+		{"", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "html"},
+		{"", "text/calendar", "ics"},
+		{"", "text/*", "html"},
+		{"", "text/*;q=0.5, text/csv", "csv"},
+		{"", "application/pdf;q=0.4, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet;q=0.6", "xlsx"},
+		// Точный тип с q=0 исключает формат, даже если подходит */*
+		{"", "application/json;q=0, */*", "html"},
+		{"CSV", "application/json", "csv"},
+	}
+	for _, tc := range cases {
+		format, err := negotiateScheduleFormat(tc.format, tc.accept)
+		require.NoError(t, err, tc.accept)
+		assert.Equal(t, tc.want, format.name, "format=%q accept=%q", tc.format, tc.accept)
+	}
+
+	_, err := negotiateScheduleFormat("", "image/png, application/xml")
+	assert.ErrorIs(t, err, ErrNotAcceptable)
+	_, err = negotiateScheduleFormat("docx", "")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
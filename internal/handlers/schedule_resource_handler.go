@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetGroupScheduleResourceHandler отправляет расписание группы в формате, выбранном по Accept или параметру format
+// @Summary Расписание группы в выбранном формате
+// @Description Один адрес для всех форматов расписания: json, html, csv, ics, xlsx и pdf.
+// @Description Формат задается параметром format, а без него выбирается по заголовку Accept с учетом q; без Accept отдается JSON.
+// @Description Параметры date и refresh относятся к странице HTML, layout - к JSON
+// @Tags Schedules
+// @Produce json,html,text/csv,text/calendar,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param uuid path string true "UUID группы"
+// @Param format query string false "Формат: json, html, csv, ics, xlsx или pdf"
+// @Param Accept header string false "Предпочитаемые типы, например text/calendar или text/html"
+// @Param date query string false "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML"
+// @Param refresh query int false "Интервал автообновления страницы HTML в секундах"
+// @Param layout query string false "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid"
+// @Param If-None-Match header string false "ETag ранее полученного ответа"
+// @Success 200 {array} models.ScheduleItem "Расписание в выбранном формате"
+// @Header 200 {string} ETag "Версия данных и формат"
+// @Success 304 "Данные не изменились"
+// @Failure 400 {object} map[string]string "error: unknown schedule format"
+// @Failure 404 {object} map[string]string "error: Group not found"
+// @Failure 406 {object} map[string]string "error: none of the accepted media types is supported"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /groups/{uuid}/schedule [get]
+func (a *App) GetGroupScheduleResourceHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindGroup, "")
+}
+
+// GetTeacherScheduleResourceHandler отправляет расписание преподавателя в формате, выбранном по Accept или параметру format
+// @Summary Расписание преподавателя в выбранном формате
+// @Tags Schedules
+// @Produce json,html,text/csv,text/calendar,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param uuid path string true "UUID преподавателя"
+// @Param format query string false "Формат: json, html, csv, ics, xlsx или pdf"
+// @Param Accept header string false "Предпочитаемые типы, например text/calendar или text/html"
+// @Param date query string false "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML"
+// @Param refresh query int false "Интервал автообновления страницы HTML в секундах"
+// @Param layout query string false "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid"
+// @Param If-None-Match header string false "ETag ранее полученного ответа"
+// @Success 200 {array} models.ScheduleItem "Расписание в выбранном формате"
+// @Header 200 {string} ETag "Версия данных и формат"
+// @Success 304 "Данные не изменились"
+// @Failure 400 {object} map[string]string "error: unknown schedule format"
+// @Failure 404 {object} map[string]string "error: Teacher not found"
+// @Failure 406 {object} map[string]string "error: none of the accepted media types is supported"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /teachers/{uuid}/schedule [get]
+func (a *App) GetTeacherScheduleResourceHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindTeacher, "")
+}
+
+// GetAudienceScheduleResourceHandler отправляет расписание аудитории в формате, выбранном по Accept или параметру format
+// @Summary Расписание аудитории в выбранном формате
+// @Tags Schedules
+// @Produce json,html,text/csv,text/calendar,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param uuid path string true "UUID аудитории"
+// @Param format query string false "Формат: json, html, csv, ics, xlsx или pdf"
+// @Param Accept header string false "Предпочитаемые типы, например text/calendar или text/html"
+// @Param date query string false "Дата в формате YYYY-MM-DD, неделя которой подсвечивается на странице HTML"
+// @Param refresh query int false "Интервал автообновления страницы HTML в секундах"
+// @Param layout query string false "Вид JSON: flat (по умолчанию) или grid - объект timetable.Grid"
+// @Param If-None-Match header string false "ETag ранее полученного ответа"
+// @Success 200 {array} models.ScheduleItem "Расписание в выбранном формате"
+// @Header 200 {string} ETag "Версия данных и формат"
+// @Success 304 "Данные не изменились"
+// @Failure 400 {object} map[string]string "error: unknown schedule format"
+// @Failure 404 {object} map[string]string "error: Audience not found"
+// @Failure 406 {object} map[string]string "error: none of the accepted media types is supported"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Failure 503 {object} map[string]string "error: Semester calendar is not configured"
+// @Router /audiences/{uuid}/schedule [get]
+func (a *App) GetAudienceScheduleResourceHandler(c echo.Context) error {
+	return a.scheduleResource(c, kindAudience, "")
+}
+
+// notFoundMessages - ответы 404 для расписаний каждого вида
+var notFoundMessages = map[string]string{
+	kindGroup:     "Group not found",
+	kindTeacher:   "Teacher not found",
+	kindAudience:  "Audience not found",
+	kindTimetable: "Timetable not found",
+}
+
+// scheduleResource отправляет расписание вида kind сериализатором fixed, а если он не задан - выбранным по запросу.
+// Эндпоинты с расширением в пути (schedule.ics, schedule.pdf, ...) передают формат явно
+func (a *App) scheduleResource(c echo.Context, kind, fixed string) error {
+	id := c.Param("uuid")
+	if kind == kindTimetable {
+		id = c.Param("id")
+	}
+
+	format, ok := findScheduleFormat(fixed)
+	if !ok {
+		// Ответ зависит от Accept, кэши должны хранить представления раздельно
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+		var err error
+		format, err = negotiateScheduleFormat(c.QueryParam("format"), c.Request().Header.Get(echo.HeaderAccept))
+		switch {
+		case errors.Is(err, ErrNotAcceptable):
+			return c.JSON(http.StatusNotAcceptable, map[string]string{"error": err.Error() + ", expected one of " + scheduleFormatNames()})
+		case err != nil:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	opts, err := a.pageOptions(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	opts.links = true
+
+	variant := format.name
+	switch c.QueryParam("layout") {
+	case "", "flat":
+	case "grid":
+		opts.grid = true
+		variant += "-grid"
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "layout must be one of flat, grid"})
+	}
+
+	// Версия данных ведется для групп, остальные расписания, включая личные, меняются вместе с глобальной версией
+	scope := models.GlobalDataScope
+	if kind == kindGroup {
+		scope = id
+	}
+	if !format.volatile && a.notModifiedVariant(c, scope, variant) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	var buf bytes.Buffer
+	err = format.render(a, &buf, exportTarget{kind: kind, uuid: id}, opts)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": notFoundMessages[kind]})
+	case errors.Is(err, calendar.ErrNotConfigured):
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Semester calendar is not configured"})
+	case err != nil:
+		log.Printf("Failed to render %s schedule of %s %s: %v", format.name, kind, id, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
+	}
+
+	disposition := "inline"
+	if format.attachment {
+		disposition = "attachment"
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, disposition+`; filename="schedule.`+format.ext+`"`)
+	return c.Blob(http.StatusOK, format.contentType, buf.Bytes())
+}
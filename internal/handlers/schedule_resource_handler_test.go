@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/cache"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResourceTestApp(t *testing.T) *App {
+	db := newTestDB(t, testConnector{version: 3})
+
+	schedules := cache.New[[]models.ScheduleItem](10, time.Hour)
+	schedules.Set(cacheKey(kindGroup, "g1"), []models.ScheduleItem{{
+		ID: 1, Day: 1, Time: 1, Week: models.WeekAll,
+		Groups:      []models.Group{{UUID: "g1", Name: "ИУ7-11Б"}},
+		Disciplines: []models.Discipline{{FullName: "Физика", ActType: "lecture"}},
+	}})
+	return &App{DB: db, Cache: schedules}
+}
+
+func TestScheduleResource(t *testing.T) {
+	a := newResourceTestApp(t)
+	get := func(handler echo.HandlerFunc, header http.Header, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups/g1/schedule"+query, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("uuid")
+		c.SetParamValues("g1")
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	rec := get(a.GetGroupScheduleResourceHandler, http.Header{"Accept": {"image/png"}}, "")
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+
+	rec = get(a.GetGroupScheduleResourceHandler, http.Header{"Accept": {"application/json"}}, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+	assert.Equal(t, `W/"g1-3-json"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), "Физика")
+	jsonETag := rec.Header().Get("ETag")
+
+	rec = get(a.GetGroupScheduleResourceHandler, http.Header{"Accept": {"application/json"}, "If-None-Match": {jsonETag}}, "")
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	// ETag JSON не подходит для другого формата того же ресурса
+	rec = get(a.GetGroupScheduleResourceHandler, http.Header{"Accept": {"text/csv"}, "If-None-Match": {jsonETag}}, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `W/"g1-3-csv"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), "Физика")
+
+	rec = get(a.GetGroupScheduleResourceHandler, http.Header{"If-None-Match": {`W/"g1-3-csv"`}}, "?format=csv")
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec = get(a.GetGroupScheduleResourceHandler, nil, "?layout=table")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Эндпоинт с расширением отдает свой формат независимо от Accept
+	rec = get(a.GetGroupPDFHandler, http.Header{"Accept": {"application/json"}}, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, pdfContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `W/"g1-3-pdf"`, rec.Header().Get("ETag"))
+	assert.Empty(t, rec.Header().Get(echo.HeaderVary))
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/repository"
+	"github.com/kosttiik/semesterly_backend/internal/utils"
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrSyncInProgress = errors.New("sync is already in progress")
+	ErrFetchStructure = errors.New("failed to fetch structure")
+	ErrNoGroups       = errors.New("no groups found")
+)
+
+// SyncResult - итог синхронизации с lks
+type SyncResult struct {
+	Completed      int
+	Total          int
+	Errors         []string
+	SlotMismatches []repository.SlotMismatch // Занятия, время которых расходится с расписанием звонков
+}
+
+// Sync загружает структуру университета, расписания и экзамены всех групп из lks.
+// Прогресс рассылается через Hub. Одновременно может выполняться только одна синхронизация
+func (a *App) Sync() (SyncResult, error) {
+	if !a.syncing.CompareAndSwap(false, true) {
+		return SyncResult{}, ErrSyncInProgress
+	}
+	defer a.syncing.Store(false)
+
+	return a.sync()
+}
+
+// StartSync запускает синхронизацию в фоне и сразу возвращает управление
+func (a *App) StartSync() error {
+	if !a.syncing.CompareAndSwap(false, true) {
+		return ErrSyncInProgress
+	}
+
+	go func() {
+		defer a.syncing.Store(false)
+
+		result, err := a.sync()
+		if err != nil {
+			log.Printf("Sync failed: %v", err)
+			return
+		}
+		log.Printf("Sync finished: %d of %d groups, %d errors", result.Completed, result.Total, len(result.Errors))
+	}()
+	return nil
+}
+
+// Syncing сообщает, идет ли сейчас синхронизация или загрузка расписания из файла
+func (a *App) Syncing() bool {
+	return a.syncing.Load()
+}
+
+func (a *App) sync() (SyncResult, error) {
+	structureURL := "https://lks.bmstu.ru/lks-back/api/v1/structure"
+	var structure models.Structure
+
+	if err := utils.FetchJSON(structureURL, &structure); err != nil {
+		log.Printf("Failed to fetch structure: %v", err)
+		return SyncResult{}, fmt.Errorf("%w: %v", ErrFetchStructure, err)
+	}
+
+	if err := repository.SaveStructure(a.DB, structure); err != nil {
+		log.Printf("Failed to save structure: %v", err)
+	}
+
+	groupUUIDs := utils.ExtractGroupUUIDs(structure.Data.Children)
+	log.Printf("Fetched %d group UUIDs", len(groupUUIDs))
+
+	totalItems := len(groupUUIDs)
+	if totalItems == 0 {
+		return SyncResult{}, ErrNoGroups
+	}
+
+	completed := 0
+	startTime := time.Now()
+
+	var mu sync.Mutex
+
+	// Отправляем начальное состояние прогресса
+	mu.Lock()
+	a.Hub.BroadcastProgress(ProgressUpdate{
+		Type:           SyncProgressType,
+		CurrentItem:    0,
+		TotalItems:     totalItems,
+		CompletedItems: 0,
+		Percentage:     0,
+		ETA:            "Calculating...",
+	})
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	errors := make([]string, 0)
+	sem := make(chan struct{}, 10)                                   // Ограничение в 10 горутин
+	limiter := rate.NewLimiter(rate.Every(100*time.Millisecond), 10) // 10 запросов в 100 миллисекунд
+
+	for _, uuid := range groupUUIDs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(uuid string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Ожидание разрешения от rate limiter
+			if err := limiter.Wait(context.Background()); err != nil {
+				log.Printf("Rate limiter error for group %s: %v", uuid, err)
+				utils.AppendError(&mu, &errors, fmt.Sprintf("Rate limiter error for group %s: %v", uuid, err))
+				return
+			}
+
+			if err := a.processGroupData(uuid, &mu, &errors); err != nil {
+				log.Printf("Failed to process data for group %s: %v", uuid, err)
+				utils.AppendError(&mu, &errors, fmt.Sprintf("Group %s: %v", uuid, err))
+			}
+
+			mu.Lock()
+			completed++
+			elapsed := time.Since(startTime)
+			itemsPerSecond := float64(completed) / elapsed.Seconds()
+			remainingItems := totalItems - completed
+			eta := time.Duration(float64(remainingItems)/itemsPerSecond) * time.Second
+
+			// Отправляем состояние прогресса
+			a.Hub.BroadcastProgress(ProgressUpdate{
+				Type:           SyncProgressType,
+				CurrentItem:    completed,
+				TotalItems:     totalItems,
+				CompletedItems: completed,
+				Percentage:     float64(completed) / float64(totalItems) * 100,
+				ETA:            eta.Round(time.Second).String(),
+			})
+			mu.Unlock()
+		}(uuid)
+	}
+
+	wg.Wait()
+
+	// Финальное состояние прогресса
+	a.Hub.BroadcastProgress(ProgressUpdate{
+		Type:           SyncProgressType,
+		CurrentItem:    totalItems,
+		TotalItems:     totalItems,
+		CompletedItems: totalItems,
+		Percentage:     100,
+		ETA:            "0s",
+	})
+
+	result := SyncResult{Completed: completed, Total: totalItems, Errors: errors}
+
+	// Расписание звонков выводится после загрузки всех групп
+	mismatches, err := a.RefreshTimeSlots()
+	if err != nil {
+		log.Printf("Failed to refresh time slots: %v", err)
+		result.Errors = append(result.Errors, fmt.Sprintf("Failed to refresh time slots: %v", err))
+	}
+	result.SlotMismatches = mismatches
+
+	return result, nil
+}
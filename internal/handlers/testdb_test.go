@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testConnector - соединение database/sql для тестов без PostgreSQL. Запрос к data_versions возвращает
+// версию version для запрошенной области, запрос с подстрокой fail - ошибку, запрос с подстрокой-ключом
+// rows - эти строки, остальные запросы - пустой результат. Изменения затрагивают affected строк
+type testConnector struct {
+	version  int64
+	fail     string
+	rows     map[string]testRows
+	affected int64
+}
+
+// testRows - результат запроса: названия столбцов и значения строк
+type testRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func newTestDB(t *testing.T, connector testConnector) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(connector)}),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	return db
+}
+
+func (c testConnector) Connect(context.Context) (driver.Conn, error) { return testConn(c), nil }
+func (c testConnector) Driver() driver.Driver                        { return nil }
+
+type testConn testConnector
+
+func (c testConn) Prepare(query string) (driver.Stmt, error) {
+	return testStmt{testConnector(c), query}, nil
+}
+func (testConn) Close() error              { return nil }
+func (testConn) Begin() (driver.Tx, error) { return testTx{}, nil }
+
+type testTx struct{}
+
+func (testTx) Commit() error   { return nil }
+func (testTx) Rollback() error { return nil }
+
+type testStmt struct {
+	testConnector
+	query string
+}
+
+func (testStmt) Close() error  { return nil }
+func (testStmt) NumInput() int { return -1 }
+
+func (s testStmt) Exec([]driver.Value) (driver.Result, error) {
+	if s.fail != "" && strings.Contains(s.query, s.fail) {
+		return nil, errors.New("query failed")
+	}
+	return driver.RowsAffected(s.affected), nil
+}
+
+func (s testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.fail != "" && strings.Contains(s.query, s.fail) {
+		return nil, errors.New("query failed")
+	}
+	if strings.Contains(s.query, "data_versions") {
+		return &testRows{
+			columns: []string{"scope", "version", "updated_at"},
+			values:  [][]driver.Value{{args[0], s.version, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)}},
+		}, nil
+	}
+	for substr, rows := range s.rows {
+		if strings.Contains(s.query, substr) {
+			return &testRows{columns: rows.columns, values: rows.values}, nil
+		}
+	}
+	return &testRows{}, nil
+}
+
+func (r *testRows) Columns() []string { return r.columns }
+func (r *testRows) Close() error      { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
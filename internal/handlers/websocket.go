@@ -11,8 +11,15 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// Типы обновлений прогресса
+const (
+	SyncProgressType   = "insertProgress"
+	ExportProgressType = "exportProgress"
+)
+
 type ProgressUpdate struct {
 	Type           string  `json:"type"`
+	JobID          string  `json:"jobId,omitempty"` // Задание выгрузки, к которому относится прогресс
 	CurrentItem    int     `json:"currentItem"`
 	TotalItems     int     `json:"totalItems"`
 	CompletedItems int     `json:"completedItems"`
@@ -32,6 +39,8 @@ type WebSocketHub struct {
 	register   chan *Client
 	unregister chan *Client
 	mu         sync.Mutex
+	// Подписчики внутри процесса (например, потоки gRPC)
+	subscribers map[chan ProgressUpdate]struct{}
 }
 
 var upgrader = websocket.Upgrader{
@@ -42,10 +51,11 @@ var upgrader = websocket.Upgrader{
 
 func NewWebSocketHub() *WebSocketHub {
 	return &WebSocketHub{
-		clients:    make(map[*Client]bool),
-		broadcast:  make(chan []byte, 256), // Добавляем буферизированный канал для избежания блокировки
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
+		clients:     make(map[*Client]bool),
+		broadcast:   make(chan []byte, 256), // Добавляем буферизированный канал для избежания блокировки
+		register:    make(chan *Client),
+		unregister:  make(chan *Client),
+		subscribers: make(map[chan ProgressUpdate]struct{}),
 	}
 }
 
@@ -78,8 +88,34 @@ func (h *WebSocketHub) Run() {
 	}
 }
 
+// Subscribe подписывает на обновления прогресса без WebSocket-соединения.
+// Возвращает канал обновлений и функцию отписки
+func (h *WebSocketHub) Subscribe() (<-chan ProgressUpdate, func()) {
+	ch := make(chan ProgressUpdate, 256)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			h.mu.Unlock()
+		})
+	}
+}
+
 func (h *WebSocketHub) BroadcastProgress(update ProgressUpdate) {
 	h.mu.Lock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- update:
+		default:
+			// Подписчик не успевает читать, обновление пропускается
+		}
+	}
 	if len(h.clients) == 0 {
 		h.mu.Unlock()
 		return // Нет подключенных клиентов, сворачиваем работу
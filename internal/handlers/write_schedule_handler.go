@@ -1,116 +1,19 @@
 package handlers
 
 import (
-	"encoding/csv"
-	"fmt"
-	"net/http"
-	"os"
-	"strings"
-
-	"github.com/kosttiik/semesterly_backend/internal/models"
 	"github.com/labstack/echo/v4"
 )
 
-// WriteScheduleToFileHandler сохраняет расписание в CSV файл
+// WriteScheduleToFileHandler оставлен для старых клиентов: расписание больше не сохраняется на диск сервера,
+// а передается в ответе так же, как в GET /export/schedule.csv
 // @Summary Сохранение расписания
-// @Description Сохраняет данные расписания в CSV файл
+// @Description Устарел, используйте GET /export/schedule.csv. Возвращает все расписание в CSV вместо сохранения файла на сервере
 // @Tags WriteSchedule
-// @Accept json
-// @Produce json
-// @Success 200 {object} map[string]string "message: Schedule written to file successfully"
-// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items" "error: Failed to write schedule to file"
+// @Produce text/csv
+// @Success 200 {file} file "Файл CSV"
+// @Failure 500 {object} map[string]string "error: Failed to fetch schedule items"
+// @Deprecated
 // @Router /write-schedule [post]
 func (a *App) WriteScheduleToFileHandler(c echo.Context) error {
-	var scheduleItems []models.ScheduleItem
-
-	// Загрузка данных с подгрузкой связанных сущностей
-	if err := a.DB.Preload("Groups").Preload("Teachers").Preload("Audiences").Preload("Disciplines").Find(&scheduleItems).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch schedule items"})
-	}
-
-	filePath := "/usr/src/semesterly/data/schedule.csv"
-	if err := writeToCSV(filePath, scheduleItems); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to write schedule to file"})
-	}
-
-	return c.JSON(http.StatusOK, map[string]string{"message": "Schedule written to file successfully"})
-}
-
-// writeToCSV записывает данные расписания в CSV файл
-func writeToCSV(filePath string, scheduleItems []models.ScheduleItem) error {
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Заголовки CSV
-	writer.Write([]string{
-		"Day",
-		"Time",
-		"Week",
-		"Stream",
-		"EndTime",
-		"StartTime",
-		"DisciplineAbbr",
-		"DisciplineActType",
-		"DisciplineFullName",
-		"DisciplineShortName",
-		"Teachers",
-		"Audiences",
-		"Groups",
-	})
-
-	for _, item := range scheduleItems {
-		var disciplineAbbr, disciplineActType, disciplineFullName, disciplineShortName string
-		if len(item.Disciplines) > 0 {
-			disciplineAbbr = item.Disciplines[0].Abbr
-			disciplineActType = item.Disciplines[0].ActType
-			disciplineFullName = item.Disciplines[0].FullName
-			disciplineShortName = item.Disciplines[0].ShortName
-		}
-
-		// Форматирование списка преподавателей
-		teachers := make([]string, len(item.Teachers))
-		for i, teacher := range item.Teachers {
-			teachers[i] = fmt.Sprintf("%s %s %s", teacher.LastName, teacher.FirstName, teacher.MiddleName)
-		}
-
-		// Форматирование списка аудиторий
-		audiences := make([]string, len(item.Audiences))
-		for i, audience := range item.Audiences {
-			audiences[i] = audience.Name
-		}
-
-		// Форматирование списка групп
-		groups := make([]string, len(item.Groups))
-		for i, group := range item.Groups {
-			groups[i] = group.Name
-		}
-
-		// Запись строки в CSV
-		err := writer.Write([]string{
-			fmt.Sprintf("%d", item.Day),
-			fmt.Sprintf("%d", item.Time),
-			item.Week,
-			item.Stream,
-			item.EndTime,
-			item.StartTime,
-			disciplineAbbr,
-			disciplineActType,
-			disciplineFullName,
-			disciplineShortName,
-			strings.Join(teachers, "; "),
-			strings.Join(audiences, "; "),
-			strings.Join(groups, "; "),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to write to CSV: %w", err)
-		}
-	}
-
-	return nil
+	return a.ExportCSVHandler(c)
 }
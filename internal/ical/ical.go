@@ -0,0 +1,204 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeLayout = "20060102T150405"
+	maxLineOctets  = 75 // RFC 5545, 3.1: строки длиннее 75 октетов переносятся
+)
+
+// Event - событие VEVENT
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	RRule       string      // Правило повторения без префикса RRULE:
+	ExDates     []time.Time // Исключенные повторения
+	RDates      []time.Time // Дополнительные повторения
+	Categories  []string
+}
+
+// Calendar - календарь VCALENDAR с событиями в одной временной зоне
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+// Encode записывает календарь в формате iCalendar
+func (c Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw, location: c.Location}
+	if e.location == nil {
+		e.location = time.UTC
+	}
+
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:-//semesterly//schedule//RU")
+	e.line("CALSCALE:GREGORIAN")
+	e.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		e.property("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if e.location != time.UTC {
+		e.property("X-WR-TIMEZONE", e.location.String())
+		e.timezone(c.Events)
+	}
+
+	stamp := time.Now().UTC().Format(dateTimeLayout) + "Z"
+	for _, event := range c.Events {
+		e.line("BEGIN:VEVENT")
+		e.property("UID", escapeText(event.UID))
+		e.property("DTSTAMP", stamp)
+		e.dateTime("DTSTART", event.Start)
+		e.dateTime("DTEND", event.End)
+		if event.RRule != "" {
+			e.property("RRULE", event.RRule)
+		}
+		e.dateTimes("EXDATE", event.ExDates)
+		e.dateTimes("RDATE", event.RDates)
+		e.property("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			e.property("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.Location != "" {
+			e.property("LOCATION", escapeText(event.Location))
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = escapeText(category)
+			}
+			e.property("CATEGORIES", strings.Join(categories, ","))
+		}
+		e.line("END:VEVENT")
+	}
+
+	e.line("END:VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+type encoder struct {
+	w        *bufio.Writer
+	location *time.Location
+	err      error
+}
+
+// line записывает строку содержимого, перенося ее по 75 октетов без разрыва символов UTF-8
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if width+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, e.err = e.w.WriteString(b.String())
+}
+
+func (e *encoder) property(name, value string) {
+	e.line(name + ":" + value)
+}
+
+func (e *encoder) dateTime(name string, t time.Time) {
+	if e.location == time.UTC {
+		e.property(name, t.UTC().Format(dateTimeLayout)+"Z")
+		return
+	}
+	e.property(name+";TZID="+e.location.String(), t.In(e.location).Format(dateTimeLayout))
+}
+
+func (e *encoder) dateTimes(name string, times []time.Time) {
+	if len(times) == 0 {
+		return
+	}
+
+	sorted := append([]time.Time(nil), times...)
+	sortTimes(sorted)
+
+	values := make([]string, len(sorted))
+	for i, t := range sorted {
+		if e.location == time.UTC {
+			values[i] = t.UTC().Format(dateTimeLayout) + "Z"
+		} else {
+			values[i] = t.In(e.location).Format(dateTimeLayout)
+		}
+	}
+
+	if e.location == time.UTC {
+		e.property(name, strings.Join(values, ","))
+		return
+	}
+	e.property(name+";TZID="+e.location.String(), strings.Join(values, ","))
+}
+
+// timezone описывает временную зону календаря. Смещение берется на момент первого события,
+// переходы на летнее время не описываются: для зон без них (например, Europe/Moscow) этого достаточно
+func (e *encoder) timezone(events []Event) {
+	reference := time.Now()
+	if len(events) > 0 {
+		reference = events[0].Start
+	}
+	_, offset := reference.In(e.location).Zone()
+	name, _ := reference.In(e.location).Zone()
+
+	e.line("BEGIN:VTIMEZONE")
+	e.property("TZID", e.location.String())
+	e.line("BEGIN:STANDARD")
+	e.property("DTSTART", "19700101T000000")
+	e.property("TZOFFSETFROM", formatOffset(offset))
+	e.property("TZOFFSETTO", formatOffset(offset))
+	e.property("TZNAME", escapeText(name))
+	e.line("END:STANDARD")
+	e.line("END:VTIMEZONE")
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// escapeText экранирует значение типа TEXT (RFC 5545, 3.3.11)
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// UntilUTC форматирует границу повторений для RRULE UNTIL
+func UntilUTC(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout) + "Z"
+}
@@ -0,0 +1,111 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestScheduleEvents(t *testing.T) {
+	cal := calendar.New(time.UTC, calendar.Data{
+		Semesters: []calendar.Period{{Start: date(2025, 2, 3), End: date(2025, 3, 2)}},
+		Holidays:  []calendar.Holiday{{Title: "День защитника Отечества", Date: date(2025, 2, 24)}},
+	})
+
+	items := []models.ScheduleItem{
+		{ID: 1, Day: 1, Week: models.WeekAll, StartTime: "08:30", EndTime: "10:05"},
+		{ID: 2, Day: 1, Week: models.WeekDenominator, StartTime: "10:15", EndTime: "11:50"},
+	}
+
+	events, err := ScheduleEvents(cal, items)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	weekly := events[0]
+	assert.Equal(t, ItemUIDs(items)[0]+"-20250203@semesterly", weekly.UID)
+	assert.Equal(t, time.Date(2025, 2, 3, 8, 30, 0, 0, time.UTC), weekly.Start)
+	assert.Equal(t, time.Date(2025, 2, 3, 10, 5, 0, 0, time.UTC), weekly.End)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=1;UNTIL=20250302T235959Z", weekly.RRule)
+	assert.Equal(t, []time.Time{time.Date(2025, 2, 24, 8, 30, 0, 0, time.UTC)}, weekly.ExDates)
+	assert.Empty(t, weekly.RDates)
+
+	// Знаменатель начинается со второй недели и идет через неделю: 10.02 и 24.02 (праздник)
+	biweekly := events[1]
+	assert.Equal(t, time.Date(2025, 2, 10, 10, 15, 0, 0, time.UTC), biweekly.Start)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;UNTIL=20250302T235959Z", biweekly.RRule)
+	assert.Equal(t, []time.Time{time.Date(2025, 2, 24, 10, 15, 0, 0, time.UTC)}, biweekly.ExDates)
+}
+
+func TestItemUIDsStableAcrossResync(t *testing.T) {
+	group := models.Group{UUID: "g1"}
+	lesson := func(id uint, teacher, audience string) models.ScheduleItem {
+		return models.ScheduleItem{
+			ID: id, Day: 2, Week: models.WeekNumerator, StartTime: "10:15",
+			Groups:      []models.Group{group},
+			Disciplines: []models.Discipline{{FullName: "Физика", ActType: "lab"}},
+			Teachers:    []models.Teacher{{UUID: teacher}},
+			Audiences:   []models.Audience{{UUID: audience}},
+		}
+	}
+
+	before := ItemUIDs([]models.ScheduleItem{lesson(1, "t1", "a1"), lesson(2, "t2", "a2")})
+	// После синхронизации ID другие, порядок другой, у первой подгруппы сменилась аудитория
+	after := ItemUIDs([]models.ScheduleItem{lesson(12, "t2", "a2"), lesson(11, "t1", "a3")})
+
+	assert.NotEqual(t, before[0], before[1])
+	assert.Equal(t, before[0], after[1])
+	assert.Equal(t, before[1], after[0])
+}
+
+func TestExamEvents(t *testing.T) {
+	exams := []models.Exam{
+		{ID: 7, ExamDate: "20.06.2025", ExamTime: "10:00", Room: "218л", Disciplines: []models.Discipline{{FullName: "Математический анализ"}}},
+		{ID: 8, ExamDate: "скоро", ExamTime: "10:00"},
+	}
+
+	events := ExamEvents(time.UTC, exams)
+	require.Len(t, events, 1)
+	assert.True(t, strings.HasPrefix(events[0].UID, "exam-"))
+	assert.Equal(t, "Экзамен: Математический анализ", events[0].Summary)
+	assert.Equal(t, time.Date(2025, 6, 20, 10, 0, 0, 0, time.UTC), events[0].Start)
+	assert.Equal(t, events[0].Start.Add(ExamDuration), events[0].End)
+}
+
+func TestEncode(t *testing.T) {
+	cal := Calendar{
+		Name: "ИУ7-11Б",
+		Events: []Event{{
+			UID:         "item-1@semesterly",
+			Summary:     "Физика; лекция, поток",
+			Description: strings.Repeat("Описание ", 20),
+			Start:       time.Date(2025, 2, 3, 8, 30, 0, 0, time.UTC),
+			End:         time.Date(2025, 2, 3, 10, 5, 0, 0, time.UTC),
+			RRule:       "FREQ=WEEKLY;INTERVAL=1",
+			ExDates:     []time.Time{time.Date(2025, 2, 24, 8, 30, 0, 0, time.UTC)},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cal.Encode(&buf))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "SUMMARY:Физика\\; лекция\\, поток\r\n")
+	assert.Contains(t, out, "DTSTART:20250203T083000Z\r\n")
+	assert.Contains(t, out, "EXDATE:20250224T083000Z\r\n")
+
+	for _, line := range strings.Split(out, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+}
@@ -0,0 +1,323 @@
+package ical
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+)
+
+// ExamDuration - длительность экзамена, в lks указывается только время начала
+const ExamDuration = 3 * time.Hour
+
+// examDateLayouts - форматы даты экзамена, встречающиеся в lks
+var examDateLayouts = []string{"2006-01-02", "02.01.2006", time.RFC3339}
+
+// ScheduleEvents превращает недельное расписание в повторяющиеся события, по одному на занятие и семестр.
+// Повторения строятся правилом RRULE по четности недели, а праздники, сессии и переносы дней
+// описываются через EXDATE и RDATE по результату calendar.Expand
+func ScheduleEvents(cal *calendar.Calendar, scheduleItems []models.ScheduleItem) ([]Event, error) {
+	if !cal.Configured() {
+		return nil, calendar.ErrNotConfigured
+	}
+
+	uids := ItemUIDs(scheduleItems)
+	events := make([]Event, 0, len(scheduleItems))
+	for _, semester := range cal.Semesters() {
+		for i, item := range scheduleItems {
+			event, ok, err := scheduleEvent(cal, semester, item, uids[i])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				events = append(events, event)
+			}
+		}
+	}
+	return events, nil
+}
+
+func scheduleEvent(cal *calendar.Calendar, semester calendar.Period, item models.ScheduleItem, uid string) (Event, bool, error) {
+	if item.Day < 1 || item.Day > 7 {
+		return Event{}, false, nil
+	}
+
+	occurrences, err := cal.Expand([]models.ScheduleItem{item}, semester.Start, semester.End)
+	if err != nil {
+		return Event{}, false, err
+	}
+	if len(occurrences) == 0 {
+		return Event{}, false, nil
+	}
+
+	// Первое занятие по правилу: день недели первой недели семестра, для знаменателя - второй
+	first := semester.Start.AddDate(0, 0, item.Day-1)
+	interval := 1
+	if item.Week != models.WeekAll {
+		interval = 2
+		if item.Week == models.WeekDenominator {
+			first = first.AddDate(0, 0, 7)
+		}
+	}
+
+	start, err := clock(first, item.StartTime)
+	if err != nil {
+		return Event{}, false, err
+	}
+	end, err := clock(first, item.EndTime)
+	if err != nil {
+		return Event{}, false, err
+	}
+
+	until := time.Date(semester.End.Year(), semester.End.Month(), semester.End.Day(), 23, 59, 59, 0, semester.End.Location())
+
+	// Сравниваем даты по правилу с фактическими занятиями
+	planned := make(map[int64]time.Time)
+	for date := start; !date.After(until); date = date.AddDate(0, 0, 7*interval) {
+		planned[date.Unix()] = date
+	}
+	actual := make(map[int64]time.Time, len(occurrences))
+	for _, o := range occurrences {
+		actual[o.Start.Unix()] = o.Start
+	}
+
+	event := Event{
+		UID:         fmt.Sprintf("%s-%s@semesterly", uid, semester.Start.Format("20060102")),
+		Summary:     Summary(item),
+		Description: Description(item),
+		Location:    Location(item),
+		Start:       start,
+		End:         end,
+		RRule:       fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d;UNTIL=%s", interval, UntilUTC(until)),
+	}
+	for key, date := range planned {
+		if _, ok := actual[key]; !ok {
+			event.ExDates = append(event.ExDates, date)
+		}
+	}
+	for key, date := range actual {
+		if _, ok := planned[key]; !ok {
+			event.RDates = append(event.RDates, date)
+		}
+	}
+	sortTimes(event.ExDates)
+	sortTimes(event.RDates)
+
+	for _, d := range item.Disciplines {
+		if d.ActType != "" {
+			event.Categories = append(event.Categories, d.ActType)
+		}
+	}
+
+	return event, true, nil
+}
+
+// ItemUIDs возвращает идентификаторы занятий, не зависящие от ScheduleItem.ID. При синхронизации с lks
+// занятия удаляются и создаются заново, поэтому идентификатор строится из того, что определяет занятие:
+// групп, дня, недели, времени начала и дисциплины. Смена преподавателя или аудитории сохраняет идентификатор,
+// и календарные приложения обновляют событие вместо создания копии. Совпадающие занятия (например, у подгрупп)
+// различаются порядковым номером после сортировки по преподавателям и аудиториям
+func ItemUIDs(scheduleItems []models.ScheduleItem) []string {
+	type keyed struct {
+		index    int
+		identity string
+		detail   string
+	}
+
+	keys := make([]keyed, len(scheduleItems))
+	for i, item := range scheduleItems {
+		keys[i] = keyed{index: i, identity: itemIdentity(item), detail: itemDetail(item)}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].identity != keys[j].identity {
+			return keys[i].identity < keys[j].identity
+		}
+		return keys[i].detail < keys[j].detail
+	})
+
+	uids := make([]string, len(scheduleItems))
+	seen := make(map[string]int)
+	for _, k := range keys {
+		identity := k.identity
+		if n := seen[k.identity]; n > 0 {
+			identity = fmt.Sprintf("%s#%d", identity, n)
+		}
+		seen[k.identity]++
+
+		sum := sha1.Sum([]byte(identity))
+		uids[k.index] = "lesson-" + hex.EncodeToString(sum[:10])
+	}
+	return uids
+}
+
+func itemIdentity(item models.ScheduleItem) string {
+	groups := make([]string, 0, len(item.Groups))
+	for _, g := range item.Groups {
+		groups = append(groups, g.UUID)
+	}
+	sort.Strings(groups)
+
+	disciplines := make([]string, 0, len(item.Disciplines))
+	for _, d := range item.Disciplines {
+		disciplines = append(disciplines, d.FullName+"/"+d.ActType)
+	}
+	sort.Strings(disciplines)
+
+	return fmt.Sprintf("%d|%s|%s|%s|%s",
+		item.Day, item.Week, item.StartTime, strings.Join(groups, ","), strings.Join(disciplines, ","))
+}
+
+// itemDetail упорядочивает совпадающие занятия: сначала по преподавателям, которые у подгрупп меняются реже аудиторий
+func itemDetail(item models.ScheduleItem) string {
+	teachers := make([]string, 0, len(item.Teachers))
+	for _, t := range item.Teachers {
+		teachers = append(teachers, t.UUID)
+	}
+	sort.Strings(teachers)
+
+	audiences := make([]string, 0, len(item.Audiences))
+	for _, a := range item.Audiences {
+		audiences = append(audiences, a.UUID)
+	}
+	sort.Strings(audiences)
+
+	return strings.Join(teachers, ",") + "|" + strings.Join(audiences, ",")
+}
+
+// ExamEvents превращает экзамены в разовые события. Экзамены с неразборчивой датой или временем пропускаются
+func ExamEvents(location *time.Location, exams []models.Exam) []Event {
+	events := make([]Event, 0, len(exams))
+	for _, exam := range exams {
+		start, ok := ExamStart(location, exam)
+		if !ok {
+			continue
+		}
+
+		names := make([]string, 0, len(exam.Disciplines))
+		for _, d := range exam.Disciplines {
+			names = append(names, d.FullName)
+		}
+		summary := "Экзамен"
+		if len(names) > 0 {
+			summary += ": " + strings.Join(names, ", ")
+		}
+
+		events = append(events, Event{
+			UID:         examUID(exam) + "@semesterly",
+			Summary:     summary,
+			Description: strings.TrimSpace(strings.Join([]string{exam.LastName, exam.FirstName, exam.MiddleName}, " ")),
+			Location:    exam.Room,
+			Start:       start,
+			End:         start.Add(ExamDuration),
+			Categories:  []string{"exam"},
+		})
+	}
+	return events
+}
+
+// examUID строит идентификатор экзамена из его содержимого, так как записи пересоздаются при синхронизации
+func examUID(exam models.Exam) string {
+	disciplines := make([]string, 0, len(exam.Disciplines))
+	for _, d := range exam.Disciplines {
+		disciplines = append(disciplines, d.FullName)
+	}
+	sort.Strings(disciplines)
+
+	identity := strings.Join([]string{exam.ExamDate, exam.ExamTime, exam.LastName, exam.FirstName, exam.MiddleName, strings.Join(disciplines, ",")}, "|")
+	sum := sha1.Sum([]byte(identity))
+	return "exam-" + hex.EncodeToString(sum[:10])
+}
+
+// ExamStart разбирает дату и время начала экзамена
+func ExamStart(location *time.Location, exam models.Exam) (time.Time, bool) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	for _, layout := range examDateLayouts {
+		date, err := time.ParseInLocation(layout, strings.TrimSpace(exam.ExamDate), location)
+		if err != nil {
+			continue
+		}
+		start, err := clock(date.In(location), exam.ExamTime)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return start, true
+	}
+	return time.Time{}, false
+}
+
+// Summary возвращает название занятия: полное название дисциплины
+func Summary(item models.ScheduleItem) string {
+	names := make([]string, 0, len(item.Disciplines))
+	for _, d := range item.Disciplines {
+		name := d.FullName
+		if name == "" {
+			name = d.ShortName
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "Занятие"
+	}
+	return strings.Join(names, ", ")
+}
+
+// Description перечисляет преподавателей, группы и поток занятия
+func Description(item models.ScheduleItem) string {
+	var lines []string
+
+	teachers := make([]string, 0, len(item.Teachers))
+	for _, t := range item.Teachers {
+		teachers = append(teachers, strings.TrimSpace(strings.Join([]string{t.LastName, t.FirstName, t.MiddleName}, " ")))
+	}
+	if len(teachers) > 0 {
+		lines = append(lines, "Преподаватели: "+strings.Join(teachers, ", "))
+	}
+
+	groups := make([]string, 0, len(item.Groups))
+	for _, g := range item.Groups {
+		groups = append(groups, g.Name)
+	}
+	if len(groups) > 0 {
+		lines = append(lines, "Группы: "+strings.Join(groups, ", "))
+	}
+
+	if item.Stream != "" {
+		lines = append(lines, "Поток: "+item.Stream)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Location перечисляет аудитории занятия с корпусами
+func Location(item models.ScheduleItem) string {
+	audiences := make([]string, 0, len(item.Audiences))
+	for _, a := range item.Audiences {
+		if a.Building != "" {
+			audiences = append(audiences, a.Name+" ("+a.Building+")")
+		} else {
+			audiences = append(audiences, a.Name)
+		}
+	}
+	return strings.Join(audiences, ", ")
+}
+
+func sortTimes(times []time.Time) {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+}
+
+// clock возвращает момент времени value (HH:MM) в дату date
+func clock(date time.Time, value string) (time.Time, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location()), nil
+}
@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// csvRequired - столбцы, без которых занятие не восстановить
+var csvRequired = []string{"day", "time", "week", "starttime", "endtime", "disciplinefullname", "groups"}
+
+// ParseCSV разбирает CSV со столбцами прежней выгрузки writeToCSV (Day, Time, ..., Groups) или
+// текущего экспорта /export/schedule.csv. Названия столбцов сравниваются без учета регистра,
+// разделитель (запятая, точка с запятой, табуляция или |) и кодировка (UTF-8 или CP1251) определяются по содержимому.
+// Списки преподавателей, аудиторий и групп разделяются точкой с запятой
+func ParseCSV(r io.Reader) (Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Result{}, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	if !utf8.Valid(data) {
+		if data, err = charmap.Windows1251.NewDecoder().Bytes(data); err != nil {
+			return Result{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = sniffDelimiter(data)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return Result{}, fmt.Errorf("%w: empty file", ErrInvalidFile)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvRequired {
+		if _, ok := columns[name]; !ok {
+			return Result{}, fmt.Errorf("%w: missing column %q", ErrInvalidFile, name)
+		}
+	}
+
+	var result Result
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, RowError{Row: row, Message: err.Error()})
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		if field("type") == "exam" {
+			exam := csvExam(field)
+			if errs := validateExam(row, exam); len(errs) > 0 {
+				result.Errors = append(result.Errors, errs...)
+				continue
+			}
+			result.Exams = append(result.Exams, exam)
+			result.ExamRows = append(result.ExamRows, row)
+			continue
+		}
+
+		items, errs := csvItems(row, field)
+		for _, item := range items {
+			errs = append(errs, validateItem(row, "", item)...)
+		}
+		if len(errs) > 0 {
+			result.Errors = append(result.Errors, errs...)
+			continue
+		}
+		result.Items = append(result.Items, items...)
+	}
+	return result, nil
+}
+
+// csvItems восстанавливает занятия строки. Экспорт перечисляет все дисциплины занятия через точку с запятой,
+// каждая становится отдельным занятием того же слота и потока и объединяется с остальными при сохранении
+func csvItems(row int, field func(string) string) ([]models.ScheduleItem, []RowError) {
+	var errs []RowError
+	number := func(name string) int {
+		value, err := strconv.Atoi(field(name))
+		if err != nil {
+			errs = append(errs, RowError{Row: row, Column: name, Message: fmt.Sprintf("invalid number %q", field(name))})
+		}
+		return value
+	}
+
+	item := models.ScheduleItem{
+		Day:       number("day"),
+		Time:      number("time"),
+		Week:      field("week"),
+		Stream:    field("stream"),
+		StartTime: field("starttime"),
+		EndTime:   field("endtime"),
+	}
+	for _, name := range splitList(field("teachers"), ";") {
+		item.Teachers = append(item.Teachers, parseTeacher(name))
+	}
+	for _, name := range splitList(field("audiences"), ";") {
+		item.Audiences = append(item.Audiences, models.Audience{Name: name})
+	}
+	for _, name := range splitList(field("groups"), ";") {
+		item.Groups = append(item.Groups, models.Group{Name: name})
+	}
+
+	names := strings.Split(field("disciplinefullname"), ";")
+	disciplines := make([]models.Discipline, len(names))
+	for _, column := range []struct {
+		name string
+		set  func(*models.Discipline, string)
+	}{
+		{"disciplinefullname", func(d *models.Discipline, v string) { d.FullName = v }},
+		{"disciplineabbr", func(d *models.Discipline, v string) { d.Abbr = v }},
+		{"disciplineacttype", func(d *models.Discipline, v string) { d.ActType = v }},
+		{"disciplineshortname", func(d *models.Discipline, v string) { d.ShortName = v }},
+	} {
+		value := field(column.name)
+		if value == "" {
+			continue
+		}
+		values := strings.Split(value, ";")
+		if len(values) != len(names) {
+			errs = append(errs, RowError{Row: row, Column: column.name, Message: fmt.Sprintf("expected %d values separated by semicolons, got %d", len(names), len(values))})
+			continue
+		}
+		for i, v := range values {
+			column.set(&disciplines[i], strings.TrimSpace(v))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	items := make([]models.ScheduleItem, len(disciplines))
+	for i, d := range disciplines {
+		items[i] = item
+		items[i].DisciplineRaw = d
+	}
+	return items, nil
+}
+
+func csvExam(field func(string) string) models.Exam {
+	exam := models.Exam{
+		Room:          field("audiences"),
+		ExamDate:      field("date"),
+		ExamTime:      field("starttime"),
+		DisciplineRaw: field("disciplinefullname"),
+	}
+	teacher := parseTeacher(field("teachers"))
+	exam.LastName, exam.FirstName, exam.MiddleName = teacher.LastName, teacher.FirstName, teacher.MiddleName
+	for _, name := range splitList(field("groups"), ";") {
+		exam.Groups = append(exam.Groups, models.Group{Name: name})
+	}
+	return exam
+}
+
+// sniffDelimiter выбирает разделитель, который чаще всего встречается в строке заголовков
+func sniffDelimiter(data []byte) rune {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	best, count := ',', bytes.Count(header, []byte(","))
+	for _, candidate := range []rune{';', '\t', '|'} {
+		if n := bytes.Count(header, []byte(string(candidate))); n > count {
+			best, count = candidate, n
+		}
+	}
+	return best
+}
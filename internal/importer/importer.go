@@ -0,0 +1,233 @@
+// Package importer разбирает загруженные вручную расписания (CSV, сетка XLSX, JSON lks)
+// в занятия и экзамены в формате lks, чтобы сохранять их тем же путем, что и данные из lks
+package importer
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/ical"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+)
+
+// Форматы загружаемых файлов
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatJSON = "json"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrInvalidFile   = errors.New("invalid import file")
+)
+
+// RowError - ошибка проверки одной строки файла. Row - номер строки с единицы,
+// для JSON - номер элемента data.schedule
+type RowError struct {
+	Row     int    `json:"row"`
+	Sheet   string `json:"sheet,omitempty"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	location := fmt.Sprintf("row %d", e.Row)
+	if e.Sheet != "" {
+		location = fmt.Sprintf("sheet %q, %s", e.Sheet, location)
+	}
+	if e.Column != "" {
+		location += ", column " + e.Column
+	}
+	return location + ": " + e.Message
+}
+
+// Result - разобранный файл. Группы, преподаватели и аудитории из CSV и XLSX известны
+// только по названиям, UUID для них подбирает вызывающий код
+type Result struct {
+	Items    []models.ScheduleItem
+	Exams    []models.Exam
+	ExamRows []int // Номера строк файла, из которых прочитаны Exams
+	Errors   []RowError
+}
+
+// FormatFromName определяет формат по расширению файла
+func FormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".tsv", ".txt":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	case ".json":
+		return FormatJSON
+	}
+	return ""
+}
+
+// Parse разбирает файл в формате format. Ошибка возвращается, только если файл не удалось прочитать
+// целиком, ошибки отдельных строк собираются в Result.Errors
+func Parse(format string, r io.Reader) (Result, error) {
+	var result Result
+	var err error
+	switch format {
+	case FormatCSV:
+		result, err = ParseCSV(r)
+	case FormatXLSX:
+		result, err = ParseXLSX(r)
+	case FormatJSON:
+		result, err = ParseJSON(r)
+	default:
+		return Result{}, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	assignStreams(result.Items)
+	return result, nil
+}
+
+var clockPattern = regexp.MustCompile(`^([01]?\d|2[0-3]):[0-5]\d$`)
+
+// validateItem проверяет занятие так же строго, как его ожидают сетка, календарь и проверка конфликтов
+func validateItem(row int, sheet string, item models.ScheduleItem) []RowError {
+	var errs []RowError
+	fail := func(column, format string, args ...any) {
+		errs = append(errs, RowError{Row: row, Sheet: sheet, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if item.Day < 1 || item.Day > timetable.Days {
+		fail("day", "day must be between 1 and %d, got %d", timetable.Days, item.Day)
+	}
+	if item.Time < 1 {
+		fail("time", "time must be a positive slot number, got %d", item.Time)
+	}
+	switch item.Week {
+	case models.WeekAll, models.WeekNumerator, models.WeekDenominator:
+	default:
+		fail("week", "week must be one of all, ch, zn, got %q", item.Week)
+	}
+
+	startOK := clockPattern.MatchString(item.StartTime)
+	if !startOK {
+		fail("startTime", "invalid start time %q, expected HH:MM", item.StartTime)
+	}
+	endOK := clockPattern.MatchString(item.EndTime)
+	if !endOK {
+		fail("endTime", "invalid end time %q, expected HH:MM", item.EndTime)
+	}
+	if startOK && endOK && minutes(item.StartTime) >= minutes(item.EndTime) {
+		fail("endTime", "end time %s is not after start time %s", item.EndTime, item.StartTime)
+	}
+
+	if strings.TrimSpace(item.DisciplineRaw.FullName) == "" {
+		fail("disciplineFullName", "discipline name is required")
+	}
+	if len(item.Groups) == 0 {
+		fail("groups", "at least one group is required")
+	}
+	for _, g := range item.Groups {
+		if g.UUID == "" && strings.TrimSpace(g.Name) == "" {
+			fail("groups", "group must have a name or uuid")
+		}
+	}
+	for _, t := range item.Teachers {
+		if t.UUID == "" && strings.TrimSpace(t.LastName) == "" {
+			fail("teachers", "teacher must have a last name or uuid")
+		}
+	}
+	for _, a := range item.Audiences {
+		if a.UUID == "" && strings.TrimSpace(a.Name) == "" {
+			fail("audiences", "audience must have a name or uuid")
+		}
+	}
+	return errs
+}
+
+func validateExam(row int, exam models.Exam) []RowError {
+	var errs []RowError
+	if _, ok := ical.ExamStart(nil, exam); !ok {
+		errs = append(errs, RowError{Row: row, Column: "date", Message: fmt.Sprintf("invalid exam date %q or time %q", exam.ExamDate, exam.ExamTime)})
+	}
+	if strings.TrimSpace(exam.DisciplineRaw) == "" {
+		errs = append(errs, RowError{Row: row, Column: "disciplineFullName", Message: "discipline name is required"})
+	}
+	if len(exam.Groups) == 0 {
+		errs = append(errs, RowError{Row: row, Column: "groups", Message: "at least one group is required"})
+	}
+	return errs
+}
+
+func minutes(clock string) int {
+	var h, m int
+	fmt.Sscanf(clock, "%d:%d", &h, &m)
+	return h*60 + m
+}
+
+// assignStreams заполняет пустой поток ключом содержания занятия. Занятия одного слота с одинаковым
+// потоком объединяются при сохранении, поэтому без потока разные дисциплины слились бы в одно занятие
+func assignStreams(items []models.ScheduleItem) {
+	for i := range items {
+		if items[i].Stream != "" {
+			continue
+		}
+		d := items[i].DisciplineRaw
+		parts := []string{d.FullName, d.ActType}
+		for _, t := range items[i].Teachers {
+			parts = append(parts, t.UUID+t.LastName+t.FirstName+t.MiddleName)
+		}
+		for _, a := range items[i].Audiences {
+			parts = append(parts, a.UUID+a.Name)
+		}
+		sort.Strings(parts[2:])
+		sum := sha1.Sum([]byte(strings.Join(parts, "|")))
+		items[i].Stream = fmt.Sprintf("upload-%x", sum[:6])
+	}
+}
+
+// StableUUID возвращает UUID версии 5 для группы, преподавателя или аудитории, известных только по названию.
+// Повторная загрузка того же файла дает те же UUID
+func StableUUID(kind, name string) string {
+	sum := sha1.Sum([]byte("semesterly-import|" + kind + "|" + strings.ToLower(strings.TrimSpace(name))))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// parseTeacher разбирает «Фамилия Имя Отчество» или «Фамилия И. О.»
+func parseTeacher(name string) models.Teacher {
+	parts := strings.Fields(name)
+	var t models.Teacher
+	if len(parts) > 0 {
+		t.LastName = parts[0]
+	}
+	if len(parts) > 1 {
+		t.FirstName = parts[1]
+	}
+	if len(parts) > 2 {
+		t.MiddleName = strings.Join(parts[2:], " ")
+	}
+	// «Иванов И.И.» без пробела между инициалами
+	if t.MiddleName == "" && strings.Count(t.FirstName, ".") == 2 {
+		initials := strings.SplitAfterN(t.FirstName, ".", 2)
+		t.FirstName, t.MiddleName = initials[0], initials[1]
+	}
+	return t
+}
+
+// splitList разделяет перечисление, пропуская пустые элементы
+func splitList(value, sep string) []string {
+	var values []string
+	for _, part := range strings.Split(value, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/export"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lecture(group string, week string) models.ScheduleItem {
+	return models.ScheduleItem{
+		Day: 1, Time: 1, Week: week, StartTime: "08:30", EndTime: "10:05", Stream: "ИУ7-1",
+		Disciplines: []models.Discipline{{FullName: "Математический анализ", ActType: "lecture"}},
+		Teachers:    []models.Teacher{{LastName: "Иванов", FirstName: "Иван", MiddleName: "Иванович"}},
+		Audiences:   []models.Audience{{Name: "501ю"}},
+		Groups:      []models.Group{{Name: group}},
+	}
+}
+
+func TestParseCSVExportRoundTrip(t *testing.T) {
+	item := lecture("ИУ7-11Б", models.WeekAll)
+	item.Groups = append(item.Groups, models.Group{Name: "ИУ7-12Б"})
+	item.Disciplines = append(item.Disciplines, models.Discipline{FullName: "Линейная алгебра", ActType: "lecture"})
+
+	var buf bytes.Buffer
+	writer, err := export.NewCSVWriter(&buf, export.CSVOptions{Delimiter: ';', Encoding: export.EncodingCP1251})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteHeader())
+	require.NoError(t, writer.WriteItem(item))
+	require.NoError(t, writer.WriteExam(models.Exam{
+		Room: "218л", ExamDate: "2025-01-15", ExamTime: "09:00", LastName: "Петров", FirstName: "Петр",
+		Disciplines: []models.Discipline{{FullName: "Математический анализ"}},
+		Groups:      []models.Group{{Name: "ИУ7-11Б"}},
+	}))
+	require.NoError(t, writer.Close())
+
+	result, err := Parse(FormatCSV, &buf)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+
+	// Каждая дисциплина - отдельное занятие того же слота и потока
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, "Математический анализ", result.Items[0].DisciplineRaw.FullName)
+	assert.Equal(t, "Линейная алгебра", result.Items[1].DisciplineRaw.FullName)
+	assert.Equal(t, "ИУ7-1", result.Items[1].Stream)
+	assert.Equal(t, []models.Group{{Name: "ИУ7-11Б"}, {Name: "ИУ7-12Б"}}, result.Items[0].Groups)
+	assert.Equal(t, models.Teacher{LastName: "Иванов", FirstName: "Иван", MiddleName: "Иванович"}, result.Items[0].Teachers[0])
+
+	require.Len(t, result.Exams, 1)
+	assert.Equal(t, "Математический анализ", result.Exams[0].DisciplineRaw)
+	assert.Equal(t, "Петров", result.Exams[0].LastName)
+	assert.Equal(t, "218л", result.Exams[0].Room)
+}
+
+func TestParseCSVLegacyLayoutReportsRowErrors(t *testing.T) {
+	data := "Day,Time,Week,Stream,EndTime,StartTime,DisciplineAbbr,DisciplineActType,DisciplineFullName,DisciplineShortName,Teachers,Audiences,Groups\n" +
+		"2,3,ch,,13:50,12:00,ФИЗ,seminar,Физика,Физика,Сидоров С. С.,303,ДО-11\n" +
+		"8,x,odd,,10:00,11:00,,,,,,,\n"
+
+	result, err := Parse(FormatCSV, strings.NewReader(data))
+	require.NoError(t, err)
+
+	require.Len(t, result.Items, 1)
+	item := result.Items[0]
+	assert.Equal(t, 2, item.Day)
+	assert.Equal(t, models.WeekNumerator, item.Week)
+	assert.Equal(t, models.Teacher{LastName: "Сидоров", FirstName: "С.", MiddleName: "С."}, item.Teachers[0])
+	// Пустой поток заполняется ключом содержания, чтобы разные занятия слота не слились
+	assert.True(t, strings.HasPrefix(item.Stream, "upload-"))
+
+	columns := make([]string, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		assert.Equal(t, 3, e.Row)
+		columns = append(columns, e.Column)
+	}
+	assert.Equal(t, []string{"time"}, columns, "number errors stop the row before validation")
+}
+
+func TestParseCSVValidation(t *testing.T) {
+	data := "day;time;week;startTime;endTime;disciplineFullName;groups\n" +
+		"7;1;odd;10:05;08:30;;\n"
+
+	result, err := Parse(FormatCSV, strings.NewReader(data))
+	require.NoError(t, err)
+	assert.Empty(t, result.Items)
+
+	columns := make([]string, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		assert.Equal(t, 2, e.Row)
+		columns = append(columns, e.Column)
+	}
+	assert.Equal(t, []string{"day", "week", "endTime", "disciplineFullName", "groups"}, columns)
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	_, err := Parse(FormatCSV, strings.NewReader("day,time\n1,1\n"))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+}
+
+func TestParseXLSXExportRoundTrip(t *testing.T) {
+	seminar := lecture("ИУ7-12Б", models.WeekNumerator)
+	seminar.Time, seminar.StartTime, seminar.EndTime = 2, "10:15", "11:50"
+	seminar.Disciplines = []models.Discipline{{FullName: "Физика", ActType: "seminar"}}
+	seminar.Teachers = nil
+
+	sheets := []export.Sheet{{Name: "ИУ7, 1 курс", Schedules: []export.Schedule{
+		{Title: "ИУ7-11Б", Items: []models.ScheduleItem{lecture("ИУ7-11Б", models.WeekAll)}},
+		{Title: "ИУ7-12Б", Items: []models.ScheduleItem{lecture("ИУ7-12Б", models.WeekAll), seminar}},
+	}}}
+	var buf bytes.Buffer
+	require.NoError(t, export.WriteXLSX(&buf, sheets, timetable.DefaultBells))
+
+	result, err := Parse(FormatXLSX, &buf)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	require.Len(t, result.Items, 2)
+
+	// Объединенная ячейка двух групп - одно потоковое занятие каждую неделю
+	stream := result.Items[0]
+	assert.Equal(t, models.WeekAll, stream.Week)
+	assert.Equal(t, []models.Group{{Name: "ИУ7-11Б"}, {Name: "ИУ7-12Б"}}, stream.Groups)
+	assert.Equal(t, models.Discipline{FullName: "Математический анализ", ActType: "lecture"}, stream.DisciplineRaw)
+	assert.Equal(t, models.Teacher{LastName: "Иванов", FirstName: "И.", MiddleName: "И."}, stream.Teachers[0])
+	assert.Equal(t, []models.Audience{{Name: "501ю"}}, stream.Audiences)
+	assert.Equal(t, "08:30", stream.StartTime)
+
+	item := result.Items[1]
+	assert.Equal(t, models.WeekNumerator, item.Week)
+	assert.Equal(t, 2, item.Time)
+	assert.Equal(t, []models.Group{{Name: "ИУ7-12Б"}}, item.Groups)
+	assert.Empty(t, item.Teachers)
+}
+
+func TestParseJSONFillsGroupFromSchedule(t *testing.T) {
+	data := `{"data": {"type": "group", "uuid": "g-1", "title": "ДО-11", "schedule": [
+		{"day": 1, "time": 1, "week": "all", "startTime": "08:30", "endTime": "10:05", "stream": "s",
+		 "discipline": {"fullName": "История", "actType": "lecture"}, "source": "lks"},
+		{"day": 1, "time": 2, "week": "all", "startTime": "10:15", "endTime": "09:00",
+		 "discipline": {"fullName": "История"}}
+	]}}`
+
+	result, err := Parse(FormatJSON, strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, []models.Group{{UUID: "g-1", Name: "ДО-11"}}, result.Items[0].Groups)
+	assert.Empty(t, result.Items[0].Source)
+
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, RowError{Row: 2, Column: "endTime", Message: "end time 09:00 is not after start time 10:15"}, result.Errors[0])
+}
+
+func TestStableUUID(t *testing.T) {
+	id := StableUUID("group", "ДО-11")
+	assert.Equal(t, id, StableUUID("group", " до-11 "))
+	assert.NotEqual(t, id, StableUUID("teacher", "ДО-11"))
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+}
+
+func TestFormatFromName(t *testing.T) {
+	assert.Equal(t, FormatCSV, FormatFromName("schedule.CSV"))
+	assert.Equal(t, FormatXLSX, FormatFromName("grid.xlsx"))
+	assert.Equal(t, FormatJSON, FormatFromName("group.json"))
+	assert.Empty(t, FormatFromName("schedule.pdf"))
+}
@@ -0,0 +1,33 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+)
+
+// ParseJSON разбирает расписание в формате ответа lks (models.Schedule). Если у занятия расписания группы
+// не указаны группы, занятие относится к этой группе
+func ParseJSON(r io.Reader) (Result, error) {
+	var schedule models.Schedule
+	if err := json.NewDecoder(r).Decode(&schedule); err != nil {
+		return Result{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	var result Result
+	for i, item := range schedule.Data.Schedule {
+		if len(item.Groups) == 0 && schedule.Data.Type == "group" && schedule.Data.UUID != "" {
+			item.Groups = []models.Group{{UUID: schedule.Data.UUID, Name: schedule.Data.Title}}
+		}
+		// Источник задает загрузка, а не содержимое файла
+		item.Source = ""
+		if errs := validateItem(i+1, "", item); len(errs) > 0 {
+			result.Errors = append(result.Errors, errs...)
+			continue
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
@@ -0,0 +1,297 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	slotHeaderPattern = regexp.MustCompile(`^(\d+)\s*пара\s*(\d{1,2}:\d{2})\s*[–—-]\s*(\d{1,2}:\d{2})$`)
+	disciplinePattern = regexp.MustCompile(`^(.*\S)\s*\(([^()]+)\)$`)
+)
+
+// xlsxParities - подписи строк числителя и знаменателя в столбце «Неделя»
+var xlsxParities = map[string]string{
+	"чс":          models.WeekNumerator,
+	"ч":           models.WeekNumerator,
+	"числитель":   models.WeekNumerator,
+	"зн":          models.WeekDenominator,
+	"з":           models.WeekDenominator,
+	"знаменатель": models.WeekDenominator,
+}
+
+// gridSlot - пара из заголовка столбца
+type gridSlot struct {
+	number             int
+	startTime, endTime string
+}
+
+// gridLesson - одинаковые ячейки одного слота: занятие и недели, в которые оно идет у каждой группы
+type gridLesson struct {
+	row    int
+	item   models.ScheduleItem
+	groups []string
+	weeks  map[string]map[string]bool
+}
+
+// ParseXLSX разбирает сетку в раскладке выгрузки /export/schedule.xlsx: столбцы «День», «Группа», «Неделя»
+// и по столбцу на пару с заголовком «N пара» и временем. В ячейке занятия первая строка - дисциплина
+// с типом в скобках, затем преподаватели и аудитории через запятую, занятия ячейки разделяются пустой строкой.
+// Объединенные ячейки относятся ко всем строкам объединения: занятие на обеих строках группы идет каждую неделю,
+// а одинаковые ячейки нескольких групп становятся одним потоковым занятием
+func ParseXLSX(r io.Reader) (Result, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	defer f.Close()
+
+	var result Result
+	for _, sheet := range f.GetSheetList() {
+		rows, err := xlsxRows(f, sheet)
+		if err != nil {
+			return Result{}, fmt.Errorf("%w: sheet %q: %v", ErrInvalidFile, sheet, err)
+		}
+		items, errs := parseGrid(sheet, rows)
+		result.Items = append(result.Items, items...)
+		result.Errors = append(result.Errors, errs...)
+	}
+	return result, nil
+}
+
+// xlsxRows возвращает значения листа, в которых значение объединенной ячейки повторено во всем диапазоне
+func xlsxRows(f *excelize.File, sheet string) ([][]string, error) {
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := f.GetMergeCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	for _, cell := range merged {
+		col1, row1, err := excelize.CellNameToCoordinates(cell.GetStartAxis())
+		if err != nil {
+			return nil, err
+		}
+		col2, row2, err := excelize.CellNameToCoordinates(cell.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+		for len(rows) < row2 {
+			rows = append(rows, nil)
+		}
+		for row := row1; row <= row2; row++ {
+			for len(rows[row-1]) < col2 {
+				rows[row-1] = append(rows[row-1], "")
+			}
+			for col := col1; col <= col2; col++ {
+				rows[row-1][col-1] = cell.GetCellValue()
+			}
+		}
+	}
+	return rows, nil
+}
+
+func parseGrid(sheet string, rows [][]string) ([]models.ScheduleItem, []RowError) {
+	var errs []RowError
+	fail := func(row, col int, format string, args ...any) {
+		column := ""
+		if col > 0 {
+			column, _ = excelize.ColumnNumberToName(col)
+		}
+		errs = append(errs, RowError{Row: row, Sheet: sheet, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+	cell := func(row []string, col int) string {
+		if col <= len(row) {
+			return strings.TrimSpace(row[col-1])
+		}
+		return ""
+	}
+
+	headerRow := -1
+	for i, row := range rows {
+		if strings.EqualFold(cell(row, 1), "День") {
+			headerRow = i
+			break
+		}
+	}
+	if headerRow < 0 {
+		fail(0, 0, "header row starting with «День» not found")
+		return nil, errs
+	}
+	if label := cell(rows[headerRow], 2); !strings.EqualFold(label, "Группа") {
+		fail(headerRow+1, 2, "only group grids can be imported, got column %q", label)
+		return nil, errs
+	}
+
+	// Номера пар и время по заголовкам столбцов
+	slots := make(map[int]gridSlot)
+	for col := 4; col <= len(rows[headerRow]); col++ {
+		header := strings.Join(strings.Fields(cell(rows[headerRow], col)), " ")
+		if header == "" {
+			continue
+		}
+		match := slotHeaderPattern.FindStringSubmatch(header)
+		if match == nil {
+			fail(headerRow+1, col, "invalid slot header %q, expected «N пара HH:MM–HH:MM»", header)
+			continue
+		}
+		number, _ := strconv.Atoi(match[1])
+		slots[col] = gridSlot{number: number, startTime: normalizeClock(match[2]), endTime: normalizeClock(match[3])}
+	}
+
+	lessons := make(map[string]*gridLesson)
+	var order []string
+	for i := headerRow + 1; i < len(rows); i++ {
+		row, line := rows[i], i+1
+
+		hasLessons := false
+		for col := range slots {
+			if cell(row, col) != "" {
+				hasLessons = true
+				break
+			}
+		}
+		if !hasLessons {
+			continue
+		}
+
+		day := dayNumber(cell(row, 1))
+		if day == 0 {
+			fail(line, 1, "unknown day %q", cell(row, 1))
+			continue
+		}
+		group := cell(row, 2)
+		if group == "" {
+			fail(line, 2, "group name is required")
+			continue
+		}
+		parity := ""
+		if label := strings.ToLower(cell(row, 3)); label != "" {
+			var ok bool
+			if parity, ok = xlsxParities[label]; !ok {
+				fail(line, 3, "unknown week %q, expected чс or зн", cell(row, 3))
+				continue
+			}
+		}
+
+		for col := 4; col <= len(row); col++ {
+			slot, ok := slots[col]
+			text := strings.ReplaceAll(cell(row, col), "\r\n", "\n")
+			if !ok || text == "" {
+				continue
+			}
+			for _, lessonText := range strings.Split(text, "\n\n") {
+				key := fmt.Sprintf("%d|%d|%s", day, slot.number, strings.TrimSpace(lessonText))
+				lesson, seen := lessons[key]
+				if !seen {
+					item, err := parseLessonText(lessonText)
+					if err != nil {
+						fail(line, col, "%v", err)
+						continue
+					}
+					item.Day, item.Time, item.StartTime, item.EndTime = day, slot.number, slot.startTime, slot.endTime
+					lesson = &gridLesson{row: line, item: item, weeks: make(map[string]map[string]bool)}
+					lessons[key] = lesson
+					order = append(order, key)
+				}
+				if lesson.weeks[group] == nil {
+					lesson.weeks[group] = make(map[string]bool)
+					lesson.groups = append(lesson.groups, group)
+				}
+				if parity == "" {
+					lesson.weeks[group][models.WeekNumerator] = true
+					lesson.weeks[group][models.WeekDenominator] = true
+				} else {
+					lesson.weeks[group][parity] = true
+				}
+			}
+		}
+	}
+
+	var items []models.ScheduleItem
+	for _, key := range order {
+		lesson := lessons[key]
+		for _, week := range []string{models.WeekAll, models.WeekNumerator, models.WeekDenominator} {
+			item := lesson.item
+			item.Week = week
+			for _, group := range lesson.groups {
+				weeks := lesson.weeks[group]
+				both := weeks[models.WeekNumerator] && weeks[models.WeekDenominator]
+				if (week == models.WeekAll && both) || (!both && weeks[week]) {
+					item.Groups = append(item.Groups, models.Group{Name: group})
+				}
+			}
+			if len(item.Groups) == 0 {
+				continue
+			}
+			if itemErrs := validateItem(lesson.row, sheet, item); len(itemErrs) > 0 {
+				errs = append(errs, itemErrs...)
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	return items, errs
+}
+
+// parseLessonText разбирает текст занятия в том виде, в котором его печатает экспорт:
+// «Дисциплина (тип)», строка преподавателей и строка аудиторий. Строка с цифрами считается аудиториями
+func parseLessonText(text string) (models.ScheduleItem, error) {
+	lines := splitList(text, "\n")
+	if len(lines) == 0 {
+		return models.ScheduleItem{}, fmt.Errorf("empty lesson")
+	}
+
+	var item models.ScheduleItem
+	if match := disciplinePattern.FindStringSubmatch(lines[0]); match != nil {
+		item.DisciplineRaw = models.Discipline{FullName: match[1], ActType: strings.TrimSpace(match[2])}
+	} else {
+		item.DisciplineRaw = models.Discipline{FullName: lines[0]}
+	}
+
+	for _, line := range lines[1:] {
+		if strings.IndexFunc(line, unicode.IsDigit) >= 0 {
+			if len(item.Audiences) > 0 {
+				return item, fmt.Errorf("unexpected line %q in lesson %q", line, lines[0])
+			}
+			for _, name := range splitList(line, ",") {
+				item.Audiences = append(item.Audiences, models.Audience{Name: name})
+			}
+			continue
+		}
+		if len(item.Teachers) > 0 || len(item.Audiences) > 0 {
+			return item, fmt.Errorf("unexpected line %q in lesson %q", line, lines[0])
+		}
+		for _, name := range splitList(line, ",") {
+			item.Teachers = append(item.Teachers, parseTeacher(name))
+		}
+	}
+	return item, nil
+}
+
+func dayNumber(name string) int {
+	for day, dayName := range timetable.DayNames {
+		if day > 0 && strings.EqualFold(name, dayName) {
+			return day
+		}
+	}
+	return 0
+}
+
+// normalizeClock дополняет час ведущим нулем: 8:30 -> 08:30
+func normalizeClock(clock string) string {
+	if len(clock) == 4 {
+		return "0" + clock
+	}
+	return clock
+}
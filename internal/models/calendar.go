@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Semester описывает учебный семестр. Даты хранятся в формате YYYY-MM-DD
+type Semester struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+	DeletedAt time.Time `json:"-" gorm:"index"`
+	Title     string    `json:"title"`
+	StartDate string    `json:"startDate"`
+	EndDate   string    `json:"endDate"`
+}
+
+// Holiday описывает праздничный день без занятий
+type Holiday struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+	DeletedAt time.Time `json:"-" gorm:"index"`
+	Title     string    `json:"title"`
+	Date      string    `json:"date" gorm:"uniqueIndex"`
+}
+
+// ExamSession описывает период экзаменационной сессии, в который обычных занятий нет
+type ExamSession struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+	DeletedAt time.Time `json:"-" gorm:"index"`
+	Title     string    `json:"title"`
+	StartDate string    `json:"startDate"`
+	EndDate   string    `json:"endDate"`
+}
+
+// DayTransfer описывает перенос: в дату Date занятия проходят по расписанию дня недели Weekday
+type DayTransfer struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+	DeletedAt time.Time `json:"-" gorm:"index"`
+	Title     string    `json:"title"`
+	Date      string    `json:"date" gorm:"uniqueIndex"`
+	Weekday   int       `json:"weekday"`          // 1 - понедельник, 6 - суббота
+	Parity    string    `json:"parity,omitempty"` // ch или zn, пусто - четность недели самой даты
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// CalendarFeed - подписка на расписание в формате iCalendar. Календарные приложения
+// периодически запрашивают ее по секретному Token
+type CalendarFeed struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"-"`
+	DeletedAt time.Time `json:"-" gorm:"index"`
+	Token     string    `json:"token" gorm:"uniqueIndex"`
+	Kind      string    `json:"kind"` // group, teacher, audience или timetable
+	Ref       string    `json:"ref"`  // UUID группы, преподавателя, аудитории или ID личного расписания
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// GlobalDataScope - область версии данных, меняющаяся при любом изменении расписания
+const GlobalDataScope = "global"
+
+// DataVersion хранит версию данных для всего расписания (Scope = global) или для группы (Scope = UUID группы).
+// Версия увеличивается при каждом изменении данных во время загрузки
+type DataVersion struct {
+	Scope     string    `json:"scope" gorm:"primaryKey"`
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
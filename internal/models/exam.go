@@ -14,6 +14,7 @@ type Exam struct {
 	FirstName   string       `json:"firstName"`
 	MiddleName  string       `json:"middleName"`
 	Disciplines []Discipline `json:"disciplines" gorm:"many2many:exam_disciplines;"`
+	Groups      []Group      `json:"groups,omitempty" gorm:"many2many:exam_groups;"`
 	// Временное поле для парсинга JSON
 	DisciplineRaw string `json:"discipline" gorm:"-"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PersonalTimetable - личное расписание, собранное из расписаний нескольких групп.
+// Доступно всем по PublicID, изменить его можно только с EditToken
+type PersonalTimetable struct {
+	ID        uint              `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time         `json:"-"`
+	UpdatedAt time.Time         `json:"-"`
+	DeletedAt time.Time         `json:"-" gorm:"index"`
+	PublicID  string            `json:"id" gorm:"uniqueIndex"`
+	EditToken string            `json:"-"`
+	Title     string            `json:"title"`
+	Sources   []TimetableSource `json:"sources" gorm:"serializer:json"`
+}
+
+// TimetableSource - группа, занятия которой входят в личное расписание, с фильтрами.
+// Дисциплины сравниваются по полному названию или аббревиатуре, типы занятий - по actType.
+// Пустой список include означает все занятия
+type TimetableSource struct {
+	Group              string   `json:"group"` // UUID группы
+	IncludeDisciplines []string `json:"includeDisciplines,omitempty"`
+	ExcludeDisciplines []string `json:"excludeDisciplines,omitempty"`
+	IncludeActTypes    []string `json:"includeActTypes,omitempty"`
+	ExcludeActTypes    []string `json:"excludeActTypes,omitempty"`
+}
@@ -5,6 +5,20 @@ import (
 	"time"
 )
 
+// Значения поля ScheduleItem.Week
+const (
+	WeekAll         = "all" // Каждую неделю
+	WeekNumerator   = "ch"  // Числитель
+	WeekDenominator = "zn"  // Знаменатель
+)
+
+// SourceLKS - значение ScheduleItem.Source для занятий, загруженных из lks.
+// Занятия из загруженных файлов помечаются SourceUploadPrefix и тегом загрузки
+const (
+	SourceLKS          = "lks"
+	SourceUploadPrefix = "upload:"
+)
+
 type Schedule struct {
 	Data struct {
 		Type     string         `json:"type"`
@@ -22,6 +36,7 @@ type ScheduleItem struct {
 	DeletedAt   time.Time    `json:"-" gorm:"index"`
 	Day         int          `json:"day"`
 	Time        int          `json:"time"`
+	SlotID      *uint        `json:"slotId" gorm:"index"` // Ссылка на TimeSlot, заполняется после загрузки данных
 	Week        string       `json:"week"`
 	Groups      []Group      `json:"groups" gorm:"many2many:schedule_item_groups;"`
 	Stream      string       `json:"stream"`
@@ -33,6 +48,7 @@ type ScheduleItem struct {
 	// Временное поле для парсинга JSON
 	DisciplineRaw Discipline `json:"discipline" gorm:"-"`
 	Permission    string     `json:"permission"`
+	Source        string     `json:"source" gorm:"index;default:'lks'"` // Откуда загружено занятие
 }
 
 // Кастомная сериализация для ScheduleItem (пока что только таким методом смог убрать пустую дисциплину с id 0 в ответе)
@@ -45,6 +61,7 @@ func (s ScheduleItem) MarshalJSON() ([]byte, error) {
 		// DeletedAt   time.Time    `json:"-"`
 		Day         int          `json:"day"`
 		Time        int          `json:"time"`
+		SlotID      *uint        `json:"slotId"`
 		Week        string       `json:"week"`
 		Groups      []Group      `json:"groups"`
 		Stream      string       `json:"stream"`
@@ -54,6 +71,7 @@ func (s ScheduleItem) MarshalJSON() ([]byte, error) {
 		StartTime   string       `json:"startTime"`
 		Disciplines []Discipline `json:"disciplines"`
 		Permission  string       `json:"permission"`
+		Source      string       `json:"source"`
 	}{
 		ID:          s.ID,
 		// CreatedAt:   s.CreatedAt,
@@ -61,6 +79,7 @@ func (s ScheduleItem) MarshalJSON() ([]byte, error) {
 		// DeletedAt:   s.DeletedAt,
 		Day:         s.Day,
 		Time:        s.Time,
+		SlotID:      s.SlotID,
 		Week:        s.Week,
 		Groups:      s.Groups,
 		Stream:      s.Stream,
@@ -70,6 +89,7 @@ func (s ScheduleItem) MarshalJSON() ([]byte, error) {
 		StartTime:   s.StartTime,
 		Disciplines: s.Disciplines,
 		Permission:  s.Permission,
+		Source:      s.Source,
 	})
 }
 
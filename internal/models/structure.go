@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Structure struct {
 	Data struct {
 		Abbr     string  `json:"abbr"`
@@ -19,3 +21,20 @@ type Child struct {
 	ParentUUID *string `json:"parentUuid,omitempty"`
 	Children   []Child `json:"children"`
 }
+
+// StructureNode - узел дерева структуры университета (факультет, кафедра, курс, группа),
+// сохраненный при загрузке данных
+type StructureNode struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"-"`
+	UpdatedAt  time.Time `json:"-"`
+	DeletedAt  time.Time `json:"-" gorm:"index"`
+	UUID       string    `json:"uuid" gorm:"uniqueIndex"`
+	ParentUUID *string   `json:"parentUuid" gorm:"index"`
+	Position   int       `json:"position"` // Порядок среди соседних узлов
+	Abbr       string    `json:"abbr"`
+	Name       string    `json:"name"`
+	NodeType   *string   `json:"nodeType"`
+	Course     *int      `json:"course"`
+	Semester   *int      `json:"semester"`
+}
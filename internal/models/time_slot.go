@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// TimeSlot - пара из расписания звонков. Выводится из занятий при загрузке данных
+type TimeSlot struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+	DeletedAt time.Time `json:"-" gorm:"index"`
+	Number    int       `json:"number" gorm:"uniqueIndex"` // Совпадает с ScheduleItem.Time
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
+}
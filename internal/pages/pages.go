@@ -0,0 +1,165 @@
+// Package pages отрисовывает расписания в HTML для киосков, ссылок в письмах и браузеров без приложения.
+// Шаблоны и стили встроены в исполняемый файл
+package pages
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/export"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+)
+
+//go:embed templates
+var files embed.FS
+
+var templates = template.Must(template.ParseFS(files, "templates/*.tmpl"))
+
+// weekLabels - подписи занятий не каждую неделю
+var weekLabels = map[string]string{
+	models.WeekNumerator:   "чс",
+	models.WeekDenominator: "зн",
+}
+
+// Link - ссылка на расписание в другом формате
+type Link struct {
+	Title string
+	URL   string
+}
+
+// Page - страница расписания группы, преподавателя или аудитории.
+// Week - неделя, которую нужно подсветить, nil, если календарь семестра не настроен
+type Page struct {
+	Title      string
+	Kind       string // Подпись вида расписания: «Группа», «Преподаватель», «Аудитория»
+	Grid       timetable.Grid
+	Week       *calendar.WeekInfo
+	ShowGroups bool
+	Links      []Link
+	Refresh    int // Интервал автообновления страницы в секундах для киосков, 0 - без обновления
+}
+
+type pageView struct {
+	Page
+	WeekTitle string
+	Days      []dayView
+	Empty     bool
+}
+
+type dayView struct {
+	Name  string
+	Today bool
+	Slots []slotView
+}
+
+type slotView struct {
+	Number    int
+	StartTime string
+	EndTime   string
+	Lessons   []lessonView
+}
+
+type lessonView struct {
+	WeekLabel  string
+	Current    bool // Занятие идет на подсвеченной неделе
+	Other      bool // Занятие идет на другой неделе
+	Discipline string
+	ActType    string
+	Teachers   string
+	Audiences  string
+	Groups     string
+}
+
+// Render записывает HTML-страницу расписания
+func Render(w io.Writer, page Page) error {
+	return templates.ExecuteTemplate(w, "schedule.html.tmpl", newPageView(page))
+}
+
+func newPageView(page Page) pageView {
+	view := pageView{Page: page, Empty: true}
+
+	parity := ""
+	today := 0
+	if week := page.Week; week != nil && week.InSemester {
+		parity = week.Parity
+		today = week.ScheduleDay
+		view.WeekTitle = weekTitle(*week)
+	}
+
+	for _, day := range page.Grid.Days {
+		dv := dayView{Name: day.Name, Today: day.Day == today}
+		for _, cell := range day.Slots {
+			sv := slotView{Number: cell.Slot, StartTime: cell.StartTime, EndTime: cell.EndTime}
+			for _, lessons := range [][]models.ScheduleItem{cell.All, cell.Numerator, cell.Denominator} {
+				for _, item := range lessons {
+					sv.Lessons = append(sv.Lessons, newLessonView(item, parity, page.ShowGroups))
+				}
+			}
+			if len(sv.Lessons) > 0 {
+				dv.Slots = append(dv.Slots, sv)
+			}
+		}
+		if len(dv.Slots) > 0 {
+			view.Days = append(view.Days, dv)
+			view.Empty = false
+		}
+	}
+	return view
+}
+
+func newLessonView(item models.ScheduleItem, parity string, showGroups bool) lessonView {
+	view := lessonView{WeekLabel: weekLabels[item.Week]}
+	if parity != "" && view.WeekLabel != "" {
+		view.Current = item.Week == parity
+		view.Other = !view.Current
+	}
+
+	var names, types []string
+	for _, d := range item.Disciplines {
+		name := d.FullName
+		if name == "" {
+			name = d.ShortName
+		}
+		names = append(names, name)
+		if d.ActType != "" {
+			types = append(types, d.ActType)
+		}
+	}
+	view.Discipline = strings.Join(names, ", ")
+	view.ActType = strings.Join(types, ", ")
+
+	teachers := make([]string, len(item.Teachers))
+	for i, t := range item.Teachers {
+		teachers[i] = export.TeacherShortName(t)
+	}
+	view.Teachers = strings.Join(teachers, ", ")
+
+	audiences := make([]string, len(item.Audiences))
+	for i, a := range item.Audiences {
+		audiences[i] = a.Name
+	}
+	view.Audiences = strings.Join(audiences, ", ")
+
+	if showGroups {
+		groups := make([]string, len(item.Groups))
+		for i, g := range item.Groups {
+			groups[i] = g.Name
+		}
+		view.Groups = strings.Join(groups, ", ")
+	}
+	return view
+}
+
+// weekTitle описывает подсвеченную неделю: «12 неделя, числитель»
+func weekTitle(week calendar.WeekInfo) string {
+	parity := "знаменатель"
+	if week.Parity == models.WeekNumerator {
+		parity = "числитель"
+	}
+	return fmt.Sprintf("%d неделя, %s", week.WeekNumber, parity)
+}
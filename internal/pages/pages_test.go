@@ -0,0 +1,83 @@
+package pages
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/kosttiik/semesterly_backend/internal/timetable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lesson(id uint, day int, week, name string) models.ScheduleItem {
+	return models.ScheduleItem{
+		ID: id, Day: day, Time: 1, Week: week, StartTime: "08:30", EndTime: "10:05",
+		Disciplines: []models.Discipline{{FullName: name, ActType: "seminar"}},
+		Teachers:    []models.Teacher{{LastName: "Иванов", FirstName: "Иван", MiddleName: "Иванович"}},
+		Audiences:   []models.Audience{{Name: "501ю"}},
+		Groups:      []models.Group{{Name: "ИУ7-11Б"}},
+	}
+}
+
+func TestNewPageViewHighlightsParity(t *testing.T) {
+	grid := timetable.Build([]models.ScheduleItem{
+		lesson(1, 2, models.WeekNumerator, "Физика"),
+		lesson(2, 2, models.WeekDenominator, "Химия"),
+		lesson(3, 2, models.WeekAll, "История"),
+	}, timetable.DefaultBells)
+
+	view := newPageView(Page{Title: "ИУ7-11Б", Grid: grid, Week: &calendar.WeekInfo{
+		InSemester: true, WeekNumber: 3, Parity: models.WeekNumerator, ScheduleDay: 2,
+	}})
+
+	assert.Equal(t, "3 неделя, числитель", view.WeekTitle)
+	require.Len(t, view.Days, 1, "days without lessons are skipped")
+	day := view.Days[0]
+	assert.Equal(t, "Вторник", day.Name)
+	assert.True(t, day.Today)
+
+	require.Len(t, day.Slots, 1)
+	lessons := day.Slots[0].Lessons
+	require.Len(t, lessons, 3)
+	// Сначала занятия каждую неделю, затем числитель и знаменатель
+	assert.Equal(t, "История", lessons[0].Discipline)
+	assert.False(t, lessons[0].Current || lessons[0].Other)
+	assert.True(t, lessons[1].Current)
+	assert.Equal(t, "чс", lessons[1].WeekLabel)
+	assert.True(t, lessons[2].Other)
+	assert.Equal(t, "Иванов И. И.", lessons[2].Teachers)
+	assert.Empty(t, lessons[2].Groups)
+}
+
+func TestRender(t *testing.T) {
+	item := lesson(1, 1, models.WeekAll, "<script>")
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, Page{
+		Title:      "Иванов Иван Иванович",
+		Kind:       "Преподаватель",
+		Grid:       timetable.Build([]models.ScheduleItem{item}, timetable.DefaultBells),
+		ShowGroups: true,
+		Links:      []Link{{Title: "PDF", URL: "/api/v1/teachers/t-1/schedule.pdf"}},
+		Refresh:    300,
+	}))
+
+	html := buf.String()
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.Contains(t, html, `<meta http-equiv="refresh" content="300">`)
+	assert.Contains(t, html, "@media print")
+	assert.Contains(t, html, "Понедельник")
+	assert.Contains(t, html, "ИУ7-11Б")
+	assert.Contains(t, html, `href="/api/v1/teachers/t-1/schedule.pdf"`)
+	assert.Contains(t, html, "&lt;script&gt;")
+	assert.NotContains(t, html, "<script>")
+	assert.NotContains(t, html, "Сейчас", "no week is highlighted without a calendar")
+}
+
+func TestRenderEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, Page{Title: "501ю", Kind: "Аудитория", Grid: timetable.Build(nil, timetable.DefaultBells)}))
+	assert.Contains(t, buf.String(), "Занятий нет")
+}
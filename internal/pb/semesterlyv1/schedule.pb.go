@@ -0,0 +1,1223 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: semesterly/v1/schedule.proto
+
+package semesterlyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Четность недели, на которой проходит занятие
+type Week int32
+
+const (
+	Week_WEEK_UNSPECIFIED Week = 0
+	Week_WEEK_ALL         Week = 1 // Каждую неделю
+	Week_WEEK_NUMERATOR   Week = 2 // Числитель
+	Week_WEEK_DENOMINATOR Week = 3 // Знаменатель
+)
+
+// Enum value maps for Week.
+var (
+	Week_name = map[int32]string{
+		0: "WEEK_UNSPECIFIED",
+		1: "WEEK_ALL",
+		2: "WEEK_NUMERATOR",
+		3: "WEEK_DENOMINATOR",
+	}
+	Week_value = map[string]int32{
+		"WEEK_UNSPECIFIED": 0,
+		"WEEK_ALL":         1,
+		"WEEK_NUMERATOR":   2,
+		"WEEK_DENOMINATOR": 3,
+	}
+)
+
+func (x Week) Enum() *Week {
+	p := new(Week)
+	*p = x
+	return p
+}
+
+func (x Week) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Week) Descriptor() protoreflect.EnumDescriptor {
+	return file_semesterly_v1_schedule_proto_enumTypes[0].Descriptor()
+}
+
+func (Week) Type() protoreflect.EnumType {
+	return &file_semesterly_v1_schedule_proto_enumTypes[0]
+}
+
+func (x Week) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Week.Descriptor instead.
+func (Week) EnumDescriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+type Group struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	DepartmentUid string                 `protobuf:"bytes,4,opt,name=department_uid,json=departmentUid,proto3" json:"department_uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Group) Reset() {
+	*x = Group{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Group) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Group) ProtoMessage() {}
+
+func (x *Group) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Group.ProtoReflect.Descriptor instead.
+func (*Group) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Group) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Group) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Group) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Group) GetDepartmentUid() string {
+	if x != nil {
+		return x.DepartmentUid
+	}
+	return ""
+}
+
+type Teacher struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	FirstName     string                 `protobuf:"bytes,4,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	MiddleName    string                 `protobuf:"bytes,5,opt,name=middle_name,json=middleName,proto3" json:"middle_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Teacher) Reset() {
+	*x = Teacher{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Teacher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Teacher) ProtoMessage() {}
+
+func (x *Teacher) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Teacher.ProtoReflect.Descriptor instead.
+func (*Teacher) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Teacher) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Teacher) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Teacher) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Teacher) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Teacher) GetMiddleName() string {
+	if x != nil {
+		return x.MiddleName
+	}
+	return ""
+}
+
+type Audience struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Building      string                 `protobuf:"bytes,4,opt,name=building,proto3" json:"building,omitempty"`
+	DepartmentUid *string                `protobuf:"bytes,5,opt,name=department_uid,json=departmentUid,proto3,oneof" json:"department_uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Audience) Reset() {
+	*x = Audience{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Audience) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Audience) ProtoMessage() {}
+
+func (x *Audience) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Audience.ProtoReflect.Descriptor instead.
+func (*Audience) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Audience) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Audience) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Audience) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Audience) GetBuilding() string {
+	if x != nil {
+		return x.Building
+	}
+	return ""
+}
+
+func (x *Audience) GetDepartmentUid() string {
+	if x != nil && x.DepartmentUid != nil {
+		return *x.DepartmentUid
+	}
+	return ""
+}
+
+type Discipline struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Abbr          string                 `protobuf:"bytes,2,opt,name=abbr,proto3" json:"abbr,omitempty"`
+	ActType       string                 `protobuf:"bytes,3,opt,name=act_type,json=actType,proto3" json:"act_type,omitempty"`
+	FullName      string                 `protobuf:"bytes,4,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	ShortName     string                 `protobuf:"bytes,5,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discipline) Reset() {
+	*x = Discipline{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discipline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discipline) ProtoMessage() {}
+
+func (x *Discipline) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discipline.ProtoReflect.Descriptor instead.
+func (*Discipline) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Discipline) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Discipline) GetAbbr() string {
+	if x != nil {
+		return x.Abbr
+	}
+	return ""
+}
+
+func (x *Discipline) GetActType() string {
+	if x != nil {
+		return x.ActType
+	}
+	return ""
+}
+
+func (x *Discipline) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *Discipline) GetShortName() string {
+	if x != nil {
+		return x.ShortName
+	}
+	return ""
+}
+
+type ScheduleItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Day           int32                  `protobuf:"varint,2,opt,name=day,proto3" json:"day,omitempty"`   // 1 - понедельник, 6 - суббота
+	Time          int32                  `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"` // Номер пары
+	Week          Week                   `protobuf:"varint,4,opt,name=week,proto3,enum=semesterly.v1.Week" json:"week,omitempty"`
+	Stream        string                 `protobuf:"bytes,5,opt,name=stream,proto3" json:"stream,omitempty"`
+	StartTime     string                 `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       string                 `protobuf:"bytes,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Permission    string                 `protobuf:"bytes,8,opt,name=permission,proto3" json:"permission,omitempty"`
+	Groups        []*Group               `protobuf:"bytes,9,rep,name=groups,proto3" json:"groups,omitempty"`
+	Teachers      []*Teacher             `protobuf:"bytes,10,rep,name=teachers,proto3" json:"teachers,omitempty"`
+	Audiences     []*Audience            `protobuf:"bytes,11,rep,name=audiences,proto3" json:"audiences,omitempty"`
+	Disciplines   []*Discipline          `protobuf:"bytes,12,rep,name=disciplines,proto3" json:"disciplines,omitempty"`
+	SlotId        *uint64                `protobuf:"varint,13,opt,name=slot_id,json=slotId,proto3,oneof" json:"slot_id,omitempty"` // Ссылка на пару из расписания звонков
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleItem) Reset() {
+	*x = ScheduleItem{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleItem) ProtoMessage() {}
+
+func (x *ScheduleItem) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleItem.ProtoReflect.Descriptor instead.
+func (*ScheduleItem) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ScheduleItem) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScheduleItem) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *ScheduleItem) GetTime() int32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *ScheduleItem) GetWeek() Week {
+	if x != nil {
+		return x.Week
+	}
+	return Week_WEEK_UNSPECIFIED
+}
+
+func (x *ScheduleItem) GetStream() string {
+	if x != nil {
+		return x.Stream
+	}
+	return ""
+}
+
+func (x *ScheduleItem) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *ScheduleItem) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *ScheduleItem) GetPermission() string {
+	if x != nil {
+		return x.Permission
+	}
+	return ""
+}
+
+func (x *ScheduleItem) GetGroups() []*Group {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *ScheduleItem) GetTeachers() []*Teacher {
+	if x != nil {
+		return x.Teachers
+	}
+	return nil
+}
+
+func (x *ScheduleItem) GetAudiences() []*Audience {
+	if x != nil {
+		return x.Audiences
+	}
+	return nil
+}
+
+func (x *ScheduleItem) GetDisciplines() []*Discipline {
+	if x != nil {
+		return x.Disciplines
+	}
+	return nil
+}
+
+func (x *ScheduleItem) GetSlotId() uint64 {
+	if x != nil && x.SlotId != nil {
+		return *x.SlotId
+	}
+	return 0
+}
+
+type ListGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupsRequest) Reset() {
+	*x = ListGroupsRequest{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupsRequest) ProtoMessage() {}
+
+func (x *ListGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{5}
+}
+
+type ListGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*Group               `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupsResponse) Reset() {
+	*x = ListGroupsResponse{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupsResponse) ProtoMessage() {}
+
+func (x *ListGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListGroupsResponse) GetGroups() []*Group {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type GetGroupScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupScheduleRequest) Reset() {
+	*x = GetGroupScheduleRequest{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupScheduleRequest) ProtoMessage() {}
+
+func (x *GetGroupScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetGroupScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetGroupScheduleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type GetGroupScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ScheduleItem        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupScheduleResponse) Reset() {
+	*x = GetGroupScheduleResponse{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupScheduleResponse) ProtoMessage() {}
+
+func (x *GetGroupScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetGroupScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetGroupScheduleResponse) GetItems() []*ScheduleItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type GetTeacherScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeacherScheduleRequest) Reset() {
+	*x = GetTeacherScheduleRequest{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeacherScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeacherScheduleRequest) ProtoMessage() {}
+
+func (x *GetTeacherScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeacherScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetTeacherScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTeacherScheduleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type GetTeacherScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ScheduleItem        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeacherScheduleResponse) Reset() {
+	*x = GetTeacherScheduleResponse{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeacherScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeacherScheduleResponse) ProtoMessage() {}
+
+func (x *GetTeacherScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeacherScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetTeacherScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetTeacherScheduleResponse) GetItems() []*ScheduleItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type GetAudienceScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAudienceScheduleRequest) Reset() {
+	*x = GetAudienceScheduleRequest{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAudienceScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAudienceScheduleRequest) ProtoMessage() {}
+
+func (x *GetAudienceScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAudienceScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetAudienceScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetAudienceScheduleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type GetAudienceScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ScheduleItem        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAudienceScheduleResponse) Reset() {
+	*x = GetAudienceScheduleResponse{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAudienceScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAudienceScheduleResponse) ProtoMessage() {}
+
+func (x *GetAudienceScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAudienceScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetAudienceScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetAudienceScheduleResponse) GetItems() []*ScheduleItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type TriggerSyncRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerSyncRequest) Reset() {
+	*x = TriggerSyncRequest{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerSyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerSyncRequest) ProtoMessage() {}
+
+func (x *TriggerSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerSyncRequest.ProtoReflect.Descriptor instead.
+func (*TriggerSyncRequest) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{13}
+}
+
+type TriggerSyncResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerSyncResponse) Reset() {
+	*x = TriggerSyncResponse{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerSyncResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerSyncResponse) ProtoMessage() {}
+
+func (x *TriggerSyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerSyncResponse.ProtoReflect.Descriptor instead.
+func (*TriggerSyncResponse) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{14}
+}
+
+type StreamSyncProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSyncProgressRequest) Reset() {
+	*x = StreamSyncProgressRequest{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSyncProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSyncProgressRequest) ProtoMessage() {}
+
+func (x *StreamSyncProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSyncProgressRequest.ProtoReflect.Descriptor instead.
+func (*StreamSyncProgressRequest) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{15}
+}
+
+type StreamSyncProgressResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CurrentItem    int32                  `protobuf:"varint,1,opt,name=current_item,json=currentItem,proto3" json:"current_item,omitempty"`
+	TotalItems     int32                  `protobuf:"varint,2,opt,name=total_items,json=totalItems,proto3" json:"total_items,omitempty"`
+	CompletedItems int32                  `protobuf:"varint,3,opt,name=completed_items,json=completedItems,proto3" json:"completed_items,omitempty"`
+	Percentage     float64                `protobuf:"fixed64,4,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	Eta            string                 `protobuf:"bytes,5,opt,name=eta,proto3" json:"eta,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StreamSyncProgressResponse) Reset() {
+	*x = StreamSyncProgressResponse{}
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSyncProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSyncProgressResponse) ProtoMessage() {}
+
+func (x *StreamSyncProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_semesterly_v1_schedule_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSyncProgressResponse.ProtoReflect.Descriptor instead.
+func (*StreamSyncProgressResponse) Descriptor() ([]byte, []int) {
+	return file_semesterly_v1_schedule_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StreamSyncProgressResponse) GetCurrentItem() int32 {
+	if x != nil {
+		return x.CurrentItem
+	}
+	return 0
+}
+
+func (x *StreamSyncProgressResponse) GetTotalItems() int32 {
+	if x != nil {
+		return x.TotalItems
+	}
+	return 0
+}
+
+func (x *StreamSyncProgressResponse) GetCompletedItems() int32 {
+	if x != nil {
+		return x.CompletedItems
+	}
+	return 0
+}
+
+func (x *StreamSyncProgressResponse) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+func (x *StreamSyncProgressResponse) GetEta() string {
+	if x != nil {
+		return x.Eta
+	}
+	return ""
+}
+
+var File_semesterly_v1_schedule_proto protoreflect.FileDescriptor
+
+const file_semesterly_v1_schedule_proto_rawDesc = "" +
+	"\n" +
+	"\x1csemesterly/v1/schedule.proto\x12\rsemesterly.v1\"f\n" +
+	"\x05Group\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12%\n" +
+	"\x0edepartment_uid\x18\x04 \x01(\tR\rdepartmentUid\"\x8a\x01\n" +
+	"\aTeacher\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x04 \x01(\tR\tfirstName\x12\x1f\n" +
+	"\vmiddle_name\x18\x05 \x01(\tR\n" +
+	"middleName\"\x9d\x01\n" +
+	"\bAudience\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bbuilding\x18\x04 \x01(\tR\bbuilding\x12*\n" +
+	"\x0edepartment_uid\x18\x05 \x01(\tH\x00R\rdepartmentUid\x88\x01\x01B\x11\n" +
+	"\x0f_department_uid\"\x87\x01\n" +
+	"\n" +
+	"Discipline\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04abbr\x18\x02 \x01(\tR\x04abbr\x12\x19\n" +
+	"\bact_type\x18\x03 \x01(\tR\aactType\x12\x1b\n" +
+	"\tfull_name\x18\x04 \x01(\tR\bfullName\x12\x1d\n" +
+	"\n" +
+	"short_name\x18\x05 \x01(\tR\tshortName\"\xdf\x03\n" +
+	"\fScheduleItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03day\x18\x02 \x01(\x05R\x03day\x12\x12\n" +
+	"\x04time\x18\x03 \x01(\x05R\x04time\x12'\n" +
+	"\x04week\x18\x04 \x01(\x0e2\x13.semesterly.v1.WeekR\x04week\x12\x16\n" +
+	"\x06stream\x18\x05 \x01(\tR\x06stream\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\tR\tstartTime\x12\x19\n" +
+	"\bend_time\x18\a \x01(\tR\aendTime\x12\x1e\n" +
+	"\n" +
+	"permission\x18\b \x01(\tR\n" +
+	"permission\x12,\n" +
+	"\x06groups\x18\t \x03(\v2\x14.semesterly.v1.GroupR\x06groups\x122\n" +
+	"\bteachers\x18\n" +
+	" \x03(\v2\x16.semesterly.v1.TeacherR\bteachers\x125\n" +
+	"\taudiences\x18\v \x03(\v2\x17.semesterly.v1.AudienceR\taudiences\x12;\n" +
+	"\vdisciplines\x18\f \x03(\v2\x19.semesterly.v1.DisciplineR\vdisciplines\x12\x1c\n" +
+	"\aslot_id\x18\r \x01(\x04H\x00R\x06slotId\x88\x01\x01B\n" +
+	"\n" +
+	"\b_slot_id\"\x13\n" +
+	"\x11ListGroupsRequest\"B\n" +
+	"\x12ListGroupsResponse\x12,\n" +
+	"\x06groups\x18\x01 \x03(\v2\x14.semesterly.v1.GroupR\x06groups\"-\n" +
+	"\x17GetGroupScheduleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"M\n" +
+	"\x18GetGroupScheduleResponse\x121\n" +
+	"\x05items\x18\x01 \x03(\v2\x1b.semesterly.v1.ScheduleItemR\x05items\"/\n" +
+	"\x19GetTeacherScheduleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"O\n" +
+	"\x1aGetTeacherScheduleResponse\x121\n" +
+	"\x05items\x18\x01 \x03(\v2\x1b.semesterly.v1.ScheduleItemR\x05items\"0\n" +
+	"\x1aGetAudienceScheduleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"P\n" +
+	"\x1bGetAudienceScheduleResponse\x121\n" +
+	"\x05items\x18\x01 \x03(\v2\x1b.semesterly.v1.ScheduleItemR\x05items\"\x14\n" +
+	"\x12TriggerSyncRequest\"\x15\n" +
+	"\x13TriggerSyncResponse\"\x1b\n" +
+	"\x19StreamSyncProgressRequest\"\xbb\x01\n" +
+	"\x1aStreamSyncProgressResponse\x12!\n" +
+	"\fcurrent_item\x18\x01 \x01(\x05R\vcurrentItem\x12\x1f\n" +
+	"\vtotal_items\x18\x02 \x01(\x05R\n" +
+	"totalItems\x12'\n" +
+	"\x0fcompleted_items\x18\x03 \x01(\x05R\x0ecompletedItems\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x04 \x01(\x01R\n" +
+	"percentage\x12\x10\n" +
+	"\x03eta\x18\x05 \x01(\tR\x03eta*T\n" +
+	"\x04Week\x12\x14\n" +
+	"\x10WEEK_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bWEEK_ALL\x10\x01\x12\x12\n" +
+	"\x0eWEEK_NUMERATOR\x10\x02\x12\x14\n" +
+	"\x10WEEK_DENOMINATOR\x10\x032\xe5\x04\n" +
+	"\x0fScheduleService\x12Q\n" +
+	"\n" +
+	"ListGroups\x12 .semesterly.v1.ListGroupsRequest\x1a!.semesterly.v1.ListGroupsResponse\x12c\n" +
+	"\x10GetGroupSchedule\x12&.semesterly.v1.GetGroupScheduleRequest\x1a'.semesterly.v1.GetGroupScheduleResponse\x12i\n" +
+	"\x12GetTeacherSchedule\x12(.semesterly.v1.GetTeacherScheduleRequest\x1a).semesterly.v1.GetTeacherScheduleResponse\x12l\n" +
+	"\x13GetAudienceSchedule\x12).semesterly.v1.GetAudienceScheduleRequest\x1a*.semesterly.v1.GetAudienceScheduleResponse\x12T\n" +
+	"\vTriggerSync\x12!.semesterly.v1.TriggerSyncRequest\x1a\".semesterly.v1.TriggerSyncResponse\x12k\n" +
+	"\x12StreamSyncProgress\x12(.semesterly.v1.StreamSyncProgressRequest\x1a).semesterly.v1.StreamSyncProgressResponse0\x01BNZLgithub.com/kosttiik/semesterly_backend/internal/pb/semesterlyv1;semesterlyv1b\x06proto3"
+
+var (
+	file_semesterly_v1_schedule_proto_rawDescOnce sync.Once
+	file_semesterly_v1_schedule_proto_rawDescData []byte
+)
+
+func file_semesterly_v1_schedule_proto_rawDescGZIP() []byte {
+	file_semesterly_v1_schedule_proto_rawDescOnce.Do(func() {
+		file_semesterly_v1_schedule_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_semesterly_v1_schedule_proto_rawDesc), len(file_semesterly_v1_schedule_proto_rawDesc)))
+	})
+	return file_semesterly_v1_schedule_proto_rawDescData
+}
+
+var file_semesterly_v1_schedule_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_semesterly_v1_schedule_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_semesterly_v1_schedule_proto_goTypes = []any{
+	(Week)(0),                           // 0: semesterly.v1.Week
+	(*Group)(nil),                       // 1: semesterly.v1.Group
+	(*Teacher)(nil),                     // 2: semesterly.v1.Teacher
+	(*Audience)(nil),                    // 3: semesterly.v1.Audience
+	(*Discipline)(nil),                  // 4: semesterly.v1.Discipline
+	(*ScheduleItem)(nil),                // 5: semesterly.v1.ScheduleItem
+	(*ListGroupsRequest)(nil),           // 6: semesterly.v1.ListGroupsRequest
+	(*ListGroupsResponse)(nil),          // 7: semesterly.v1.ListGroupsResponse
+	(*GetGroupScheduleRequest)(nil),     // 8: semesterly.v1.GetGroupScheduleRequest
+	(*GetGroupScheduleResponse)(nil),    // 9: semesterly.v1.GetGroupScheduleResponse
+	(*GetTeacherScheduleRequest)(nil),   // 10: semesterly.v1.GetTeacherScheduleRequest
+	(*GetTeacherScheduleResponse)(nil),  // 11: semesterly.v1.GetTeacherScheduleResponse
+	(*GetAudienceScheduleRequest)(nil),  // 12: semesterly.v1.GetAudienceScheduleRequest
+	(*GetAudienceScheduleResponse)(nil), // 13: semesterly.v1.GetAudienceScheduleResponse
+	(*TriggerSyncRequest)(nil),          // 14: semesterly.v1.TriggerSyncRequest
+	(*TriggerSyncResponse)(nil),         // 15: semesterly.v1.TriggerSyncResponse
+	(*StreamSyncProgressRequest)(nil),   // 16: semesterly.v1.StreamSyncProgressRequest
+	(*StreamSyncProgressResponse)(nil),  // 17: semesterly.v1.StreamSyncProgressResponse
+}
+var file_semesterly_v1_schedule_proto_depIdxs = []int32{
+	0,  // 0: semesterly.v1.ScheduleItem.week:type_name -> semesterly.v1.Week
+	1,  // 1: semesterly.v1.ScheduleItem.groups:type_name -> semesterly.v1.Group
+	2,  // 2: semesterly.v1.ScheduleItem.teachers:type_name -> semesterly.v1.Teacher
+	3,  // 3: semesterly.v1.ScheduleItem.audiences:type_name -> semesterly.v1.Audience
+	4,  // 4: semesterly.v1.ScheduleItem.disciplines:type_name -> semesterly.v1.Discipline
+	1,  // 5: semesterly.v1.ListGroupsResponse.groups:type_name -> semesterly.v1.Group
+	5,  // 6: semesterly.v1.GetGroupScheduleResponse.items:type_name -> semesterly.v1.ScheduleItem
+	5,  // 7: semesterly.v1.GetTeacherScheduleResponse.items:type_name -> semesterly.v1.ScheduleItem
+	5,  // 8: semesterly.v1.GetAudienceScheduleResponse.items:type_name -> semesterly.v1.ScheduleItem
+	6,  // 9: semesterly.v1.ScheduleService.ListGroups:input_type -> semesterly.v1.ListGroupsRequest
+	8,  // 10: semesterly.v1.ScheduleService.GetGroupSchedule:input_type -> semesterly.v1.GetGroupScheduleRequest
+	10, // 11: semesterly.v1.ScheduleService.GetTeacherSchedule:input_type -> semesterly.v1.GetTeacherScheduleRequest
+	12, // 12: semesterly.v1.ScheduleService.GetAudienceSchedule:input_type -> semesterly.v1.GetAudienceScheduleRequest
+	14, // 13: semesterly.v1.ScheduleService.TriggerSync:input_type -> semesterly.v1.TriggerSyncRequest
+	16, // 14: semesterly.v1.ScheduleService.StreamSyncProgress:input_type -> semesterly.v1.StreamSyncProgressRequest
+	7,  // 15: semesterly.v1.ScheduleService.ListGroups:output_type -> semesterly.v1.ListGroupsResponse
+	9,  // 16: semesterly.v1.ScheduleService.GetGroupSchedule:output_type -> semesterly.v1.GetGroupScheduleResponse
+	11, // 17: semesterly.v1.ScheduleService.GetTeacherSchedule:output_type -> semesterly.v1.GetTeacherScheduleResponse
+	13, // 18: semesterly.v1.ScheduleService.GetAudienceSchedule:output_type -> semesterly.v1.GetAudienceScheduleResponse
+	15, // 19: semesterly.v1.ScheduleService.TriggerSync:output_type -> semesterly.v1.TriggerSyncResponse
+	17, // 20: semesterly.v1.ScheduleService.StreamSyncProgress:output_type -> semesterly.v1.StreamSyncProgressResponse
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_semesterly_v1_schedule_proto_init() }
+func file_semesterly_v1_schedule_proto_init() {
+	if File_semesterly_v1_schedule_proto != nil {
+		return
+	}
+	file_semesterly_v1_schedule_proto_msgTypes[2].OneofWrappers = []any{}
+	file_semesterly_v1_schedule_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_semesterly_v1_schedule_proto_rawDesc), len(file_semesterly_v1_schedule_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_semesterly_v1_schedule_proto_goTypes,
+		DependencyIndexes: file_semesterly_v1_schedule_proto_depIdxs,
+		EnumInfos:         file_semesterly_v1_schedule_proto_enumTypes,
+		MessageInfos:      file_semesterly_v1_schedule_proto_msgTypes,
+	}.Build()
+	File_semesterly_v1_schedule_proto = out.File
+	file_semesterly_v1_schedule_proto_goTypes = nil
+	file_semesterly_v1_schedule_proto_depIdxs = nil
+}
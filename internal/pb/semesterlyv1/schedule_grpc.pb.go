@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: semesterly/v1/schedule.proto
+
+package semesterlyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ScheduleService_ListGroups_FullMethodName          = "/semesterly.v1.ScheduleService/ListGroups"
+	ScheduleService_GetGroupSchedule_FullMethodName    = "/semesterly.v1.ScheduleService/GetGroupSchedule"
+	ScheduleService_GetTeacherSchedule_FullMethodName  = "/semesterly.v1.ScheduleService/GetTeacherSchedule"
+	ScheduleService_GetAudienceSchedule_FullMethodName = "/semesterly.v1.ScheduleService/GetAudienceSchedule"
+	ScheduleService_TriggerSync_FullMethodName         = "/semesterly.v1.ScheduleService/TriggerSync"
+	ScheduleService_StreamSyncProgress_FullMethodName  = "/semesterly.v1.ScheduleService/StreamSyncProgress"
+)
+
+// ScheduleServiceClient is the client API for ScheduleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ScheduleService отдает данные расписания внутренним сервисам
+type ScheduleServiceClient interface {
+	// Список всех групп
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	// Расписание группы по UUID
+	GetGroupSchedule(ctx context.Context, in *GetGroupScheduleRequest, opts ...grpc.CallOption) (*GetGroupScheduleResponse, error)
+	// Расписание преподавателя по UUID
+	GetTeacherSchedule(ctx context.Context, in *GetTeacherScheduleRequest, opts ...grpc.CallOption) (*GetTeacherScheduleResponse, error)
+	// Расписание аудитории по UUID
+	GetAudienceSchedule(ctx context.Context, in *GetAudienceScheduleRequest, opts ...grpc.CallOption) (*GetAudienceScheduleResponse, error)
+	// Запуск синхронизации с lks в фоне, требует метаданные authorization: Bearer <ADMIN_TOKEN>
+	TriggerSync(ctx context.Context, in *TriggerSyncRequest, opts ...grpc.CallOption) (*TriggerSyncResponse, error)
+	// Поток прогресса синхронизации, завершается после окончания синхронизации или сразу, если она не идет
+	StreamSyncProgress(ctx context.Context, in *StreamSyncProgressRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSyncProgressResponse], error)
+}
+
+type scheduleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScheduleServiceClient(cc grpc.ClientConnInterface) ScheduleServiceClient {
+	return &scheduleServiceClient{cc}
+}
+
+func (c *scheduleServiceClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, ScheduleService_ListGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) GetGroupSchedule(ctx context.Context, in *GetGroupScheduleRequest, opts ...grpc.CallOption) (*GetGroupScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGroupScheduleResponse)
+	err := c.cc.Invoke(ctx, ScheduleService_GetGroupSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) GetTeacherSchedule(ctx context.Context, in *GetTeacherScheduleRequest, opts ...grpc.CallOption) (*GetTeacherScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTeacherScheduleResponse)
+	err := c.cc.Invoke(ctx, ScheduleService_GetTeacherSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) GetAudienceSchedule(ctx context.Context, in *GetAudienceScheduleRequest, opts ...grpc.CallOption) (*GetAudienceScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAudienceScheduleResponse)
+	err := c.cc.Invoke(ctx, ScheduleService_GetAudienceSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) TriggerSync(ctx context.Context, in *TriggerSyncRequest, opts ...grpc.CallOption) (*TriggerSyncResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerSyncResponse)
+	err := c.cc.Invoke(ctx, ScheduleService_TriggerSync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) StreamSyncProgress(ctx context.Context, in *StreamSyncProgressRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSyncProgressResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ScheduleService_ServiceDesc.Streams[0], ScheduleService_StreamSyncProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamSyncProgressRequest, StreamSyncProgressResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ScheduleService_StreamSyncProgressClient = grpc.ServerStreamingClient[StreamSyncProgressResponse]
+
+// ScheduleServiceServer is the server API for ScheduleService service.
+// All implementations must embed UnimplementedScheduleServiceServer
+// for forward compatibility.
+//
+// ScheduleService отдает данные расписания внутренним сервисам
+type ScheduleServiceServer interface {
+	// Список всех групп
+	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error)
+	// Расписание группы по UUID
+	GetGroupSchedule(context.Context, *GetGroupScheduleRequest) (*GetGroupScheduleResponse, error)
+	// Расписание преподавателя по UUID
+	GetTeacherSchedule(context.Context, *GetTeacherScheduleRequest) (*GetTeacherScheduleResponse, error)
+	// Расписание аудитории по UUID
+	GetAudienceSchedule(context.Context, *GetAudienceScheduleRequest) (*GetAudienceScheduleResponse, error)
+	// Запуск синхронизации с lks в фоне, требует метаданные authorization: Bearer <ADMIN_TOKEN>
+	TriggerSync(context.Context, *TriggerSyncRequest) (*TriggerSyncResponse, error)
+	// Поток прогресса синхронизации, завершается после окончания синхронизации или сразу, если она не идет
+	StreamSyncProgress(*StreamSyncProgressRequest, grpc.ServerStreamingServer[StreamSyncProgressResponse]) error
+	mustEmbedUnimplementedScheduleServiceServer()
+}
+
+// UnimplementedScheduleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScheduleServiceServer struct{}
+
+func (UnimplementedScheduleServiceServer) ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (UnimplementedScheduleServiceServer) GetGroupSchedule(context.Context, *GetGroupScheduleRequest) (*GetGroupScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetGroupSchedule not implemented")
+}
+func (UnimplementedScheduleServiceServer) GetTeacherSchedule(context.Context, *GetTeacherScheduleRequest) (*GetTeacherScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTeacherSchedule not implemented")
+}
+func (UnimplementedScheduleServiceServer) GetAudienceSchedule(context.Context, *GetAudienceScheduleRequest) (*GetAudienceScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAudienceSchedule not implemented")
+}
+func (UnimplementedScheduleServiceServer) TriggerSync(context.Context, *TriggerSyncRequest) (*TriggerSyncResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TriggerSync not implemented")
+}
+func (UnimplementedScheduleServiceServer) StreamSyncProgress(*StreamSyncProgressRequest, grpc.ServerStreamingServer[StreamSyncProgressResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamSyncProgress not implemented")
+}
+func (UnimplementedScheduleServiceServer) mustEmbedUnimplementedScheduleServiceServer() {}
+func (UnimplementedScheduleServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeScheduleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScheduleServiceServer will
+// result in compilation errors.
+type UnsafeScheduleServiceServer interface {
+	mustEmbedUnimplementedScheduleServiceServer()
+}
+
+func RegisterScheduleServiceServer(s grpc.ServiceRegistrar, srv ScheduleServiceServer) {
+	// If the following call panics, it indicates UnimplementedScheduleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ScheduleService_ServiceDesc, srv)
+}
+
+func _ScheduleService_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScheduleService_ListGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_GetGroupSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).GetGroupSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScheduleService_GetGroupSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).GetGroupSchedule(ctx, req.(*GetGroupScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_GetTeacherSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeacherScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).GetTeacherSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScheduleService_GetTeacherSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).GetTeacherSchedule(ctx, req.(*GetTeacherScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_GetAudienceSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAudienceScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).GetAudienceSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScheduleService_GetAudienceSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).GetAudienceSchedule(ctx, req.(*GetAudienceScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_TriggerSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerSyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).TriggerSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScheduleService_TriggerSync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).TriggerSync(ctx, req.(*TriggerSyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_StreamSyncProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSyncProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScheduleServiceServer).StreamSyncProgress(m, &grpc.GenericServerStream[StreamSyncProgressRequest, StreamSyncProgressResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ScheduleService_StreamSyncProgressServer = grpc.ServerStreamingServer[StreamSyncProgressResponse]
+
+// ScheduleService_ServiceDesc is the grpc.ServiceDesc for ScheduleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScheduleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "semesterly.v1.ScheduleService",
+	HandlerType: (*ScheduleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListGroups",
+			Handler:    _ScheduleService_ListGroups_Handler,
+		},
+		{
+			MethodName: "GetGroupSchedule",
+			Handler:    _ScheduleService_GetGroupSchedule_Handler,
+		},
+		{
+			MethodName: "GetTeacherSchedule",
+			Handler:    _ScheduleService_GetTeacherSchedule_Handler,
+		},
+		{
+			MethodName: "GetAudienceSchedule",
+			Handler:    _ScheduleService_GetAudienceSchedule_Handler,
+		},
+		{
+			MethodName: "TriggerSync",
+			Handler:    _ScheduleService_TriggerSync_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSyncProgress",
+			Handler:       _ScheduleService_StreamSyncProgress_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "semesterly/v1/schedule.proto",
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log"
@@ -14,19 +15,29 @@ import (
 	"gorm.io/gorm"
 
 	_ "github.com/kosttiik/semesterly_backend/docs" // Swagger documentation
+	"github.com/kosttiik/semesterly_backend/internal/cache"
+	"github.com/kosttiik/semesterly_backend/internal/calendar"
+	"github.com/kosttiik/semesterly_backend/internal/compress"
+	"github.com/kosttiik/semesterly_backend/internal/gql"
 	"github.com/kosttiik/semesterly_backend/internal/handlers"
 	"github.com/kosttiik/semesterly_backend/internal/models"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
 type App struct {
-	DB  *gorm.DB
-	Hub *handlers.WebSocketHub
+	DB       *gorm.DB
+	Hub      *handlers.WebSocketHub
+	Calendar *calendar.Calendar
+	Cache    *cache.Cache[[]models.ScheduleItem]
+	GraphQL  *gql.Handler
+	// Обработчики общие для REST и gRPC, чтобы синхронизация не запускалась параллельно
+	Handlers *handlers.App
 }
 
 var (
 	ErrMissingDatabaseConfig = errors.New("missing DATABASE_URL environment variable")
 	ErrInvalidRetryConfig    = errors.New("invalid retry configuration")
+	ErrInvalidCacheConfig    = errors.New("invalid cache configuration")
 )
 
 // Инициализация приложения с подключением к БД
@@ -45,6 +56,31 @@ func New() (*App, error) {
 		return nil, ErrMissingDatabaseConfig
 	}
 
+	// Календарь семестра для вычисления четности недель
+	cal, err := calendar.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// Кеш расписаний групп, преподавателей и аудиторий
+	cacheSize := 1000
+	cacheTTL := 5 * time.Minute
+
+	if cacheSizeStr := os.Getenv("CACHE_SIZE"); cacheSizeStr != "" {
+		cacheSize, err = strconv.Atoi(cacheSizeStr)
+		if err != nil || cacheSize < 0 {
+			return nil, fmt.Errorf("%w: CACHE_SIZE must be a non-negative integer", ErrInvalidCacheConfig)
+		}
+	}
+
+	if cacheTTLStr := os.Getenv("CACHE_TTL"); cacheTTLStr != "" {
+		cacheTTLSeconds, err := strconv.Atoi(cacheTTLStr)
+		if err != nil || cacheTTLSeconds < 0 {
+			return nil, fmt.Errorf("%w: CACHE_TTL must be a non-negative integer in seconds", ErrInvalidCacheConfig)
+		}
+		cacheTTL = time.Duration(cacheTTLSeconds) * time.Second
+	}
+
 	// Получаем настройки повторных попыток подключения
 	maxRetriesStr := os.Getenv("DB_MAX_RETRIES")
 	retryIntervalStr := os.Getenv("DB_RETRY_INTERVAL")
@@ -71,7 +107,6 @@ func New() (*App, error) {
 	}
 
 	var db *gorm.DB
-	var err error
 
 	// Ожидание подключения к БД
 	for i := range maxRetries {
@@ -91,17 +126,58 @@ func New() (*App, error) {
 	log.Println("Connected to the database successfully!")
 
 	// Миграция БД
-	err = db.AutoMigrate(&models.ScheduleItem{}, &models.Exam{})
+	err = db.AutoMigrate(
+		&models.ScheduleItem{},
+		&models.Exam{},
+		&models.Semester{},
+		&models.Holiday{},
+		&models.ExamSession{},
+		&models.DayTransfer{},
+		&models.DataVersion{},
+		&models.StructureNode{},
+		&models.TimeSlot{},
+		&models.PersonalTimetable{},
+		&models.CalendarFeed{},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Семестры, праздники и переносы из базы данных дополняют настройки календаря из окружения
+	if err := (&handlers.App{DB: db, Calendar: cal}).ReloadCalendar(); err != nil {
+		return nil, fmt.Errorf("failed to load academic calendar: %w", err)
+	}
+	if !cal.Configured() {
+		log.Println("No semester configured, date-based features are disabled")
+	}
+
+	// Расписание звонков для данных, загруженных до его появления
+	if _, err := (&handlers.App{DB: db}).RefreshTimeSlots(); err != nil {
+		return nil, fmt.Errorf("failed to derive time slots: %w", err)
+	}
+
+	graphqlHandler, err := gql.NewHandler(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL schema: %w", err)
+	}
+
 	hub := handlers.NewWebSocketHub()
 	go hub.Run()
 
+	scheduleCache := cache.New[[]models.ScheduleItem](cacheSize, cacheTTL)
+
 	return &App{
-		DB:  db,
-		Hub: hub,
+		DB:       db,
+		Hub:      hub,
+		Calendar: cal,
+		Cache:    scheduleCache,
+		GraphQL:  graphqlHandler,
+		Handlers: &handlers.App{
+			DB:       db,
+			Hub:      hub,
+			Calendar: cal,
+			Cache:    scheduleCache,
+		},
 	}, nil
 }
 
@@ -120,14 +196,13 @@ func (a *App) RegisterRoutes(e *echo.Echo) {
 	}))
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE},
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{echo.GET, echo.POST, echo.PUT, echo.DELETE},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-Edit-Token"},
+		ExposeHeaders: []string{"ETag", "Last-Modified"},
 	}))
 
-	h := &handlers.App{
-		DB:  a.DB,
-		Hub: a.Hub,
-	}
+	h := a.Handlers
 
 	// Документация Swagger
 	e.GET("/docs/*", echoSwagger.WrapHandler)
@@ -136,13 +211,100 @@ func (a *App) RegisterRoutes(e *echo.Echo) {
 	e.POST("/api/v1/insert-data", h.InsertDataHandler)
 	e.POST("/api/v1/insert-group-schedule/:uuid", h.InsertGroupScheduleHandler)
 
-	e.GET("/api/v1/get-groups", h.GetGroupsHandler)
-	e.GET("/api/v1/get-data", h.GetDataHandler)
-	e.GET("/api/v1/get-group-schedule/:uuid", h.GetGroupScheduleHandler)
+	// Эндпоинты чтения отдают ETag/Last-Modified и сжимают ответы
+	e.GET("/api/v1/get-groups", h.GetGroupsHandler, compress.Middleware())
+	e.GET("/api/v1/get-data", h.GetDataHandler, compress.Middleware())
+	e.GET("/api/v1/get-group-schedule/:uuid", h.GetGroupScheduleHandler, compress.Middleware())
+
+	e.GET("/api/v1/audiences/free", h.GetFreeAudiencesHandler)
+	e.POST("/api/v1/free-slots", h.FreeSlotsHandler)
+	e.GET("/api/v1/calendar/week", h.GetCalendarWeekHandler)
+	e.GET("/api/v1/slots", h.GetSlotsHandler)
+
+	e.GET("/api/v1/groups/:uuid/occurrences", h.GetGroupOccurrencesHandler)
+	e.GET("/api/v1/teachers/:uuid/occurrences", h.GetTeacherOccurrencesHandler)
+	e.GET("/api/v1/audiences/:uuid/occurrences", h.GetAudienceOccurrencesHandler)
+	e.GET("/api/v1/groups/:uuid/schedule.ics", h.GetGroupICSHandler)
+	e.GET("/api/v1/teachers/:uuid/schedule.ics", h.GetTeacherICSHandler)
+	e.GET("/api/v1/audiences/:uuid/schedule.ics", h.GetAudienceICSHandler)
+	e.POST("/api/v1/feeds", h.CreateCalendarFeedHandler)
+	e.GET("/api/v1/feeds/:token", h.GetCalendarFeedHandler)
+	e.DELETE("/api/v1/feeds/:token", h.DeleteCalendarFeedHandler)
+	e.GET("/api/v1/now", h.GetNowHandler)
+	e.GET("/api/v1/conflicts", h.GetConflictsHandler)
+	e.GET("/api/v1/compare", h.CompareHandler, compress.Middleware())
+	e.GET("/api/v1/cache/stats", h.GetCacheStatsHandler)
+
+	e.GET("/api/v1/graphql", a.GraphQL.ServeHTTP, compress.Middleware())
+	e.POST("/api/v1/graphql", a.GraphQL.ServeHTTP, compress.Middleware())
 
+	e.POST("/api/v1/timetables", h.CreatePersonalTimetableHandler)
+	e.GET("/api/v1/timetables/:id", h.GetPersonalTimetableHandler)
+	e.PUT("/api/v1/timetables/:id", h.UpdatePersonalTimetableHandler)
+	e.DELETE("/api/v1/timetables/:id", h.DeletePersonalTimetableHandler)
+	e.GET("/api/v1/timetables/:id/schedule", h.GetPersonalTimetableScheduleHandler, compress.Middleware())
+	e.GET("/api/v1/timetables/:id/occurrences", h.GetPersonalTimetableOccurrencesHandler)
+
+	e.GET("/api/v1/export/schedule.xlsx", h.ExportXLSXHandler)
+	e.GET("/api/v1/export/schedule.pdf", h.ExportPDFHandler)
+	e.GET("/api/v1/export/schedule.csv", h.ExportCSVHandler)
+	e.GET("/api/v1/groups/:uuid/schedule.pdf", h.GetGroupPDFHandler)
+	e.GET("/api/v1/teachers/:uuid/schedule.pdf", h.GetTeacherPDFHandler)
+	e.GET("/api/v1/audiences/:uuid/schedule.pdf", h.GetAudiencePDFHandler)
 	e.POST("/api/v1/write-schedule", h.WriteScheduleToFileHandler)
+	e.GET("/api/v1/groups/:uuid/schedule.html", h.GetGroupHTMLHandler, compress.Middleware())
+	e.GET("/api/v1/teachers/:uuid/schedule.html", h.GetTeacherHTMLHandler, compress.Middleware())
+	e.GET("/api/v1/audiences/:uuid/schedule.html", h.GetAudienceHTMLHandler, compress.Middleware())
+
+	// Один адрес расписания для всех форматов, формат выбирается по Accept или параметру format
+	e.GET("/api/v1/groups/:uuid/schedule", h.GetGroupScheduleResourceHandler, compress.Middleware())
+	e.GET("/api/v1/teachers/:uuid/schedule", h.GetTeacherScheduleResourceHandler, compress.Middleware())
+	e.GET("/api/v1/audiences/:uuid/schedule", h.GetAudienceScheduleResourceHandler, compress.Middleware())
 
 	e.GET("/ws", h.HandleWebSocket)
+
+	// Зеркало публичного API lks для инструментов, работающих с ним напрямую
+	lks := e.Group("/lks-back/api/v1", compress.Middleware())
+	lks.GET("/structure", h.GetMirrorStructureHandler)
+	lks.GET("/schedules/groups/:uuid/public", h.GetMirrorGroupScheduleHandler)
+	lks.GET("/schedules/exams/:uuid/public", h.GetMirrorGroupExamsHandler)
+
+	// Административные эндпоинты требуют заголовок Authorization: Bearer <ADMIN_TOKEN>
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	if adminToken == "" {
+		log.Println("ADMIN_TOKEN is not set, admin endpoints are disabled")
+	}
+	admin := e.Group("/api/v1/admin", middleware.KeyAuth(func(key string, c echo.Context) (bool, error) {
+		return adminToken != "" && subtle.ConstantTimeCompare([]byte(key), []byte(adminToken)) == 1, nil
+	}))
+
+	admin.GET("/semesters", h.ListSemestersHandler)
+	admin.POST("/semesters", h.CreateSemesterHandler)
+	admin.PUT("/semesters/:id", h.UpdateSemesterHandler)
+	admin.DELETE("/semesters/:id", h.DeleteSemesterHandler)
+
+	admin.GET("/holidays", h.ListHolidaysHandler)
+	admin.POST("/holidays", h.CreateHolidayHandler)
+	admin.PUT("/holidays/:id", h.UpdateHolidayHandler)
+	admin.DELETE("/holidays/:id", h.DeleteHolidayHandler)
+
+	admin.GET("/exam-sessions", h.ListExamSessionsHandler)
+	admin.POST("/exam-sessions", h.CreateExamSessionHandler)
+	admin.PUT("/exam-sessions/:id", h.UpdateExamSessionHandler)
+	admin.DELETE("/exam-sessions/:id", h.DeleteExamSessionHandler)
+
+	admin.GET("/day-transfers", h.ListDayTransfersHandler)
+	admin.POST("/day-transfers", h.CreateDayTransferHandler)
+	admin.PUT("/day-transfers/:id", h.UpdateDayTransferHandler)
+	admin.DELETE("/day-transfers/:id", h.DeleteDayTransferHandler)
+
+	admin.POST("/import", h.ImportScheduleHandler, middleware.BodyLimit("32M"))
+
+	admin.GET("/export-jobs", h.ListExportJobsHandler)
+	admin.POST("/export-jobs", h.CreateExportJobHandler)
+	admin.GET("/export-jobs/:id", h.GetExportJobHandler)
+	admin.GET("/export-jobs/:id/archive", h.DownloadExportJobHandler)
+	admin.DELETE("/export-jobs/:id", h.DeleteExportJobHandler)
 }
 
 // customLogger для форматирования логов с использованием LOG_TIME_FORMAT
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CalendarFeed возвращает подписку на календарь по токену
+func CalendarFeed(db *gorm.DB, token string) (models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := db.Where("token = ?", token).First(&feed).Error
+	return feed, err
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BumpDataVersion увеличивает версию данных группы и глобальную версию
+func BumpDataVersion(db *gorm.DB, groupUUIDs ...string) error {
+	now := time.Now()
+	scopes := append([]string{models.GlobalDataScope}, groupUUIDs...)
+
+	for _, scope := range scopes {
+		err := db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "scope"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"version":    gorm.Expr("data_versions.version + 1"),
+				"updated_at": now,
+			}),
+		}).Create(&models.DataVersion{Scope: scope, Version: 1, UpdatedAt: now}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DataVersionOf возвращает версию данных области; если данные еще не менялись, версия равна нулю
+func DataVersionOf(db *gorm.DB, scope string) (models.DataVersion, error) {
+	var version models.DataVersion
+	err := db.Where("scope = ?", scope).First(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DataVersion{Scope: scope}, nil
+	}
+	return version, err
+}
+
+// BumpAllDataVersions увеличивает версии всех областей данных, например после изменения,
+// затрагивающего ответы всех групп
+func BumpAllDataVersions(db *gorm.DB) error {
+	if err := db.Model(&models.DataVersion{}).Where("1 = 1").Updates(map[string]any{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	// Создает глобальную версию, если данные еще не менялись
+	return BumpDataVersion(db)
+}
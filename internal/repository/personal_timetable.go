@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PersonalTimetable возвращает личное расписание по публичному ID
+func PersonalTimetable(db *gorm.DB, publicID string) (models.PersonalTimetable, error) {
+	var timetable models.PersonalTimetable
+	err := db.Where("public_id = ?", publicID).First(&timetable).Error
+	return timetable, err
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// withAssociations подгружает все связанные сущности элемента расписания
+func withAssociations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Groups").
+		Preload("Teachers").
+		Preload("Audiences").
+		Preload("Disciplines")
+}
+
+// AllSchedule возвращает все элементы расписания
+func AllSchedule(db *gorm.DB) ([]models.ScheduleItem, error) {
+	var scheduleItems []models.ScheduleItem
+	err := withAssociations(db).Find(&scheduleItems).Error
+	return scheduleItems, err
+}
+
+// EachScheduleBatch обходит все элементы расписания пачками по size, не загружая их в память целиком
+func EachScheduleBatch(db *gorm.DB, size int, fn func(batch []models.ScheduleItem) error) error {
+	var batch []models.ScheduleItem
+	return withAssociations(db).Order("id").FindInBatches(&batch, size, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// GroupSchedule возвращает расписание группы по ее UUID
+func GroupSchedule(db *gorm.DB, uuid string) ([]models.ScheduleItem, error) {
+	var scheduleItems []models.ScheduleItem
+
+	err := withAssociations(db).
+		Joins("JOIN schedule_item_groups ON schedule_item_groups.schedule_item_id = schedule_items.id").
+		Joins("JOIN groups ON groups.id = schedule_item_groups.group_id").
+		Where("groups.uuid = ?", uuid).
+		Find(&scheduleItems).Error
+
+	return scheduleItems, err
+}
+
+// TeacherSchedule возвращает расписание преподавателя по его UUID
+func TeacherSchedule(db *gorm.DB, uuid string) ([]models.ScheduleItem, error) {
+	var scheduleItems []models.ScheduleItem
+
+	err := withAssociations(db).
+		Joins("JOIN schedule_item_teachers ON schedule_item_teachers.schedule_item_id = schedule_items.id").
+		Joins("JOIN teachers ON teachers.id = schedule_item_teachers.teacher_id").
+		Where("teachers.uuid = ?", uuid).
+		Find(&scheduleItems).Error
+
+	return scheduleItems, err
+}
+
+// AudienceSchedule возвращает расписание аудитории по ее UUID
+func AudienceSchedule(db *gorm.DB, uuid string) ([]models.ScheduleItem, error) {
+	var scheduleItems []models.ScheduleItem
+
+	err := withAssociations(db).
+		Joins("JOIN schedule_item_audiences ON schedule_item_audiences.schedule_item_id = schedule_items.id").
+		Joins("JOIN audiences ON audiences.id = schedule_item_audiences.audience_id").
+		Where("audiences.uuid = ?", uuid).
+		Find(&scheduleItems).Error
+
+	return scheduleItems, err
+}
+
+// RelatedSchedule возвращает занятия, у которых есть общая группа, преподаватель или аудитория
+// с одним из занятий scheduleItems, включая сами scheduleItems
+func RelatedSchedule(db *gorm.DB, scheduleItems []models.ScheduleItem) ([]models.ScheduleItem, error) {
+	var groupIDs, teacherIDs, audienceIDs []uint
+	for _, item := range scheduleItems {
+		for _, g := range item.Groups {
+			groupIDs = append(groupIDs, g.ID)
+		}
+		for _, t := range item.Teachers {
+			teacherIDs = append(teacherIDs, t.ID)
+		}
+		for _, a := range item.Audiences {
+			audienceIDs = append(audienceIDs, a.ID)
+		}
+	}
+	if len(groupIDs)+len(teacherIDs)+len(audienceIDs) == 0 {
+		return scheduleItems, nil
+	}
+
+	// IN с пустым списком заменяется несуществующим ID
+	orNone := func(ids []uint) []uint {
+		if len(ids) == 0 {
+			return []uint{0}
+		}
+		return ids
+	}
+	var related []models.ScheduleItem
+	err := withAssociations(db).
+		Where("id IN (?)", db.Table("schedule_item_groups").Select("schedule_item_id").Where("group_id IN ?", orNone(groupIDs))).
+		Or("id IN (?)", db.Table("schedule_item_teachers").Select("schedule_item_id").Where("teacher_id IN ?", orNone(teacherIDs))).
+		Or("id IN (?)", db.Table("schedule_item_audiences").Select("schedule_item_id").Where("audience_id IN ?", orNone(audienceIDs))).
+		Find(&related).Error
+	return related, err
+}
+
+// Groups возвращает все группы
+func Groups(db *gorm.DB) ([]models.Group, error) {
+	var groups []models.Group
+	err := db.Find(&groups).Error
+	return groups, err
+}
+
+// Teachers возвращает всех преподавателей
+func Teachers(db *gorm.DB) ([]models.Teacher, error) {
+	var teachers []models.Teacher
+	err := db.Find(&teachers).Error
+	return teachers, err
+}
+
+// Audiences возвращает все аудитории
+func Audiences(db *gorm.DB) ([]models.Audience, error) {
+	var audiences []models.Audience
+	err := db.Find(&audiences).Error
+	return audiences, err
+}
+
+// FindGroup ищет группу по UUID или по названию
+func FindGroup(db *gorm.DB, ref string) (models.Group, error) {
+	var group models.Group
+	err := db.Where("uuid = ? OR name = ?", ref, ref).Order("id").First(&group).Error
+	return group, err
+}
+
+// GroupExams возвращает экзамены группы по ее UUID
+func GroupExams(db *gorm.DB, uuid string) ([]models.Exam, error) {
+	var exams []models.Exam
+
+	err := db.Preload("Disciplines").Preload("Groups").
+		Joins("JOIN exam_groups ON exam_groups.exam_id = exams.id").
+		Joins("JOIN groups ON groups.id = exam_groups.group_id").
+		Where("groups.uuid = ?", uuid).
+		Order("exams.id").
+		Find(&exams).Error
+
+	return exams, err
+}
+
+// AllExams возвращает все экзамены с дисциплинами и группами
+func AllExams(db *gorm.DB) ([]models.Exam, error) {
+	var exams []models.Exam
+	err := db.Preload("Disciplines").Preload("Groups").Order("id").Find(&exams).Error
+	return exams, err
+}
+
+// TeacherExams возвращает экзамены, которые принимает преподаватель с указанным UUID
+func TeacherExams(db *gorm.DB, uuid string) ([]models.Exam, error) {
+	var teacher models.Teacher
+	if err := db.Where("uuid = ?", uuid).First(&teacher).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return []models.Exam{}, nil
+		}
+		return nil, err
+	}
+
+	var exams []models.Exam
+	err := db.Preload("Disciplines").Preload("Groups").
+		Where("last_name = ? AND first_name = ? AND middle_name = ?", teacher.LastName, teacher.FirstName, teacher.MiddleName).
+		Order("id").
+		Find(&exams).Error
+	return exams, err
+}
+
+// AudienceExams возвращает экзамены в аудитории с указанным UUID
+func AudienceExams(db *gorm.DB, uuid string) ([]models.Exam, error) {
+	var exams []models.Exam
+
+	err := db.Preload("Disciplines").Preload("Groups").
+		Where("room IN (?)", db.Model(&models.Audience{}).Select("name").Where("uuid = ?", uuid)).
+		Order("id").
+		Find(&exams).Error
+
+	return exams, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SaveStructure заменяет сохраненное дерево структуры университета
+func SaveStructure(db *gorm.DB, structure models.Structure) error {
+	root := models.StructureNode{
+		UUID: structure.Data.UUID,
+		Abbr: structure.Data.Abbr,
+		Name: structure.Data.Name,
+	}
+	nodes := append([]models.StructureNode{root}, flattenStructure(structure.Data.UUID, structure.Data.Children)...)
+
+	// Узел может встречаться в дереве несколько раз, сохраняем первое вхождение
+	seen := make(map[string]bool, len(nodes))
+	unique := nodes[:0]
+	for _, node := range nodes {
+		if !seen[node.UUID] {
+			seen[node.UUID] = true
+			unique = append(unique, node)
+		}
+	}
+	nodes = unique
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.StructureNode{}).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(nodes, 500).Error
+	})
+}
+
+func flattenStructure(parentUUID string, children []models.Child) []models.StructureNode {
+	var nodes []models.StructureNode
+	for i, child := range children {
+		parent := parentUUID
+		nodes = append(nodes, models.StructureNode{
+			UUID:       child.UUID,
+			ParentUUID: &parent,
+			Position:   i,
+			Abbr:       child.Abbr,
+			Name:       child.Name,
+			NodeType:   child.NodeType,
+			Course:     child.Course,
+			Semester:   child.Semester,
+		})
+		nodes = append(nodes, flattenStructure(child.UUID, child.Children)...)
+	}
+	return nodes
+}
+
+// StructureNodes возвращает все узлы дерева структуры, упорядоченные по позиции
+func StructureNodes(db *gorm.DB) ([]models.StructureNode, error) {
+	var nodes []models.StructureNode
+	err := db.Order("position").Find(&nodes).Error
+	return nodes, err
+}
+
+// LoadStructure восстанавливает дерево структуры университета в формате lks.
+// Если структура еще не загружалась, возвращается пустое дерево
+func LoadStructure(db *gorm.DB) (models.Structure, error) {
+	var structure models.Structure
+
+	nodes, err := StructureNodes(db)
+	if err != nil {
+		return structure, err
+	}
+
+	children := make(map[string][]models.StructureNode)
+	for _, node := range nodes {
+		if node.ParentUUID == nil {
+			structure.Data.UUID = node.UUID
+			structure.Data.Abbr = node.Abbr
+			structure.Data.Name = node.Name
+			continue
+		}
+		children[*node.ParentUUID] = append(children[*node.ParentUUID], node)
+	}
+
+	structure.Data.Children = buildStructure(structure.Data.UUID, children)
+	return structure, nil
+}
+
+func buildStructure(parentUUID string, children map[string][]models.StructureNode) []models.Child {
+	result := make([]models.Child, 0, len(children[parentUUID]))
+	for _, node := range children[parentUUID] {
+		result = append(result, models.Child{
+			Abbr:       node.Abbr,
+			Name:       node.Name,
+			UUID:       node.UUID,
+			NodeType:   node.NodeType,
+			Course:     node.Course,
+			Semester:   node.Semester,
+			ParentUUID: node.ParentUUID,
+			Children:   buildStructure(node.UUID, children),
+		})
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SlotTiming - время пары, встреченное в занятиях, и количество таких занятий
+type SlotTiming struct {
+	Number    int    `json:"-" gorm:"column:time"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	Items     int64  `json:"items"`
+}
+
+// SlotMismatch описывает занятия, время которых расходится с расписанием звонков
+type SlotMismatch struct {
+	Slot     int             `json:"slot"`
+	Expected models.TimeSlot `json:"expected"`
+	Actual   SlotTiming      `json:"actual"`
+}
+
+// TimeSlots возвращает расписание звонков, упорядоченное по номеру пары
+func TimeSlots(db *gorm.DB) ([]models.TimeSlot, error) {
+	var slots []models.TimeSlot
+	err := db.Order("number").Find(&slots).Error
+	return slots, err
+}
+
+// DeriveTimeSlots выводит расписание звонков из занятий: для каждого номера пары выбирается
+// самое частое время. Занятия получают ссылку на пару, а расхождения возвращаются для проверки.
+// updated сообщает, изменились ли ссылки занятий на пары
+func DeriveTimeSlots(db *gorm.DB) (mismatches []SlotMismatch, updated bool, err error) {
+	var timings []SlotTiming
+	if err := db.Model(&models.ScheduleItem{}).
+		Select("time, start_time, end_time, COUNT(*) AS items").
+		Where("start_time <> ''").
+		Group("time, start_time, end_time").
+		Scan(&timings).Error; err != nil {
+		return nil, false, err
+	}
+
+	slots, mismatches := CanonicalSlots(timings)
+	if len(slots) == 0 {
+		return mismatches, false, nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for i := range slots {
+			slots[i].CreatedAt = now
+			slots[i].UpdatedAt = now
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"start_time", "end_time", "updated_at"}),
+		}).Create(&slots).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`UPDATE schedule_items SET slot_id = time_slots.id
+			FROM time_slots
+			WHERE time_slots.number = schedule_items.time
+			AND schedule_items.slot_id IS DISTINCT FROM time_slots.id`)
+		if result.Error != nil {
+			return result.Error
+		}
+		updated = result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// ID пар известны только после сохранения
+	byNumber := make(map[int]models.TimeSlot, len(slots))
+	stored, err := TimeSlots(db)
+	if err != nil {
+		return nil, updated, err
+	}
+	for _, slot := range stored {
+		byNumber[slot.Number] = slot
+	}
+	for i := range mismatches {
+		mismatches[i].Expected = byNumber[mismatches[i].Slot]
+	}
+
+	return mismatches, updated, nil
+}
+
+// CanonicalSlots выбирает для каждого номера пары самое частое время,
+// при равенстве - более раннее. Остальные варианты времени возвращаются как расхождения
+func CanonicalSlots(timings []SlotTiming) ([]models.TimeSlot, []SlotMismatch) {
+	best := make(map[int]SlotTiming)
+	for _, t := range timings {
+		current, ok := best[t.Number]
+		if !ok || t.Items > current.Items ||
+			(t.Items == current.Items && t.StartTime+t.EndTime < current.StartTime+current.EndTime) {
+			best[t.Number] = t
+		}
+	}
+
+	var slots []models.TimeSlot
+	for number, t := range best {
+		slots = append(slots, models.TimeSlot{Number: number, StartTime: t.StartTime, EndTime: t.EndTime})
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Number < slots[j].Number })
+
+	var mismatches []SlotMismatch
+	for _, t := range timings {
+		canonical := best[t.Number]
+		if t.StartTime != canonical.StartTime || t.EndTime != canonical.EndTime {
+			mismatches = append(mismatches, SlotMismatch{
+				Slot:     t.Number,
+				Expected: models.TimeSlot{Number: t.Number, StartTime: canonical.StartTime, EndTime: canonical.EndTime},
+				Actual:   t,
+			})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Slot != mismatches[j].Slot {
+			return mismatches[i].Slot < mismatches[j].Slot
+		}
+		return mismatches[i].Actual.StartTime < mismatches[j].Actual.StartTime
+	})
+
+	return slots, mismatches
+}
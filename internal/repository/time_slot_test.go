@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalSlots(t *testing.T) {
+	slots, mismatches := CanonicalSlots([]SlotTiming{
+		{Number: 2, StartTime: "10:15", EndTime: "11:50", Items: 40},
+		{Number: 1, StartTime: "08:30", EndTime: "10:05", Items: 50},
+		{Number: 1, StartTime: "08:40", EndTime: "10:15", Items: 3},
+		{Number: 3, StartTime: "12:05", EndTime: "13:40", Items: 2},
+		{Number: 3, StartTime: "12:00", EndTime: "13:35", Items: 2},
+	})
+
+	require.Len(t, slots, 3)
+	assert.Equal(t, 1, slots[0].Number)
+	assert.Equal(t, "08:30", slots[0].StartTime)
+	assert.Equal(t, "12:00", slots[2].StartTime) // При равенстве выбирается более раннее время
+
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, 1, mismatches[0].Slot)
+	assert.Equal(t, "08:40", mismatches[0].Actual.StartTime)
+	assert.Equal(t, "08:30", mismatches[0].Expected.StartTime)
+	assert.Equal(t, int64(3), mismatches[0].Actual.Items)
+	assert.Equal(t, "12:05", mismatches[1].Actual.StartTime)
+}
@@ -0,0 +1,110 @@
+package timetable
+
+import (
+	"sort"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+)
+
+const (
+	Days        = 6 // Занятия проходят с понедельника по субботу
+	SlotsPerDay = 7 // Количество пар в учебном дне
+)
+
+// DayNames - названия дней недели, индекс совпадает с ScheduleItem.Day
+var DayNames = [...]string{"", "Понедельник", "Вторник", "Среда", "Четверг", "Пятница", "Суббота"}
+
+// Bell - время начала и окончания пары
+type Bell struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+// DefaultBells - расписание звонков по умолчанию, индекс - номер пары
+var DefaultBells = map[int]Bell{
+	1: {"08:30", "10:05"},
+	2: {"10:15", "11:50"},
+	3: {"12:00", "13:35"},
+	4: {"13:50", "15:25"},
+	5: {"15:40", "17:15"},
+	6: {"17:25", "19:00"},
+	7: {"19:10", "20:45"},
+}
+
+// Grid - расписание в виде сетки дни × пары
+type Grid struct {
+	Days []Day `json:"days"`
+}
+
+// Day - строка сетки с парами одного дня
+type Day struct {
+	Day   int    `json:"day"`
+	Name  string `json:"name"`
+	Slots []Cell `json:"slots"`
+}
+
+// Cell - ячейка сетки. Пустая ячейка содержит пустые списки, а не пропускается
+type Cell struct {
+	Slot        int                   `json:"slot"`
+	StartTime   string                `json:"startTime"`
+	EndTime     string                `json:"endTime"`
+	All         []models.ScheduleItem `json:"all"`         // Каждую неделю
+	Numerator   []models.ScheduleItem `json:"numerator"`   // Только по числителям
+	Denominator []models.ScheduleItem `json:"denominator"` // Только по знаменателям
+}
+
+// Build раскладывает занятия по сетке. Время пар берется из bells,
+// а если для пары его нет - из самих занятий
+func Build(scheduleItems []models.ScheduleItem, bells map[int]Bell) Grid {
+	slots := SlotsPerDay
+	for _, item := range scheduleItems {
+		slots = max(slots, item.Time)
+	}
+
+	times := make(map[int]Bell, slots)
+	for slot, bell := range bells {
+		times[slot] = bell
+	}
+	for _, item := range scheduleItems {
+		if _, ok := times[item.Time]; !ok && item.StartTime != "" {
+			times[item.Time] = Bell{StartTime: item.StartTime, EndTime: item.EndTime}
+		}
+	}
+
+	grid := Grid{Days: make([]Day, Days)}
+	for i := range grid.Days {
+		day := Day{Day: i + 1, Name: DayNames[i+1], Slots: make([]Cell, slots)}
+		for j := range day.Slots {
+			day.Slots[j] = Cell{
+				Slot:        j + 1,
+				StartTime:   times[j+1].StartTime,
+				EndTime:     times[j+1].EndTime,
+				All:         []models.ScheduleItem{},
+				Numerator:   []models.ScheduleItem{},
+				Denominator: []models.ScheduleItem{},
+			}
+		}
+		grid.Days[i] = day
+	}
+
+	// Порядок занятий в ячейке не должен зависеть от порядка выборки из БД
+	sorted := append([]models.ScheduleItem(nil), scheduleItems...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	for _, item := range sorted {
+		if item.Day < 1 || item.Day > Days || item.Time < 1 {
+			continue
+		}
+		cell := &grid.Days[item.Day-1].Slots[item.Time-1]
+		switch item.Week {
+		case models.WeekNumerator:
+			cell.Numerator = append(cell.Numerator, item)
+		case models.WeekDenominator:
+			cell.Denominator = append(cell.Denominator, item)
+		default:
+			cell.All = append(cell.All, item)
+		}
+	}
+
+	return grid
+}
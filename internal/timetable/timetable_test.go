@@ -0,0 +1,35 @@
+package timetable
+
+import (
+	"testing"
+
+	"github.com/kosttiik/semesterly_backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	grid := Build([]models.ScheduleItem{
+		{ID: 3, Day: 1, Time: 2, Week: models.WeekDenominator},
+		{ID: 2, Day: 1, Time: 2, Week: models.WeekNumerator},
+		{ID: 1, Day: 1, Time: 1, Week: models.WeekAll},
+		{ID: 4, Day: 6, Time: 8, Week: models.WeekAll, StartTime: "20:50", EndTime: "22:25"},
+	}, DefaultBells)
+
+	require.Len(t, grid.Days, Days)
+	assert.Equal(t, "Понедельник", grid.Days[0].Name)
+
+	monday := grid.Days[0].Slots
+	require.Len(t, monday, 8) // Восьмая пара из занятий расширяет сетку
+	assert.Equal(t, "08:30", monday[0].StartTime)
+	assert.Len(t, monday[0].All, 1)
+	assert.Len(t, monday[1].Numerator, 1)
+	assert.Len(t, monday[1].Denominator, 1)
+	assert.Empty(t, monday[1].All)
+	assert.NotNil(t, monday[1].All)
+
+	saturday := grid.Days[5].Slots
+	assert.Equal(t, "20:50", saturday[7].StartTime)
+	assert.Equal(t, uint(4), saturday[7].All[0].ID)
+	assert.Equal(t, "20:50", monday[7].StartTime) // Время пары одинаково для всех дней
+}